@@ -65,6 +65,9 @@ type OpenLibertyApplicationSpec struct {
 	// +operator-sdk:csv:customresourcedefinitions:order=11,type=spec,displayName="Manage LTPA",xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
 	ManageLTPA *bool `json:"manageLTPA,omitempty"`
 
+	// +operator-sdk:csv:customresourcedefinitions:order=11,type=spec,displayName="LTPA"
+	LTPA *OpenLibertyApplicationLTPA `json:"ltpa,omitempty"`
+
 	// Enable management of TLS certificates. Defaults to true.
 	// +operator-sdk:csv:customresourcedefinitions:order=12,type=spec,displayName="Manage TLS",xDescriptors="urn:alm:descriptor:com.tectonic.ui:booleanSwitch"
 	ManageTLS *bool `json:"manageTLS,omitempty"`
@@ -451,6 +454,66 @@ type OpenLibertyApplicationServiceability struct {
 	// A convenient field to request the StorageClassName of the persisted storage to use for serviceability.
 	// +kubebuilder:validation:Pattern=.+
 	StorageClassName string `json:"storageClassName,omitempty"`
+
+	// Optional. Dumps the pods of the application before their application container is stopped, when the JVM runs out of memory, or when they stay not ready.
+	AutoDump *OpenLibertyApplicationAutoDump `json:"autoDump,omitempty"`
+
+	// Optional. Periodically deletes old server dumps, performance data, trace archives and logs from the serviceability storage.
+	Retention *OpenLibertyApplicationServiceabilityRetention `json:"retention,omitempty"`
+}
+
+// Defines which files are kept in the serviceability storage. Files that do not meet any of the limits are deleted.
+// The logs of running pods are rotated by Liberty and are never deleted.
+type OpenLibertyApplicationServiceabilityRetention struct {
+	// Optional. Files older than this duration, such as 168h, are deleted.
+	MaxAge *metav1.Duration `json:"maxAge,omitempty"`
+
+	// Optional. The maximum total size of the files in the serviceability storage, such as 5Gi. The oldest files are deleted first.
+	// +kubebuilder:validation:Pattern=^([+-]?[0-9.]+)([eEinumkKMGTP]*[-+]?[0-9]*)$
+	MaxTotalSize string `json:"maxTotalSize,omitempty"`
+
+	// Optional. The number of most recent files of each type kept for each pod.
+	KeepLast *OpenLibertyApplicationServiceabilityKeepLast `json:"keepLast,omitempty"`
+}
+
+// Defines the number of most recent files of each type kept for each pod
+type OpenLibertyApplicationServiceabilityKeepLast struct {
+	// Optional. The number of server dump archives kept in the serverDumps folder.
+	// +kubebuilder:validation:Minimum=0
+	ServerDumps *int32 `json:"serverDumps,omitempty"`
+
+	// Optional. The number of performance data archives kept in the performanceData folder.
+	// +kubebuilder:validation:Minimum=0
+	PerformanceData *int32 `json:"performanceData,omitempty"`
+
+	// Optional. The number of trace archives kept in the traces folder.
+	// +kubebuilder:validation:Minimum=0
+	Traces *int32 `json:"traces,omitempty"`
+
+	// Optional. The number of log files kept in the logs folder of pods that no longer exist.
+	// +kubebuilder:validation:Minimum=0
+	Logs *int32 `json:"logs,omitempty"`
+}
+
+// Defines when the pods of the application are dumped automatically.
+type OpenLibertyApplicationAutoDump struct {
+	// Optional. Dumps the server in a preStop hook before the application container is stopped, such as when it fails its liveness probe or the pod is deleted. Defaults to false.
+	PreStop *bool `json:"preStop,omitempty"`
+
+	// Optional. Configures the JVM to dump the server when it throws a java.lang.OutOfMemoryError, before the server is stopped. Defaults to false.
+	OOMKilled *bool `json:"oomKilled,omitempty"`
+
+	// Optional. The number of seconds a pod must stay not ready, while its application container is running, before it is dumped. By default, pods are not dumped for readiness failures.
+	// +kubebuilder:validation:Minimum=1
+	NotReadySeconds *int32 `json:"notReadySeconds,omitempty"`
+
+	// Optional. List of memory dump types to request: thread, heap, system. Defaults to thread and heap.
+	// +listType=set
+	Include []OpenLibertyDumpInclude `json:"include,omitempty"`
+
+	// Optional. The maximum number of OpenLibertyDumps created for not ready pods of the application in an hour. Defaults to 1.
+	// +kubebuilder:validation:Minimum=1
+	MaxDumpsPerHour *int32 `json:"maxDumpsPerHour,omitempty"`
 }
 
 // Configures the ingress resource.
@@ -512,6 +575,31 @@ type SemeruCompilerStatus struct {
 	ServiceHostname string `json:"serviceHostname,omitempty"`
 }
 
+// Defines the state of the rotation of the password encryption key
+type PasswordEncryptionStatus struct {
+	// Hash of the password encryption key that the operator managed passwords of the application are encoded with
+	KeyHash string `json:"keyHash,omitempty"`
+	// Hash of the password encryption key that was used before the last rotation
+	PreviousKeyHash string `json:"previousKeyHash,omitempty"`
+	// The phase of the last rotation of the password encryption key. The operator managed passwords are re-encoded with the new key,
+	// then the application pods are rolled out with the new key and the re-encoded passwords together.
+	// +kubebuilder:validation:Enum=ReEncoding;RollingPods;Completed
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Password Encryption Key Rotation Phase",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	RotationPhase PasswordEncryptionKeyRotationPhase `json:"rotationPhase,omitempty"`
+}
+
+// Defines the phase of the rotation of the password encryption key
+type PasswordEncryptionKeyRotationPhase string
+
+const (
+	// The operator managed passwords are being re-encoded with the new password encryption key
+	PasswordEncryptionKeyRotationPhaseReEncoding PasswordEncryptionKeyRotationPhase = "ReEncoding"
+	// The application pods are being rolled out with the new password encryption key
+	PasswordEncryptionKeyRotationPhaseRollingPods PasswordEncryptionKeyRotationPhase = "RollingPods"
+	// The application pods use the new password encryption key
+	PasswordEncryptionKeyRotationPhaseCompleted PasswordEncryptionKeyRotationPhase = "Completed"
+)
+
 // Defines the observed state of OpenLibertyApplication.
 type OpenLibertyApplicationStatus struct {
 	// +listType=atomic
@@ -530,11 +618,18 @@ type OpenLibertyApplicationStatus struct {
 
 	SemeruCompiler *SemeruCompilerStatus `json:"semeruCompiler,omitempty"`
 
+	// The state of the rotation of the password encryption key shared with .spec.managePasswordEncryption
+	PasswordEncryption *PasswordEncryptionStatus `json:"passwordEncryption,omitempty"`
+
 	// The generation identifier of this OpenLibertyApplication instance completely reconciled by the Operator.
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 
 	// The reconciliation interval in seconds.
 	ReconcileInterval *int32 `json:"reconcileInterval,omitempty"`
+
+	// The pods of the application whose folders in the serviceability storage are swept by .spec.serviceability.retention
+	// +listType=set
+	ServiceabilityPods []string `json:"serviceabilityPods,omitempty"`
 }
 
 // Defines possible status conditions.
@@ -617,6 +712,14 @@ type OpenLibertyApplicationList struct {
 	Items           []OpenLibertyApplication `json:"items"`
 }
 
+// Configures the LTPA keys of the Liberty server.
+type OpenLibertyApplicationLTPA struct {
+	// Name of a Secret with existing LTPA keys to use instead of the keys generated with .spec.manageLTPA. The Secret must have the LTPA keys file in field ltpa.keys and its password in field password.
+	// The password can be in plain text or encoded with the Liberty securityUtility encode command.
+	// +operator-sdk:csv:customresourcedefinitions:order=1,type=spec,displayName="Keys Secret Reference",xDescriptors="urn:alm:descriptor:io.kubernetes:Secret"
+	KeysSecretRef *string `json:"keysSecretRef,omitempty"`
+}
+
 // Specifies the configuration for Single Sign-On (SSO) providers to authenticate with.
 type OpenLibertyApplicationSSO struct {
 	// +listType=atomic
@@ -861,6 +964,14 @@ func (cr *OpenLibertyApplication) GetManageLTPA() *bool {
 	return cr.Spec.ManageLTPA
 }
 
+// GetLTPAKeysSecretRef returns the name of the Secret with existing LTPA keys
+func (cr *OpenLibertyApplication) GetLTPAKeysSecretRef() *string {
+	if cr.Spec.LTPA != nil {
+		return cr.Spec.LTPA.KeysSecretRef
+	}
+	return nil
+}
+
 // GetManageTLS returns deployment's node and pod affinity settings
 func (cr *OpenLibertyApplication) GetManageTLS() *bool {
 	return cr.Spec.ManageTLS
@@ -1150,6 +1261,72 @@ func (s *OpenLibertyApplicationServiceability) GetVolumeClaimName() string {
 	return s.VolumeClaimName
 }
 
+// GetAutoDump returns the automatic dump policy for Serviceability
+func (s *OpenLibertyApplicationServiceability) GetAutoDump() *OpenLibertyApplicationAutoDump {
+	return s.AutoDump
+}
+
+// GetRetention returns the retention of the files in the serviceability storage
+func (s *OpenLibertyApplicationServiceability) GetRetention() *OpenLibertyApplicationServiceabilityRetention {
+	return s.Retention
+}
+
+// GetKeepLast returns the number of most recent files of a type, i.e. serverDumps, performanceData, traces or logs, kept for each pod, or -1 if all are kept
+func (r *OpenLibertyApplicationServiceabilityRetention) GetKeepLast(fileType string) int {
+	if r.KeepLast == nil {
+		return -1
+	}
+	var keepLast *int32
+	switch fileType {
+	case "serverDumps":
+		keepLast = r.KeepLast.ServerDumps
+	case "performanceData":
+		keepLast = r.KeepLast.PerformanceData
+	case "traces":
+		keepLast = r.KeepLast.Traces
+	case "logs":
+		keepLast = r.KeepLast.Logs
+	}
+	if keepLast == nil {
+		return -1
+	}
+	return int(*keepLast)
+}
+
+// IsPreStopEnabled returns whether the server is dumped before the application container is stopped. Defaults to false.
+func (a *OpenLibertyApplicationAutoDump) IsPreStopEnabled() bool {
+	return a.PreStop != nil && *a.PreStop
+}
+
+// IsOOMKilledEnabled returns whether the JVM dumps the server when it runs out of memory. Defaults to false.
+func (a *OpenLibertyApplicationAutoDump) IsOOMKilledEnabled() bool {
+	return a.OOMKilled != nil && *a.OOMKilled
+}
+
+// GetNotReadyDuration returns how long a pod must stay not ready before it is dumped, or 0 if pods are not dumped for readiness failures
+func (a *OpenLibertyApplicationAutoDump) GetNotReadyDuration() time.Duration {
+	if a.NotReadySeconds == nil {
+		return 0
+	}
+	return time.Duration(*a.NotReadySeconds) * time.Second
+}
+
+// GetInclude returns the memory dump types to request. Defaults to thread and heap.
+func (a *OpenLibertyApplicationAutoDump) GetInclude() []OpenLibertyDumpInclude {
+	if len(a.Include) == 0 {
+		return []OpenLibertyDumpInclude{OpenLibertyDumpIncludeThread, OpenLibertyDumpIncludeHeap}
+	}
+	return a.Include
+}
+
+// GetMaxDumpsPerHour returns the maximum number of OpenLibertyDumps created for not ready pods of the application in an hour. Defaults to 1.
+func (a *OpenLibertyApplicationAutoDump) GetMaxDumpsPerHour() int {
+	if a.MaxDumpsPerHour == nil {
+		return 1
+	}
+	return int(*a.MaxDumpsPerHour)
+}
+
 // GetPort returns service port
 func (s *OpenLibertyApplicationService) GetPort() int32 {
 	if s != nil && s.Port != 0 {
@@ -0,0 +1,4 @@
+package v1
+
+// Hub marks this type as the conversion hub. The v1beta2 version of OpenLibertyDump is converted to and from this version.
+func (*OpenLibertyDump) Hub() {}
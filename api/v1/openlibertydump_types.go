@@ -1,6 +1,9 @@
 package v1
 
 import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -8,11 +11,28 @@ import (
 
 // OpenLibertyDumpSpec defines the desired state of OpenLibertyDump
 type OpenLibertyDumpSpec struct {
-	// The name of the Pod, which must be in the same namespace as the OpenLibertyDump CR.
-	PodName string `json:"podName"`
+	// The name of the Pod, which must be in the same namespace as the OpenLibertyDump CR. Exactly one of podName, applicationRef or podSelector must be set.
+	PodName string `json:"podName,omitempty"`
+	// Optional. The OpenLibertyApplication, in the same namespace as the OpenLibertyDump CR, whose running pods are all dumped.
+	ApplicationRef *corev1.LocalObjectReference `json:"applicationRef,omitempty"`
+	// Optional. Selects the running pods, in the same namespace as the OpenLibertyDump CR, to dump.
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+	// Optional. The maximum number of pods selected by applicationRef or podSelector that are dumped at the same time. Defaults to 3.
+	// +kubebuilder:validation:Minimum=1
+	MaxConcurrentDumps *int `json:"maxConcurrentDumps,omitempty"`
 	// Optional. List of memory dump types to request: thread, heap, system.
 	// +listType=set
 	Include []OpenLibertyDumpInclude `json:"include,omitempty"`
+	// Optional. A cron expression (e.g. "0 */6 * * *") to take server dumps on a recurring schedule. Schedules are evaluated in UTC. When unset, a single dump is taken.
+	Schedule string `json:"schedule,omitempty"`
+	// Optional. The number of most recent scheduled dump archives to keep. Older archives are deleted from the serviceability folder. Only applies when schedule is set.
+	// +kubebuilder:validation:Minimum=1
+	RetentionCount *int32 `json:"retentionCount,omitempty"`
+	// Optional. The number of seconds a dump of a pod can run before it is cancelled and marked as failed. By default, dumps are not timed out.
+	// +kubebuilder:validation:Minimum=1
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+	// Optional. Uploads each dump archive to an S3-compatible object storage bucket.
+	Export *OperationExport `json:"export,omitempty"`
 }
 
 // Defines the possible values for dump types
@@ -36,14 +56,59 @@ type OpenLibertyDumpStatus struct {
 	// Location of the generated dump file
 	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Dump File Path",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
 	DumpFile string `json:"dumpFile,omitempty"`
+	// The name of the Pod the dump file was written to
+	PodName string `json:"podName,omitempty"`
+	// The uploaded dump file, when export is set
+	Export *OperationExportStatus `json:"export,omitempty"`
 	// The generation identifier of this OpenLibertyDump instance completely reconciled by the Operator.
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Time the most recent scheduled dump was started
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+	// Time the next scheduled dump will be started
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+	// Scheduled dumps that were taken, most recent first
+	// +listType=atomic
+	History []DumpHistoryEntry `json:"history,omitempty"`
+	// Results of the most recent dumps of the pods selected by applicationRef or podSelector
+	// +listType=atomic
+	Pods []DumpPodStatus `json:"pods,omitempty"`
 }
 
 type DumpStatusVersions struct {
 	Reconciled string `json:"reconciled,omitempty"`
 }
 
+// Defines the outcome of a scheduled dump
+type DumpHistoryEntry struct {
+	// Time the dump was started
+	StartTime metav1.Time `json:"startTime"`
+	// Location of the generated dump file
+	DumpFile string `json:"dumpFile,omitempty"`
+	// The name of the Pod the dump file was written to
+	PodName string `json:"podName,omitempty"`
+	// Status of the dump, True if the dump completed successfully or Unknown while it is running
+	Status corev1.ConditionStatus `json:"status"`
+	// Message for a dump that failed to complete
+	Message string `json:"message,omitempty"`
+	// The uploaded dump file, when export is set
+	Export *OperationExportStatus `json:"export,omitempty"`
+	// Results of the dumps of the pods selected by applicationRef or podSelector
+	// +listType=atomic
+	Pods []DumpPodStatus `json:"pods,omitempty"`
+}
+
+// Defines the outcome of a dump of one of the pods selected by applicationRef or podSelector
+type DumpPodStatus struct {
+	// The name of the dumped Pod
+	PodName string `json:"podName"`
+	// Location of the generated dump file
+	DumpFile string `json:"dumpFile,omitempty"`
+	// The uploaded dump file, when export is set
+	Export *OperationExportStatus `json:"export,omitempty"`
+	// +listType=atomic
+	Conditions []OperationStatusCondition `json:"conditions,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:storageversion
@@ -51,10 +116,14 @@ type DumpStatusVersions struct {
 // +kubebuilder:printcolumn:name="Started",type="string",JSONPath=".status.conditions[?(@.type=='Started')].status",priority=0,description="Indicates if dump operation has started"
 // +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[?(@.type=='Started')].reason",priority=1,description="Reason for dump operation failing to start"
 // +kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.conditions[?(@.type=='Started')].message",priority=1,description="Message for dump operation failing to start"
+// +kubebuilder:printcolumn:name="Running",type="string",JSONPath=".status.conditions[?(@.type=='Running')].status",priority=0,description="Indicates if dump operation is running"
 // +kubebuilder:printcolumn:name="Completed",type="string",JSONPath=".status.conditions[?(@.type=='Completed')].status",priority=0,description="Indicates if dump operation has completed"
 // +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[?(@.type=='Completed')].reason",priority=1,description="Reason for dump operation failing to complete"
 // +kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.conditions[?(@.type=='Completed')].message",priority=1,description="Message for dump operation failing to complete"
+// +kubebuilder:printcolumn:name="Exported",type="string",JSONPath=".status.conditions[?(@.type=='Exported')].status",priority=1,description="Indicates if the dump file has been exported"
+// +kubebuilder:printcolumn:name="Failed",type="string",JSONPath=".status.conditions[?(@.type=='Failed')].status",priority=1,description="Indicates if dump operation has failed"
 // +kubebuilder:printcolumn:name="Dump file",type="string",JSONPath=".status.dumpFile",priority=0,description="Indicates filename of the server dump"
+// +kubebuilder:printcolumn:name="Last Schedule",type="date",JSONPath=".status.lastScheduleTime",priority=1,description="Time the most recent scheduled dump was started"
 // +operator-sdk:csv:customresourcedefinitions:displayName="OpenLibertyDump"
 // Day-2 operation for generating server dumps
 type OpenLibertyDump struct {
@@ -73,6 +142,20 @@ type OpenLibertyDumpList struct {
 	Items           []OpenLibertyDump `json:"items"`
 }
 
+// GetMaxConcurrentDumps returns the maximum number of pods that are dumped at the same time. Defaults to 3.
+func (cr *OpenLibertyDump) GetMaxConcurrentDumps() int {
+	defaultMaxConcurrentDumps := 3
+	return getIntValueOrDefault(cr.Spec.MaxConcurrentDumps, defaultMaxConcurrentDumps)
+}
+
+// GetTimeout returns the duration a dump of a pod can run before it is cancelled, or 0 if dumps are not timed out
+func (cr *OpenLibertyDump) GetTimeout() time.Duration {
+	if cr.Spec.TimeoutSeconds == nil {
+		return 0
+	}
+	return time.Duration(*cr.Spec.TimeoutSeconds) * time.Second
+}
+
 func init() {
 	SchemeBuilder.Register(&OpenLibertyDump{}, &OpenLibertyDumpList{})
 }
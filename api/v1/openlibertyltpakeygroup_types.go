@@ -0,0 +1,93 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Defines the desired state of OpenLibertyLTPAKeyGroup
+type OpenLibertyLTPAKeyGroupSpec struct {
+	// The namespaces that share the LTPA keys of the group. The OpenLibertyApplications with .spec.manageLTPA in these namespaces use the LTPA keys of the group
+	// instead of generating LTPA keys for their namespace. A namespace can only be a member of one group.
+	// +kubebuilder:validation:MinItems=1
+	// +listType=set
+	// +operator-sdk:csv:customresourcedefinitions:order=1,type=spec,displayName="Namespaces"
+	Namespaces []string `json:"namespaces"`
+}
+
+// Defines the observed state of OpenLibertyLTPAKeyGroup
+type OpenLibertyLTPAKeyGroupStatus struct {
+	// +listType=map
+	// +listMapKey=type
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// The namespaces that are no longer members of the group, from which the LTPA keys of the group could not be removed yet
+	// +listType=set
+	FormerNamespaces []string `json:"formerNamespaces,omitempty"`
+	// The time at which the LTPA keys of the group were last generated or rotated, in seconds since the Unix epoch
+	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Last Rotation",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
+	LastRotation string `json:"lastRotation,omitempty"`
+	// The member namespaces that the LTPA keys of the group are mirrored into
+	// +listType=map
+	// +listMapKey=namespace
+	Namespaces []LTPAKeyGroupNamespaceStatus `json:"namespaces,omitempty"`
+	// The generation identifier of this OpenLibertyLTPAKeyGroup instance completely reconciled by the Operator.
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// Defines the state of the LTPA keys of the group in one of the member namespaces
+type LTPAKeyGroupNamespaceStatus struct {
+	// The name of the namespace
+	Namespace string `json:"namespace"`
+	// The last rotation time of the LTPA keys that the OpenLibertyApplications in the namespace use. The group only rotates its LTPA keys
+	// when all the member namespaces use the current LTPA keys of the group.
+	LastRotation string `json:"lastRotation,omitempty"`
+	// Message explaining why the LTPA keys of the group could not be mirrored into the namespace
+	Message string `json:"message,omitempty"`
+}
+
+// Condition types of OpenLibertyLTPAKeyGroup
+const (
+	// LTPAKeyGroupConditionTypeSynced indicates whether the LTPA keys of the group are mirrored into all the member namespaces
+	LTPAKeyGroupConditionTypeSynced = "Synced"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:resource:path=openlibertyltpakeygroups,scope=Cluster,shortName=olltpakeygroup
+// +kubebuilder:printcolumn:name="Namespaces",type="string",JSONPath=".spec.namespaces",priority=1,description="Namespaces that share the LTPA keys of the group"
+// +kubebuilder:printcolumn:name="Synced",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status",priority=0,description="Indicates if the LTPA keys are mirrored into all the member namespaces"
+// +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].reason",priority=1,description="Reason for the LTPA keys failing to be mirrored"
+// +kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].message",priority=1,description="Message for the LTPA keys failing to be mirrored"
+// +kubebuilder:printcolumn:name="Last Rotation",type="string",JSONPath=".status.lastRotation",priority=1,description="Time at which the LTPA keys were last rotated"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+// +operator-sdk:csv:customresourcedefinitions:displayName="OpenLibertyLTPAKeyGroup"
+// Shares one set of LTPA keys between the OpenLibertyApplications of several namespaces
+type OpenLibertyLTPAKeyGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OpenLibertyLTPAKeyGroupSpec   `json:"spec,omitempty"`
+	Status OpenLibertyLTPAKeyGroupStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// OpenLibertyLTPAKeyGroupList contains a list of OpenLibertyLTPAKeyGroup
+type OpenLibertyLTPAKeyGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []OpenLibertyLTPAKeyGroup `json:"items"`
+}
+
+// GetNamespaceStatus returns the status of the LTPA keys of the group in the namespace, or nil if the namespace has no status
+func (s *OpenLibertyLTPAKeyGroupStatus) GetNamespaceStatus(namespace string) *LTPAKeyGroupNamespaceStatus {
+	for i := range s.Namespaces {
+		if s.Namespaces[i].Namespace == namespace {
+			return &s.Namespaces[i]
+		}
+	}
+	return nil
+}
+
+func init() {
+	SchemeBuilder.Register(&OpenLibertyLTPAKeyGroup{}, &OpenLibertyLTPAKeyGroupList{})
+}
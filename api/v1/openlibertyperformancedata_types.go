@@ -3,6 +3,7 @@ package v1
 import (
 	"time"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -10,8 +11,11 @@ import (
 
 // OpenLibertyPerformanceDataSpec defines the desired state of OpenLibertyPerformanceData
 type OpenLibertyPerformanceDataSpec struct {
-	// The name of the Pod, which must be in the same namespace as the OpenLibertyPerformanceData CR.
-	PodName string `json:"podName"`
+	// The name of the Pod, which must be in the same namespace as the OpenLibertyPerformanceData CR. Exactly one of podName or applicationRef must be set.
+	PodName string `json:"podName,omitempty"`
+
+	// Optional. The OpenLibertyApplication, in the same namespace as the OpenLibertyPerformanceData CR, whose running pods all gather performance data at the same time.
+	ApplicationRef *corev1.LocalObjectReference `json:"applicationRef,omitempty"`
 
 	// The total time, in seconds, for gathering performance data. The minimum value is 10 seconds. The maximum value is 600 seconds (10 minutes). Defaults to 240 seconds (4 minutes).
 	// +kubebuilder:validation:Minimum=10
@@ -21,6 +25,12 @@ type OpenLibertyPerformanceDataSpec struct {
 	// The time, in seconds, between executions. The minimum value is 1 second. Defaults to 30 seconds.
 	// +kubebuilder:validation:Minimum=1
 	Interval *int `json:"interval,omitempty"`
+
+	// The collector that gathers the performance data. linperf gathers OS-level and javacore data with the linperf.sh script. netstat-snapshot gathers snapshots of the network connections with netstat. jfr records the Liberty JVM with Java Flight Recorder for the timespan. Defaults to linperf.
+	Collector string `json:"collector,omitempty"`
+
+	// Optional. Uploads the performance data file to an S3-compatible object storage bucket.
+	Export *OperationExport `json:"export,omitempty"`
 }
 
 // Defines the observed state of OpenLibertyPerformanceData
@@ -31,10 +41,64 @@ type OpenLibertyPerformanceDataStatus struct {
 	// Location of the generated performance data file
 	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Performance Data File Path",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
 	PerformanceDataFile string `json:"performanceDataFile,omitempty"`
+	// The uploaded performance data file, when export is set
+	Export *OperationExportStatus `json:"export,omitempty"`
+	// Summary of the performance data file, when the collector supports it
+	Summary *PerformanceDataSummary `json:"summary,omitempty"`
+	// The pods of the application that gather performance data when applicationRef is set
+	// +listType=atomic
+	Pods []PerformanceDataPodStatus `json:"pods,omitempty"`
+	// The name of the ConfigMap with the summary manifest of the performance data of the pods, when applicationRef is set
+	Manifest string `json:"manifest,omitempty"`
 	// The generation identifier of this OpenLibertyPerformanceData instance completely reconciled by the Operator.
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
 
+// Defines the outcome of the performance data of one of the pods of applicationRef
+type PerformanceDataPodStatus struct {
+	// The name of the Pod
+	PodName string `json:"podName"`
+	// Location of the generated performance data file of the pod
+	PerformanceDataFile string `json:"performanceDataFile,omitempty"`
+	// Time at which the pod started gathering performance data
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// Time at which the performance data file of the pod was generated
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// The uploaded performance data file of the pod, when export is set
+	Export *OperationExportStatus `json:"export,omitempty"`
+	// Summary of the performance data file of the pod, when the collector supports it
+	Summary *PerformanceDataSummary `json:"summary,omitempty"`
+	// +listType=atomic
+	Conditions []OperationStatusCondition `json:"conditions,omitempty"`
+}
+
+// Defines a summary of a performance data file for a first-pass triage
+type PerformanceDataSummary struct {
+	// The system load averages over the last 1, 5 and 15 minutes when the collection started
+	LoadAverage string `json:"loadAverage,omitempty"`
+	// The threads of the Liberty JVM with the highest average CPU usage during the collection
+	// +listType=atomic
+	TopThreads []PerformanceDataThread `json:"topThreads,omitempty"`
+	// The number of javacores in the performance data file
+	Javacores int `json:"javacores,omitempty"`
+	// The number of threads in each state in the last javacore, such as R (runnable), CW (condition wait), P (parked) or B (blocked)
+	ThreadStates map[string]int `json:"threadStates,omitempty"`
+	// The number of global and scavenge garbage collection cycles since the JVM started, as of the last javacore
+	GCCounts map[string]int `json:"gcCounts,omitempty"`
+}
+
+// Defines the CPU usage of a thread during the collection
+type PerformanceDataThread struct {
+	// The ID of the thread
+	ID string `json:"id"`
+	// The name of the thread, as reported by top
+	Name string `json:"name,omitempty"`
+	// The average CPU usage of the thread, in percent
+	AverageCPU string `json:"averageCPU,omitempty"`
+	// The highest CPU usage of the thread, in percent
+	MaxCPU string `json:"maxCPU,omitempty"`
+}
+
 type PerformanceDataStatusVersions struct {
 	Reconciled string `json:"reconciled,omitempty"`
 }
@@ -43,6 +107,8 @@ type PerformanceDataStatusVersions struct {
 // +kubebuilder:subresource:status
 // +kubebuilder:storageversion
 // +kubebuilder:resource:path=openlibertyperformancedata,scope=Namespaced,shortName=olperfdata
+// +kubebuilder:printcolumn:name="Application",type="string",JSONPath=".spec.applicationRef.name",priority=1,description="Application whose pods gather performance data"
+// +kubebuilder:printcolumn:name="Collector",type="string",JSONPath=".spec.collector",priority=1,description="Collector that gathers the performance data"
 // +kubebuilder:printcolumn:name="Started",type="string",JSONPath=".status.conditions[?(@.type=='Started')].status",priority=0,description="Indicates if performance data operation has started"
 // +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[?(@.type=='Started')].reason",priority=1,description="Reason for performance data operation failing to start"
 // +kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.conditions[?(@.type=='Started')].message",priority=1,description="Message for performance data operation failing to start"
@@ -50,6 +116,8 @@ type PerformanceDataStatusVersions struct {
 // +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[?(@.type=='Completed')].reason",priority=1,description="Reason for performance data operation failing to complete"
 // +kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.conditions[?(@.type=='Completed')].message",priority=1,description="Message for performance data operation failing to complete"
 // +kubebuilder:printcolumn:name="Performance Data file",type="string",JSONPath=".status.performanceDataFile",priority=0,description="Indicates filename of the server performance data"
+// +kubebuilder:printcolumn:name="Summarized",type="string",JSONPath=".status.conditions[?(@.type=='Summarized')].status",priority=1,description="Indicates if the performance data file has been summarized"
+// +kubebuilder:printcolumn:name="Exported",type="string",JSONPath=".status.conditions[?(@.type=='Exported')].status",priority=1,description="Indicates if the performance data file has been exported"
 // +operator-sdk:csv:customresourcedefinitions:displayName="OpenLibertyPerformanceData"
 // Day-2 operation for generating server performance data
 type OpenLibertyPerformanceData struct {
@@ -87,6 +155,14 @@ func (cr *OpenLibertyPerformanceData) GetInterval() int {
 	return getIntValueOrDefault(cr.Spec.Interval, defaultInterval)
 }
 
+// GetCollector returns the name of the collector that gathers the performance data. Defaults to linperf.
+func (cr *OpenLibertyPerformanceData) GetCollector() string {
+	if cr.Spec.Collector == "" {
+		return "linperf"
+	}
+	return cr.Spec.Collector
+}
+
 // GetStatus return condition's status
 func (cr *OpenLibertyPerformanceData) GetStatus() *OpenLibertyPerformanceDataStatus {
 	return &cr.Status
@@ -1,6 +1,8 @@
 package v1
 
 import (
+	"time"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -9,8 +11,11 @@ import (
 
 // Defines the desired state of OpenLibertyTrace
 type OpenLibertyTraceSpec struct {
-	// The name of the Pod, which must be in the same namespace as the OpenLibertyTrace CR.
-	PodName string `json:"podName"`
+	// The name of the Pod, which must be in the same namespace as the OpenLibertyTrace CR. Exactly one of podName or applicationRef must be set.
+	PodName string `json:"podName,omitempty"`
+
+	// Optional. The OpenLibertyApplication, in the same namespace as the OpenLibertyTrace CR, whose pods are all traced, including the pods that are scheduled after tracing was enabled.
+	ApplicationRef *corev1.LocalObjectReference `json:"applicationRef,omitempty"`
 
 	// The trace string to be used to selectively enable trace. The default is *=info.
 	TraceSpecification string `json:"traceSpecification"`
@@ -21,8 +26,30 @@ type OpenLibertyTraceSpec struct {
 	// If an enforced maximum file size exists, this setting is used to determine how many of each of the logs files are kept.
 	MaxFiles *int32 `json:"maxFiles,omitempty"`
 
+	// Optional. The format of the trace log: ENHANCED, BASIC or ADVANCED. Defaults to ENHANCED.
+	// +kubebuilder:validation:Enum=ENHANCED;BASIC;ADVANCED
+	TraceFormat string `json:"traceFormat,omitempty"`
+
+	// Optional. The level of the messages written to the console while tracing: INFO, AUDIT, WARNING, ERROR or OFF.
+	// +kubebuilder:validation:Enum=INFO;AUDIT;WARNING;ERROR;OFF
+	ConsoleLogLevel string `json:"consoleLogLevel,omitempty"`
+
+	// Optional. The format of the messages.log file while tracing: simple, json or tbasic.
+	// +kubebuilder:validation:Enum=simple;json;tbasic
+	MessageFormat string `json:"messageFormat,omitempty"`
+
 	// Set to true to stop tracing.
 	Disable *bool `json:"disable,omitempty"`
+
+	// Optional. The number of seconds after which tracing is stopped, counted from the time it was enabled on the pod.
+	// +kubebuilder:validation:Minimum=1
+	DurationSeconds *int32 `json:"durationSeconds,omitempty"`
+
+	// Optional. The time at which tracing is stopped, such as 2024-01-01T18:00:00Z. If durationSeconds is also set, tracing is stopped at the earliest of the two.
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// Optional. Uploads an archive of the trace log directory to an S3-compatible object storage bucket when tracing is stopped.
+	Export *OperationExport `json:"export,omitempty"`
 }
 
 // Defines the observed state of OpenLibertyTrace operation
@@ -31,9 +58,16 @@ type OpenLibertyTraceStatus struct {
 	Conditions       []OperationStatusCondition `json:"conditions,omitempty"`
 	OperatedResource OperatedResource           `json:"operatedResource,omitempty"`
 	Versions         TraceStatusVersions        `json:"versions,omitempty"`
+	// The pods of the application traced when applicationRef is set
+	// +listType=atomic
+	OperatedResources []OperatedResource `json:"operatedResources,omitempty"`
+	// Time at which tracing is stopped, when durationSeconds or expiresAt is set
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
 	// Location of the trace log directory
 	// +operator-sdk:csv:customresourcedefinitions:type=status,displayName="Log Directory",xDescriptors="urn:alm:descriptor:com.tectonic.ui:text"
 	LogDirectory string `json:"logDirectory,omitempty"`
+	// The uploaded archive of the trace log directory, when export is set
+	Export *OperationExportStatus `json:"export,omitempty"`
 	// The generation identifier of this OpenLibertyTrace instance completely reconciled by the Operator.
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
 }
@@ -47,9 +81,13 @@ type TraceStatusVersions struct {
 // +kubebuilder:storageversion
 // +kubebuilder:resource:path=openlibertytraces,scope=Namespaced,shortName=oltrace;oltraces
 // +kubebuilder:printcolumn:name="PodName",type="string",JSONPath=".status.operatedResource.resourceName",priority=0,description="Name of the last operated pod"
+// +kubebuilder:printcolumn:name="Application",type="string",JSONPath=".spec.applicationRef.name",priority=0,description="Name of the application whose pods are traced"
 // +kubebuilder:printcolumn:name="Tracing",type="string",JSONPath=".status.conditions[?(@.type=='Enabled')].status",priority=0,description="Status of the trace condition"
 // +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".status.conditions[?(@.type=='Enabled')].reason",priority=1,description="Reason for the failure of trace condition"
 // +kubebuilder:printcolumn:name="Message",type="string",JSONPath=".status.conditions[?(@.type=='Enabled')].message",priority=1,description="Failure message from trace condition"
+// +kubebuilder:printcolumn:name="Exported",type="string",JSONPath=".status.conditions[?(@.type=='Exported')].status",priority=1,description="Indicates if the trace logs have been exported"
+// +kubebuilder:printcolumn:name="Expires",type="date",JSONPath=".status.expiresAt",priority=1,description="Time at which tracing is stopped"
+// +kubebuilder:printcolumn:name="Expired",type="string",JSONPath=".status.conditions[?(@.type=='Expired')].status",priority=1,description="Indicates if tracing was stopped because its time limit was reached"
 // +operator-sdk:csv:customresourcedefinitions:displayName="OpenLibertyTrace"
 // Day-2 operation for gathering server traces
 type OpenLibertyTrace struct {
@@ -191,6 +229,20 @@ func (s *OpenLibertyTraceStatus) SetOperatedResource(or OperatedResource) {
 	s.OperatedResource = or
 }
 
+// GetExpiry returns the time at which tracing that was enabled at enabledSince is stopped, or nil if tracing does not expire
+func (cr *OpenLibertyTrace) GetExpiry(enabledSince time.Time) *metav1.Time {
+	var expiry *metav1.Time
+	if cr.Spec.DurationSeconds != nil {
+		t := metav1.NewTime(enabledSince.Add(time.Duration(*cr.Spec.DurationSeconds) * time.Second))
+		expiry = &t
+	}
+	if cr.Spec.ExpiresAt != nil && (expiry == nil || cr.Spec.ExpiresAt.Before(expiry)) {
+		t := *cr.Spec.ExpiresAt
+		expiry = &t
+	}
+	return expiry
+}
+
 func (cr *OpenLibertyTrace) Initialize() {
 	if cr.Spec.Disable == nil {
 		disable := false
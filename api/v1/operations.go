@@ -17,10 +17,42 @@ type OperationStatusCondition struct {
 	Type               OperationStatusConditionType `json:"type,omitempty"`
 }
 
+// Defines an S3-compatible object storage bucket that the artifact of a day-2 operation is uploaded to
+type OperationExport struct {
+	// The URL of the S3-compatible object storage service, e.g. https://s3.us-east-1.amazonaws.com or http://minio.minio.svc:9000.
+	// +kubebuilder:validation:Pattern=`^https?://`
+	Endpoint string `json:"endpoint"`
+	// The name of the bucket.
+	Bucket string `json:"bucket"`
+	// Optional. The region used to sign requests. Defaults to us-east-1.
+	Region string `json:"region,omitempty"`
+	// Optional. A prefix added to the object keys. Objects are keyed by their path in the serviceability folder.
+	Prefix string `json:"prefix,omitempty"`
+	// The name of a Secret, in the same namespace as the CR, with the access key in its accessKeyId key and the secret key in its secretAccessKey key.
+	// A temporary security token can be set in the optional sessionToken key.
+	CredentialsSecretRef corev1.LocalObjectReference `json:"credentialsSecretRef"`
+}
+
+// Defines an artifact that was uploaded to object storage
+type OperationExportStatus struct {
+	// URL of the uploaded object
+	URL string `json:"url,omitempty"`
+	// SHA-256 checksum of the uploaded object, hex encoded
+	SHA256 string `json:"sha256,omitempty"`
+	// Time the upload completed
+	ExportTime *metav1.Time `json:"exportTime,omitempty"`
+}
+
 // OperatedResource ...
 type OperatedResource struct {
 	ResourceType string `json:"resourceType,omitempty"`
 	ResourceName string `json:"resourceName,omitempty"`
+	// Location of the trace log directory of the pod, when the pods of an application are traced
+	LogDirectory string `json:"logDirectory,omitempty"`
+	// The uploaded archive of the trace log directory of the pod, when export is set
+	Export *OperationExportStatus `json:"export,omitempty"`
+	// +listType=atomic
+	Conditions []OperationStatusCondition `json:"conditions,omitempty"`
 }
 
 // GetOperatedResourceName get the last operated resource name
@@ -51,10 +83,18 @@ const (
 	OperationStatusConditionTypeEnabled OperationStatusConditionType = "Enabled"
 	// OperationStatusConditionTypeStarted indicates whether operation has been started
 	OperationStatusConditionTypeStarted OperationStatusConditionType = "Started"
+	// OperationStatusConditionTypeRunning indicates whether operation is running
+	OperationStatusConditionTypeRunning OperationStatusConditionType = "Running"
 	// OperationStatusConditionTypeCompleted indicates whether operation has been completed
 	OperationStatusConditionTypeCompleted OperationStatusConditionType = "Completed"
+	// OperationStatusConditionTypeExported indicates whether the artifact of the operation has been exported
+	OperationStatusConditionTypeExported OperationStatusConditionType = "Exported"
+	// OperationStatusConditionTypeSummarized indicates whether the artifact of the operation has been summarized
+	OperationStatusConditionTypeSummarized OperationStatusConditionType = "Summarized"
 	// OperationStatusConditionTypeFailed indicates whether operation has failed
 	OperationStatusConditionTypeFailed OperationStatusConditionType = "Failed"
+	// OperationStatusConditionTypeExpired indicates whether operation was stopped because its time limit was reached
+	OperationStatusConditionTypeExpired OperationStatusConditionType = "Expired"
 )
 
 // GetOperationCondtion returns condition of specific type
@@ -85,3 +125,13 @@ func SetOperationCondtion(c []OperationStatusCondition, oc OperationStatusCondit
 	c = append(c, oc)
 	return c
 }
+
+// RemoveOperationCondtion removes the condition of specific type if present
+func RemoveOperationCondtion(c []OperationStatusCondition, t OperationStatusConditionType) []OperationStatusCondition {
+	for i := range c {
+		if c[i].Type == t {
+			return append(c[:i], c[i+1:]...)
+		}
+	}
+	return c
+}
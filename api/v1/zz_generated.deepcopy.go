@@ -27,9 +27,65 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	"k8s.io/api/autoscaling/v2"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DumpHistoryEntry) DeepCopyInto(out *DumpHistoryEntry) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+	if in.Export != nil {
+		in, out := &in.Export, &out.Export
+		*out = new(OperationExportStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = make([]DumpPodStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DumpHistoryEntry.
+func (in *DumpHistoryEntry) DeepCopy() *DumpHistoryEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(DumpHistoryEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DumpPodStatus) DeepCopyInto(out *DumpPodStatus) {
+	*out = *in
+	if in.Export != nil {
+		in, out := &in.Export, &out.Export
+		*out = new(OperationExportStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]OperationStatusCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DumpPodStatus.
+func (in *DumpPodStatus) DeepCopy() *DumpPodStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DumpPodStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DumpStatusVersions) DeepCopyInto(out *DumpStatusVersions) {
 	*out = *in
@@ -85,6 +141,21 @@ func (in *OAuth2Client) DeepCopy() *OAuth2Client {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LTPAKeyGroupNamespaceStatus) DeepCopyInto(out *LTPAKeyGroupNamespaceStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LTPAKeyGroupNamespaceStatus.
+func (in *LTPAKeyGroupNamespaceStatus) DeepCopy() *LTPAKeyGroupNamespaceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(LTPAKeyGroupNamespaceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OidcClient) DeepCopyInto(out *OidcClient) {
 	*out = *in
@@ -179,6 +250,46 @@ func (in *OpenLibertyApplicationAffinity) DeepCopy() *OpenLibertyApplicationAffi
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenLibertyApplicationAutoDump) DeepCopyInto(out *OpenLibertyApplicationAutoDump) {
+	*out = *in
+	if in.PreStop != nil {
+		in, out := &in.PreStop, &out.PreStop
+		*out = new(bool)
+		**out = **in
+	}
+	if in.OOMKilled != nil {
+		in, out := &in.OOMKilled, &out.OOMKilled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NotReadySeconds != nil {
+		in, out := &in.NotReadySeconds, &out.NotReadySeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Include != nil {
+		in, out := &in.Include, &out.Include
+		*out = make([]OpenLibertyDumpInclude, len(*in))
+		copy(*out, *in)
+	}
+	if in.MaxDumpsPerHour != nil {
+		in, out := &in.MaxDumpsPerHour, &out.MaxDumpsPerHour
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenLibertyApplicationAutoDump.
+func (in *OpenLibertyApplicationAutoDump) DeepCopy() *OpenLibertyApplicationAutoDump {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenLibertyApplicationAutoDump)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpenLibertyApplicationAutoScaling) DeepCopyInto(out *OpenLibertyApplicationAutoScaling) {
 	*out = *in
@@ -295,6 +406,26 @@ func (in *OpenLibertyApplicationDeployment) DeepCopy() *OpenLibertyApplicationDe
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenLibertyApplicationLTPA) DeepCopyInto(out *OpenLibertyApplicationLTPA) {
+	*out = *in
+	if in.KeysSecretRef != nil {
+		in, out := &in.KeysSecretRef, &out.KeysSecretRef
+		*out = new(string)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenLibertyApplicationLTPA.
+func (in *OpenLibertyApplicationLTPA) DeepCopy() *OpenLibertyApplicationLTPA {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenLibertyApplicationLTPA)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpenLibertyApplicationList) DeepCopyInto(out *OpenLibertyApplicationList) {
 	*out = *in
@@ -707,6 +838,16 @@ func (in *OpenLibertyApplicationServiceSessionAffinity) DeepCopy() *OpenLibertyA
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpenLibertyApplicationServiceability) DeepCopyInto(out *OpenLibertyApplicationServiceability) {
 	*out = *in
+	if in.AutoDump != nil {
+		in, out := &in.AutoDump, &out.AutoDump
+		*out = new(OpenLibertyApplicationAutoDump)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(OpenLibertyApplicationServiceabilityRetention)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenLibertyApplicationServiceability.
@@ -719,6 +860,66 @@ func (in *OpenLibertyApplicationServiceability) DeepCopy() *OpenLibertyApplicati
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenLibertyApplicationServiceabilityKeepLast) DeepCopyInto(out *OpenLibertyApplicationServiceabilityKeepLast) {
+	*out = *in
+	if in.ServerDumps != nil {
+		in, out := &in.ServerDumps, &out.ServerDumps
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PerformanceData != nil {
+		in, out := &in.PerformanceData, &out.PerformanceData
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Traces != nil {
+		in, out := &in.Traces, &out.Traces
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Logs != nil {
+		in, out := &in.Logs, &out.Logs
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenLibertyApplicationServiceabilityKeepLast.
+func (in *OpenLibertyApplicationServiceabilityKeepLast) DeepCopy() *OpenLibertyApplicationServiceabilityKeepLast {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenLibertyApplicationServiceabilityKeepLast)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenLibertyApplicationServiceabilityRetention) DeepCopyInto(out *OpenLibertyApplicationServiceabilityRetention) {
+	*out = *in
+	if in.MaxAge != nil {
+		in, out := &in.MaxAge, &out.MaxAge
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.KeepLast != nil {
+		in, out := &in.KeepLast, &out.KeepLast
+		*out = new(OpenLibertyApplicationServiceabilityKeepLast)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenLibertyApplicationServiceabilityRetention.
+func (in *OpenLibertyApplicationServiceabilityRetention) DeepCopy() *OpenLibertyApplicationServiceabilityRetention {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenLibertyApplicationServiceabilityRetention)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpenLibertyApplicationSpec) DeepCopyInto(out *OpenLibertyApplicationSpec) {
 	*out = *in
@@ -762,6 +963,11 @@ func (in *OpenLibertyApplicationSpec) DeepCopyInto(out *OpenLibertyApplicationSp
 		*out = new(bool)
 		**out = **in
 	}
+	if in.LTPA != nil {
+		in, out := &in.LTPA, &out.LTPA
+		*out = new(OpenLibertyApplicationLTPA)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.ManageTLS != nil {
 		in, out := &in.ManageTLS, &out.ManageTLS
 		*out = new(bool)
@@ -820,7 +1026,7 @@ func (in *OpenLibertyApplicationSpec) DeepCopyInto(out *OpenLibertyApplicationSp
 	if in.Serviceability != nil {
 		in, out := &in.Serviceability, &out.Serviceability
 		*out = new(OpenLibertyApplicationServiceability)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.SSO != nil {
 		in, out := &in.SSO, &out.SSO
@@ -1000,11 +1206,21 @@ func (in *OpenLibertyApplicationStatus) DeepCopyInto(out *OpenLibertyApplication
 		*out = new(SemeruCompilerStatus)
 		**out = **in
 	}
+	if in.PasswordEncryption != nil {
+		in, out := &in.PasswordEncryption, &out.PasswordEncryption
+		*out = new(PasswordEncryptionStatus)
+		**out = **in
+	}
 	if in.ReconcileInterval != nil {
 		in, out := &in.ReconcileInterval, &out.ReconcileInterval
 		*out = new(int32)
 		**out = **in
 	}
+	if in.ServiceabilityPods != nil {
+		in, out := &in.ServiceabilityPods, &out.ServiceabilityPods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenLibertyApplicationStatus.
@@ -1130,11 +1346,41 @@ func (in *OpenLibertyDumpList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpenLibertyDumpSpec) DeepCopyInto(out *OpenLibertyDumpSpec) {
 	*out = *in
+	if in.ApplicationRef != nil {
+		in, out := &in.ApplicationRef, &out.ApplicationRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.PodSelector != nil {
+		in, out := &in.PodSelector, &out.PodSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxConcurrentDumps != nil {
+		in, out := &in.MaxConcurrentDumps, &out.MaxConcurrentDumps
+		*out = new(int)
+		**out = **in
+	}
 	if in.Include != nil {
 		in, out := &in.Include, &out.Include
 		*out = make([]OpenLibertyDumpInclude, len(*in))
 		copy(*out, *in)
 	}
+	if in.RetentionCount != nil {
+		in, out := &in.RetentionCount, &out.RetentionCount
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Export != nil {
+		in, out := &in.Export, &out.Export
+		*out = new(OperationExport)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenLibertyDumpSpec.
@@ -1158,6 +1404,33 @@ func (in *OpenLibertyDumpStatus) DeepCopyInto(out *OpenLibertyDumpStatus) {
 		}
 	}
 	out.Versions = in.Versions
+	if in.Export != nil {
+		in, out := &in.Export, &out.Export
+		*out = new(OperationExportStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.LastScheduleTime != nil {
+		in, out := &in.LastScheduleTime, &out.LastScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.NextScheduleTime != nil {
+		in, out := &in.NextScheduleTime, &out.NextScheduleTime
+		*out = (*in).DeepCopy()
+	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]DumpHistoryEntry, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = make([]DumpPodStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenLibertyDumpStatus.
@@ -1170,6 +1443,117 @@ func (in *OpenLibertyDumpStatus) DeepCopy() *OpenLibertyDumpStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenLibertyLTPAKeyGroup) DeepCopyInto(out *OpenLibertyLTPAKeyGroup) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenLibertyLTPAKeyGroup.
+func (in *OpenLibertyLTPAKeyGroup) DeepCopy() *OpenLibertyLTPAKeyGroup {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenLibertyLTPAKeyGroup)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpenLibertyLTPAKeyGroup) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenLibertyLTPAKeyGroupList) DeepCopyInto(out *OpenLibertyLTPAKeyGroupList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]OpenLibertyLTPAKeyGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenLibertyLTPAKeyGroupList.
+func (in *OpenLibertyLTPAKeyGroupList) DeepCopy() *OpenLibertyLTPAKeyGroupList {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenLibertyLTPAKeyGroupList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *OpenLibertyLTPAKeyGroupList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenLibertyLTPAKeyGroupSpec) DeepCopyInto(out *OpenLibertyLTPAKeyGroupSpec) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenLibertyLTPAKeyGroupSpec.
+func (in *OpenLibertyLTPAKeyGroupSpec) DeepCopy() *OpenLibertyLTPAKeyGroupSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenLibertyLTPAKeyGroupSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenLibertyLTPAKeyGroupStatus) DeepCopyInto(out *OpenLibertyLTPAKeyGroupStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]metav1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.FormerNamespaces != nil {
+		in, out := &in.FormerNamespaces, &out.FormerNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]LTPAKeyGroupNamespaceStatus, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenLibertyLTPAKeyGroupStatus.
+func (in *OpenLibertyLTPAKeyGroupStatus) DeepCopy() *OpenLibertyLTPAKeyGroupStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenLibertyLTPAKeyGroupStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpenLibertyPerformanceData) DeepCopyInto(out *OpenLibertyPerformanceData) {
 	*out = *in
@@ -1232,6 +1616,11 @@ func (in *OpenLibertyPerformanceDataList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpenLibertyPerformanceDataSpec) DeepCopyInto(out *OpenLibertyPerformanceDataSpec) {
 	*out = *in
+	if in.ApplicationRef != nil {
+		in, out := &in.ApplicationRef, &out.ApplicationRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
 	if in.Timespan != nil {
 		in, out := &in.Timespan, &out.Timespan
 		*out = new(int)
@@ -1242,6 +1631,11 @@ func (in *OpenLibertyPerformanceDataSpec) DeepCopyInto(out *OpenLibertyPerforman
 		*out = new(int)
 		**out = **in
 	}
+	if in.Export != nil {
+		in, out := &in.Export, &out.Export
+		*out = new(OperationExport)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenLibertyPerformanceDataSpec.
@@ -1265,6 +1659,23 @@ func (in *OpenLibertyPerformanceDataStatus) DeepCopyInto(out *OpenLibertyPerform
 		}
 	}
 	out.Versions = in.Versions
+	if in.Export != nil {
+		in, out := &in.Export, &out.Export
+		*out = new(OperationExportStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Summary != nil {
+		in, out := &in.Summary, &out.Summary
+		*out = new(PerformanceDataSummary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Pods != nil {
+		in, out := &in.Pods, &out.Pods
+		*out = make([]PerformanceDataPodStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenLibertyPerformanceDataStatus.
@@ -1339,6 +1750,11 @@ func (in *OpenLibertyTraceList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OpenLibertyTraceSpec) DeepCopyInto(out *OpenLibertyTraceSpec) {
 	*out = *in
+	if in.ApplicationRef != nil {
+		in, out := &in.ApplicationRef, &out.ApplicationRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
 	if in.MaxFileSize != nil {
 		in, out := &in.MaxFileSize, &out.MaxFileSize
 		*out = new(int32)
@@ -1354,6 +1770,20 @@ func (in *OpenLibertyTraceSpec) DeepCopyInto(out *OpenLibertyTraceSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.DurationSeconds != nil {
+		in, out := &in.DurationSeconds, &out.DurationSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Export != nil {
+		in, out := &in.Export, &out.Export
+		*out = new(OperationExport)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenLibertyTraceSpec.
@@ -1376,8 +1806,24 @@ func (in *OpenLibertyTraceStatus) DeepCopyInto(out *OpenLibertyTraceStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	out.OperatedResource = in.OperatedResource
+	in.OperatedResource.DeepCopyInto(&out.OperatedResource)
 	out.Versions = in.Versions
+	if in.OperatedResources != nil {
+		in, out := &in.OperatedResources, &out.OperatedResources
+		*out = make([]OperatedResource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ExpiresAt != nil {
+		in, out := &in.ExpiresAt, &out.ExpiresAt
+		*out = (*in).DeepCopy()
+	}
+	if in.Export != nil {
+		in, out := &in.Export, &out.Export
+		*out = new(OperationExportStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenLibertyTraceStatus.
@@ -1393,6 +1839,18 @@ func (in *OpenLibertyTraceStatus) DeepCopy() *OpenLibertyTraceStatus {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OperatedResource) DeepCopyInto(out *OperatedResource) {
 	*out = *in
+	if in.Export != nil {
+		in, out := &in.Export, &out.Export
+		*out = new(OperationExportStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]OperationStatusCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatedResource.
@@ -1405,6 +1863,41 @@ func (in *OperatedResource) DeepCopy() *OperatedResource {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperationExport) DeepCopyInto(out *OperationExport) {
+	*out = *in
+	out.CredentialsSecretRef = in.CredentialsSecretRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperationExport.
+func (in *OperationExport) DeepCopy() *OperationExport {
+	if in == nil {
+		return nil
+	}
+	out := new(OperationExport)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperationExportStatus) DeepCopyInto(out *OperationExportStatus) {
+	*out = *in
+	if in.ExportTime != nil {
+		in, out := &in.ExportTime, &out.ExportTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperationExportStatus.
+func (in *OperationExportStatus) DeepCopy() *OperationExportStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OperationExportStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OperationStatusCondition) DeepCopyInto(out *OperationStatusCondition) {
 	*out = *in
@@ -1425,6 +1918,61 @@ func (in *OperationStatusCondition) DeepCopy() *OperationStatusCondition {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PasswordEncryptionStatus) DeepCopyInto(out *PasswordEncryptionStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PasswordEncryptionStatus.
+func (in *PasswordEncryptionStatus) DeepCopy() *PasswordEncryptionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PasswordEncryptionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PerformanceDataPodStatus) DeepCopyInto(out *PerformanceDataPodStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Export != nil {
+		in, out := &in.Export, &out.Export
+		*out = new(OperationExportStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Summary != nil {
+		in, out := &in.Summary, &out.Summary
+		*out = new(PerformanceDataSummary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]OperationStatusCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PerformanceDataPodStatus.
+func (in *PerformanceDataPodStatus) DeepCopy() *PerformanceDataPodStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PerformanceDataPodStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PerformanceDataStatusVersions) DeepCopyInto(out *PerformanceDataStatusVersions) {
 	*out = *in
@@ -1440,6 +1988,55 @@ func (in *PerformanceDataStatusVersions) DeepCopy() *PerformanceDataStatusVersio
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PerformanceDataSummary) DeepCopyInto(out *PerformanceDataSummary) {
+	*out = *in
+	if in.TopThreads != nil {
+		in, out := &in.TopThreads, &out.TopThreads
+		*out = make([]PerformanceDataThread, len(*in))
+		copy(*out, *in)
+	}
+	if in.ThreadStates != nil {
+		in, out := &in.ThreadStates, &out.ThreadStates
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.GCCounts != nil {
+		in, out := &in.GCCounts, &out.GCCounts
+		*out = make(map[string]int, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PerformanceDataSummary.
+func (in *PerformanceDataSummary) DeepCopy() *PerformanceDataSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(PerformanceDataSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PerformanceDataThread) DeepCopyInto(out *PerformanceDataThread) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PerformanceDataThread.
+func (in *PerformanceDataThread) DeepCopy() *PerformanceDataThread {
+	if in == nil {
+		return nil
+	}
+	out := new(PerformanceDataThread)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SemeruCompilerStatus) DeepCopyInto(out *SemeruCompilerStatus) {
 	*out = *in
@@ -0,0 +1,101 @@
+package v1beta2
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+
+	olv1 "github.com/OpenLiberty/open-liberty-operator/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConversionDataAnnotation holds the fields of a v1 resource that v1beta2 does not have, so that they are restored
+// when a resource read as v1beta2 is updated and converted back to v1
+const ConversionDataAnnotation = "openliberty.io/conversion-data"
+
+// Sets the conversion data annotation of the converted resource to the JSON of data, which holds only the fields that
+// the version of the converted resource does not have. The annotation is not set when all of these fields are empty.
+func marshalConversionData(dst metav1.Object, data interface{}) error {
+	dataJSON, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	emptyJSON, err := json.Marshal(reflect.New(reflect.TypeOf(data).Elem()).Interface())
+	if err != nil {
+		return err
+	}
+	annotations := copyAnnotationsWithout(dst.GetAnnotations(), ConversionDataAnnotation)
+	if !bytes.Equal(dataJSON, emptyJSON) {
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[ConversionDataAnnotation] = string(dataJSON)
+	}
+	dst.SetAnnotations(annotations)
+	return nil
+}
+
+// Reads the conversion data annotation of src into data and removes the annotation from the converted resource.
+// Returns false if src has no conversion data.
+func unmarshalConversionData(src, dst metav1.Object, data interface{}) (bool, error) {
+	dataJSON, ok := src.GetAnnotations()[ConversionDataAnnotation]
+	dst.SetAnnotations(copyAnnotationsWithout(dst.GetAnnotations(), ConversionDataAnnotation))
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal([]byte(dataJSON), data); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Returns a copy of the annotations without the key, so that the annotations of the source of a conversion are not
+// modified
+func copyAnnotationsWithout(annotations map[string]string, key string) map[string]string {
+	if annotations == nil {
+		return nil
+	}
+	copied := make(map[string]string, len(annotations))
+	for k, v := range annotations {
+		if k != key {
+			copied[k] = v
+		}
+	}
+	return copied
+}
+
+func convertOperationStatusConditionsTo(src []OperationStatusCondition) []olv1.OperationStatusCondition {
+	if src == nil {
+		return nil
+	}
+	dst := make([]olv1.OperationStatusCondition, len(src))
+	for i := range src {
+		dst[i] = olv1.OperationStatusCondition{
+			LastTransitionTime: src[i].LastTransitionTime,
+			LastUpdateTime:     src[i].LastUpdateTime,
+			Reason:             src[i].Reason,
+			Message:            src[i].Message,
+			Status:             src[i].Status,
+			Type:               olv1.OperationStatusConditionType(src[i].Type),
+		}
+	}
+	return dst
+}
+
+func convertOperationStatusConditionsFrom(src []olv1.OperationStatusCondition) []OperationStatusCondition {
+	if src == nil {
+		return nil
+	}
+	dst := make([]OperationStatusCondition, len(src))
+	for i := range src {
+		dst[i] = OperationStatusCondition{
+			LastTransitionTime: src[i].LastTransitionTime,
+			LastUpdateTime:     src[i].LastUpdateTime,
+			Reason:             src[i].Reason,
+			Message:            src[i].Message,
+			Status:             src[i].Status,
+			Type:               OperationStatusConditionType(src[i].Type),
+		}
+	}
+	return dst
+}
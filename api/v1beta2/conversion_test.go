@@ -0,0 +1,162 @@
+package v1beta2
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	olv1 "github.com/OpenLiberty/open-liberty-operator/api/v1"
+	"k8s.io/apimachinery/pkg/api/apitesting/fuzzer"
+	apiequality "k8s.io/apimachinery/pkg/api/equality"
+	metafuzzer "k8s.io/apimachinery/pkg/apis/meta/fuzzer"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	runtimeserializer "k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/diff"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+	"sigs.k8s.io/randfill"
+)
+
+const fuzzIterations = 500
+
+// The fuzzer is seeded with a constant so that a failing round trip can be reproduced
+const fuzzSeed = 1
+
+// Generates only values that are unchanged by a JSON round trip through the conversion data annotation
+func conversionFuzzerFuncs(_ runtimeserializer.CodecFactory) []interface{} {
+	return []interface{}{
+		func(j *metav1.Time, c randfill.Continue) {
+			*j = metav1.Unix(int64(c.Uint32()), 0)
+		},
+		func(j *metav1.Duration, c randfill.Continue) {
+			j.Duration = time.Duration(c.Int63n(int64(24 * time.Hour)))
+		},
+		// A pointer to a nil slice or map is serialized as null and restored as a nil pointer
+		func(j *olv1.OpenLibertyApplicationTopologySpreadConstraints, c randfill.Continue) {
+			c.FillNoCustom(j)
+			if j.Constraints != nil && *j.Constraints == nil {
+				j.Constraints = nil
+			}
+		},
+		func(j *olv1.OpenLibertyApplicationNetworkPolicy, c randfill.Continue) {
+			c.FillNoCustom(j)
+			if j.FromLabels != nil && *j.FromLabels == nil {
+				j.FromLabels = nil
+			}
+			if j.NamespaceLabels != nil && *j.NamespaceLabels == nil {
+				j.NamespaceLabels = nil
+			}
+		},
+	}
+}
+
+func newConversionFuzzer() *randfill.Filler {
+	funcs := fuzzer.MergeFuzzerFuncs(metafuzzer.Funcs, conversionFuzzerFuncs)
+	return fuzzer.FuzzerFor(funcs, rand.NewSource(fuzzSeed), runtimeserializer.NewCodecFactory(runtime.NewScheme()))
+}
+
+// Fuzzes the spoke, converts it to the hub and back, and checks that the spoke is unchanged
+func testSpokeRoundTrip(t *testing.T, f *randfill.Filler, newSpoke func() conversion.Convertible, newHub func() conversion.Hub) {
+	for i := 0; i < fuzzIterations; i++ {
+		spoke := newSpoke()
+		f.Fill(spoke)
+		original := spoke.DeepCopyObject()
+
+		hub := newHub()
+		if err := spoke.ConvertTo(hub); err != nil {
+			t.Fatalf("ConvertTo failed: %v", err)
+		}
+		restored := newSpoke()
+		if err := restored.ConvertFrom(hub); err != nil {
+			t.Fatalf("ConvertFrom failed: %v", err)
+		}
+		if !apiequality.Semantic.DeepEqual(original, spoke) {
+			t.Fatalf("ConvertTo modified the spoke: %s", diff.Diff(original, spoke))
+		}
+		if !apiequality.Semantic.DeepEqual(original, restored) {
+			t.Fatalf("spoke changed by the round trip through the hub: %s", diff.Diff(original, restored))
+		}
+	}
+}
+
+// Fuzzes the hub, converts it to the spoke and back, and checks that the hub is unchanged
+func testHubRoundTrip(t *testing.T, f *randfill.Filler, newSpoke func() conversion.Convertible, newHub func() conversion.Hub) {
+	for i := 0; i < fuzzIterations; i++ {
+		hub := newHub()
+		f.Fill(hub)
+		original := hub.DeepCopyObject()
+
+		spoke := newSpoke()
+		if err := spoke.ConvertFrom(hub); err != nil {
+			t.Fatalf("ConvertFrom failed: %v", err)
+		}
+		restored := newHub()
+		if err := spoke.ConvertTo(restored); err != nil {
+			t.Fatalf("ConvertTo failed: %v", err)
+		}
+		if !apiequality.Semantic.DeepEqual(original, hub) {
+			t.Fatalf("ConvertFrom modified the hub: %s", diff.Diff(original, hub))
+		}
+		if !apiequality.Semantic.DeepEqual(original, restored) {
+			t.Fatalf("hub changed by the round trip through the spoke: %s", diff.Diff(original, restored))
+		}
+	}
+}
+
+func TestOpenLibertyApplicationConversion(t *testing.T) {
+	f := newConversionFuzzer()
+	newSpoke := func() conversion.Convertible { return &OpenLibertyApplication{} }
+	newHub := func() conversion.Hub { return &olv1.OpenLibertyApplication{} }
+	testSpokeRoundTrip(t, f, newSpoke, newHub)
+	testHubRoundTrip(t, f, newSpoke, newHub)
+}
+
+func TestOpenLibertyDumpConversion(t *testing.T) {
+	f := newConversionFuzzer()
+	newSpoke := func() conversion.Convertible { return &OpenLibertyDump{} }
+	newHub := func() conversion.Hub { return &olv1.OpenLibertyDump{} }
+	testSpokeRoundTrip(t, f, newSpoke, newHub)
+	testHubRoundTrip(t, f, newSpoke, newHub)
+}
+
+func TestOpenLibertyTraceConversion(t *testing.T) {
+	f := newConversionFuzzer()
+	newSpoke := func() conversion.Convertible { return &OpenLibertyTrace{} }
+	newHub := func() conversion.Hub { return &olv1.OpenLibertyTrace{} }
+	testSpokeRoundTrip(t, f, newSpoke, newHub)
+	testHubRoundTrip(t, f, newSpoke, newHub)
+}
+
+// A v1beta2 resource that was never converted from v1 has no conversion data, so the v1-only fields are left empty
+func TestConversionWithoutConversionData(t *testing.T) {
+	spoke := &OpenLibertyDump{
+		ObjectMeta: metav1.ObjectMeta{Name: "dump", Annotations: map[string]string{"a": "b"}},
+		Spec:       OpenLibertyDumpSpec{PodName: "pod"},
+	}
+	hub := &olv1.OpenLibertyDump{}
+	if err := spoke.ConvertTo(hub); err != nil {
+		t.Fatal(err)
+	}
+	if hub.Spec.PodName != "pod" || hub.Spec.ApplicationRef != nil || hub.Spec.Schedule != "" {
+		t.Fatalf("unexpected spec: %+v", hub.Spec)
+	}
+
+	// The conversion data annotation is set only when the v1 resource has fields that v1beta2 does not have
+	back := &OpenLibertyDump{}
+	if err := back.ConvertFrom(hub); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := back.Annotations[ConversionDataAnnotation]; ok {
+		t.Fatalf("unexpected conversion data annotation: %v", back.Annotations)
+	}
+	hub.Spec.Schedule = "*/5 * * * *"
+	if err := back.ConvertFrom(hub); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := back.Annotations[ConversionDataAnnotation]; !ok {
+		t.Fatalf("expected conversion data annotation: %v", back.Annotations)
+	}
+	if _, ok := hub.Annotations[ConversionDataAnnotation]; ok {
+		t.Fatal("ConvertFrom modified the annotations of the hub")
+	}
+}
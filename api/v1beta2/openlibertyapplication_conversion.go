@@ -0,0 +1,349 @@
+package v1beta2
+
+import (
+	olv1 "github.com/OpenLiberty/open-liberty-operator/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// The fields of a v1 OpenLibertyApplication that v1beta2 does not have
+type openLibertyApplicationConversionData struct {
+	Spec   olv1.OpenLibertyApplicationSpec   `json:"spec"`
+	Status olv1.OpenLibertyApplicationStatus `json:"status"`
+}
+
+// ConvertTo converts this OpenLibertyApplication to the hub version (v1)
+func (src *OpenLibertyApplication) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*olv1.OpenLibertyApplication)
+	dst.ObjectMeta = src.ObjectMeta
+
+	dst.Spec.ApplicationImage = src.Spec.ApplicationImage
+	dst.Spec.ApplicationName = src.Spec.ApplicationName
+	dst.Spec.ApplicationVersion = src.Spec.ApplicationVersion
+	dst.Spec.PullPolicy = src.Spec.PullPolicy
+	dst.Spec.PullSecret = src.Spec.PullSecret
+	dst.Spec.ServiceAccountName = src.Spec.ServiceAccountName
+	dst.Spec.CreateKnativeService = src.Spec.CreateKnativeService
+	dst.Spec.Expose = src.Spec.Expose
+	dst.Spec.Replicas = src.Spec.Replicas
+	dst.Spec.Autoscaling = nil
+	if src.Spec.Autoscaling != nil {
+		dst.Spec.Autoscaling = &olv1.OpenLibertyApplicationAutoScaling{
+			MaxReplicas:                    src.Spec.Autoscaling.MaxReplicas,
+			MinReplicas:                    src.Spec.Autoscaling.MinReplicas,
+			TargetCPUUtilizationPercentage: src.Spec.Autoscaling.TargetCPUUtilizationPercentage,
+		}
+	}
+	dst.Spec.Resources = src.Spec.Resources
+	dst.Spec.Probes = nil
+	if src.Spec.Probes != nil {
+		dst.Spec.Probes = &olv1.OpenLibertyApplicationProbesConfig{
+			OpenLibertyApplicationProbes: olv1.OpenLibertyApplicationProbes(*src.Spec.Probes),
+		}
+	}
+	dst.Spec.Deployment = (*olv1.OpenLibertyApplicationDeployment)(src.Spec.Deployment)
+	dst.Spec.StatefulSet = nil
+	if src.Spec.StatefulSet != nil {
+		dst.Spec.StatefulSet = &olv1.OpenLibertyApplicationStatefulSet{
+			UpdateStrategy: src.Spec.StatefulSet.UpdateStrategy,
+			Annotations:    src.Spec.StatefulSet.Annotations,
+		}
+		if storage := src.Spec.StatefulSet.Storage; storage != nil {
+			dst.Spec.StatefulSet.Storage = &olv1.OpenLibertyApplicationStorage{
+				Size:                storage.Size,
+				MountPath:           storage.MountPath,
+				VolumeClaimTemplate: storage.VolumeClaimTemplate,
+			}
+		}
+	}
+	dst.Spec.Service = nil
+	if service := src.Spec.Service; service != nil {
+		dst.Spec.Service = &olv1.OpenLibertyApplicationService{
+			Port:                 service.Port,
+			Type:                 service.Type,
+			NodePort:             service.NodePort,
+			PortName:             service.PortName,
+			Annotations:          service.Annotations,
+			TargetPort:           service.TargetPort,
+			CertificateSecretRef: service.CertificateSecretRef,
+			Ports:                service.Ports,
+			Bindable:             service.Bindable,
+		}
+	}
+	dst.Spec.Route = (*olv1.OpenLibertyApplicationRoute)(src.Spec.Route)
+	dst.Spec.Serviceability = nil
+	if serviceability := src.Spec.Serviceability; serviceability != nil {
+		dst.Spec.Serviceability = &olv1.OpenLibertyApplicationServiceability{
+			Size:             serviceability.Size,
+			VolumeClaimName:  serviceability.VolumeClaimName,
+			StorageClassName: serviceability.StorageClassName,
+		}
+	}
+	dst.Spec.SSO = nil
+	if sso := src.Spec.SSO; sso != nil {
+		dst.Spec.SSO = &olv1.OpenLibertyApplicationSSO{
+			Github:                  (*olv1.GithubLogin)(sso.Github),
+			RedirectToRPHostAndPort: sso.RedirectToRPHostAndPort,
+			MapToUserRegistry:       sso.MapToUserRegistry,
+		}
+		if sso.OIDC != nil {
+			dst.Spec.SSO.OIDC = make([]olv1.OidcClient, len(sso.OIDC))
+			for i := range sso.OIDC {
+				dst.Spec.SSO.OIDC[i] = olv1.OidcClient(sso.OIDC[i])
+			}
+		}
+		if sso.Oauth2 != nil {
+			dst.Spec.SSO.Oauth2 = make([]olv1.OAuth2Client, len(sso.Oauth2))
+			for i := range sso.Oauth2 {
+				dst.Spec.SSO.Oauth2[i] = olv1.OAuth2Client(sso.Oauth2[i])
+			}
+		}
+	}
+	dst.Spec.Monitoring = (*olv1.OpenLibertyApplicationMonitoring)(src.Spec.Monitoring)
+	dst.Spec.Env = src.Spec.Env
+	dst.Spec.EnvFrom = src.Spec.EnvFrom
+	dst.Spec.Volumes = src.Spec.Volumes
+	dst.Spec.VolumeMounts = src.Spec.VolumeMounts
+	dst.Spec.InitContainers = src.Spec.InitContainers
+	dst.Spec.SidecarContainers = src.Spec.SidecarContainers
+	dst.Spec.Affinity = (*olv1.OpenLibertyApplicationAffinity)(src.Spec.Affinity)
+
+	dst.Status.Conditions = nil
+	if src.Status.Conditions != nil {
+		dst.Status.Conditions = make([]olv1.StatusCondition, len(src.Status.Conditions))
+		for i, condition := range src.Status.Conditions {
+			dst.Status.Conditions[i] = olv1.StatusCondition{
+				LastTransitionTime: condition.LastTransitionTime,
+				Reason:             condition.Reason,
+				Message:            condition.Message,
+				Status:             condition.Status,
+				Type:               olv1.StatusConditionType(condition.Type),
+			}
+		}
+	}
+	dst.Status.RouteAvailable = src.Status.RouteAvailable
+	dst.Status.ImageReference = src.Status.ImageReference
+	dst.Status.Binding = src.Status.Binding
+
+	restored := &openLibertyApplicationConversionData{}
+	if ok, err := unmarshalConversionData(src, dst, restored); err != nil || !ok {
+		return err
+	}
+	dst.Spec.ServiceAccount = restored.Spec.ServiceAccount
+	dst.Spec.ManagePasswordEncryption = restored.Spec.ManagePasswordEncryption
+	dst.Spec.ManageLTPA = restored.Spec.ManageLTPA
+	dst.Spec.LTPA = restored.Spec.LTPA
+	dst.Spec.ManageTLS = restored.Spec.ManageTLS
+	if dst.Spec.Autoscaling != nil && restored.Spec.Autoscaling != nil {
+		dst.Spec.Autoscaling.TargetMemoryUtilizationPercentage = restored.Spec.Autoscaling.TargetMemoryUtilizationPercentage
+		dst.Spec.Autoscaling.Metrics = restored.Spec.Autoscaling.Metrics
+		dst.Spec.Autoscaling.Behavior = restored.Spec.Autoscaling.Behavior
+	}
+	if dst.Spec.Probes != nil && restored.Spec.Probes != nil {
+		dst.Spec.Probes.EnableFileBased = restored.Spec.Probes.EnableFileBased
+		dst.Spec.Probes.CheckInterval = restored.Spec.Probes.CheckInterval
+		dst.Spec.Probes.StartupCheckInterval = restored.Spec.Probes.StartupCheckInterval
+	}
+	if dst.Spec.StatefulSet != nil && dst.Spec.StatefulSet.Storage != nil &&
+		restored.Spec.StatefulSet != nil && restored.Spec.StatefulSet.Storage != nil {
+		dst.Spec.StatefulSet.Storage.ClassName = restored.Spec.StatefulSet.Storage.ClassName
+	}
+	if dst.Spec.Service != nil && restored.Spec.Service != nil {
+		dst.Spec.Service.Certificate = restored.Spec.Service.Certificate
+		dst.Spec.Service.SessionAffinity = restored.Spec.Service.SessionAffinity
+		dst.Spec.Service.DisableTopologyRouting = restored.Spec.Service.DisableTopologyRouting
+	}
+	dst.Spec.SemeruCloudCompiler = restored.Spec.SemeruCloudCompiler
+	dst.Spec.NetworkPolicy = restored.Spec.NetworkPolicy
+	if dst.Spec.Serviceability != nil && restored.Spec.Serviceability != nil {
+		dst.Spec.Serviceability.AutoDump = restored.Spec.Serviceability.AutoDump
+		dst.Spec.Serviceability.Retention = restored.Spec.Serviceability.Retention
+	}
+	dst.Spec.SecurityContext = restored.Spec.SecurityContext
+	dst.Spec.TopologySpreadConstraints = restored.Spec.TopologySpreadConstraints
+	dst.Spec.DisableServiceLinks = restored.Spec.DisableServiceLinks
+	dst.Spec.Tolerations = restored.Spec.Tolerations
+	dst.Spec.DNS = restored.Spec.DNS
+	dst.Spec.HostAliases = restored.Spec.HostAliases
+	dst.Spec.PriorityClassName = restored.Spec.PriorityClassName
+	dst.Status.Endpoints = restored.Status.Endpoints
+	dst.Status.PulledImageReference = restored.Status.PulledImageReference
+	dst.Status.Versions = restored.Status.Versions
+	dst.Status.References = restored.Status.References
+	dst.Status.SemeruCompiler = restored.Status.SemeruCompiler
+	dst.Status.PasswordEncryption = restored.Status.PasswordEncryption
+	dst.Status.ObservedGeneration = restored.Status.ObservedGeneration
+	dst.Status.ReconcileInterval = restored.Status.ReconcileInterval
+	dst.Status.ServiceabilityPods = restored.Status.ServiceabilityPods
+	return nil
+}
+
+// ConvertFrom converts the hub version (v1) of an OpenLibertyApplication to this version
+func (dst *OpenLibertyApplication) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*olv1.OpenLibertyApplication)
+	dst.ObjectMeta = src.ObjectMeta
+	data := &openLibertyApplicationConversionData{}
+
+	dst.Spec.ApplicationImage = src.Spec.ApplicationImage
+	dst.Spec.ApplicationName = src.Spec.ApplicationName
+	dst.Spec.ApplicationVersion = src.Spec.ApplicationVersion
+	dst.Spec.PullPolicy = src.Spec.PullPolicy
+	dst.Spec.PullSecret = src.Spec.PullSecret
+	dst.Spec.ServiceAccountName = src.Spec.ServiceAccountName
+	dst.Spec.CreateKnativeService = src.Spec.CreateKnativeService
+	dst.Spec.Expose = src.Spec.Expose
+	dst.Spec.Replicas = src.Spec.Replicas
+	dst.Spec.Autoscaling = nil
+	if autoscaling := src.Spec.Autoscaling; autoscaling != nil {
+		dst.Spec.Autoscaling = &OpenLibertyApplicationAutoScaling{
+			MaxReplicas:                    autoscaling.MaxReplicas,
+			MinReplicas:                    autoscaling.MinReplicas,
+			TargetCPUUtilizationPercentage: autoscaling.TargetCPUUtilizationPercentage,
+		}
+		if autoscaling.TargetMemoryUtilizationPercentage != nil || len(autoscaling.Metrics) > 0 || autoscaling.Behavior != nil {
+			data.Spec.Autoscaling = &olv1.OpenLibertyApplicationAutoScaling{
+				TargetMemoryUtilizationPercentage: autoscaling.TargetMemoryUtilizationPercentage,
+				Metrics:                           autoscaling.Metrics,
+				Behavior:                          autoscaling.Behavior,
+			}
+		}
+	}
+	dst.Spec.Resources = src.Spec.Resources
+	dst.Spec.Probes = nil
+	if probes := src.Spec.Probes; probes != nil {
+		p := OpenLibertyApplicationProbes(probes.OpenLibertyApplicationProbes)
+		dst.Spec.Probes = &p
+		if probes.EnableFileBased != nil || probes.CheckInterval != nil || probes.StartupCheckInterval != nil {
+			data.Spec.Probes = &olv1.OpenLibertyApplicationProbesConfig{
+				EnableFileBased:      probes.EnableFileBased,
+				CheckInterval:        probes.CheckInterval,
+				StartupCheckInterval: probes.StartupCheckInterval,
+			}
+		}
+	}
+	dst.Spec.Deployment = (*OpenLibertyApplicationDeployment)(src.Spec.Deployment)
+	dst.Spec.StatefulSet = nil
+	if statefulSet := src.Spec.StatefulSet; statefulSet != nil {
+		dst.Spec.StatefulSet = &OpenLibertyApplicationStatefulSet{
+			UpdateStrategy: statefulSet.UpdateStrategy,
+			Annotations:    statefulSet.Annotations,
+		}
+		if storage := statefulSet.Storage; storage != nil {
+			dst.Spec.StatefulSet.Storage = &OpenLibertyApplicationStorage{
+				Size:                storage.Size,
+				MountPath:           storage.MountPath,
+				VolumeClaimTemplate: storage.VolumeClaimTemplate,
+			}
+			if storage.ClassName != "" {
+				data.Spec.StatefulSet = &olv1.OpenLibertyApplicationStatefulSet{
+					Storage: &olv1.OpenLibertyApplicationStorage{ClassName: storage.ClassName},
+				}
+			}
+		}
+	}
+	dst.Spec.Service = nil
+	if service := src.Spec.Service; service != nil {
+		dst.Spec.Service = &OpenLibertyApplicationService{
+			Port:                 service.Port,
+			Type:                 service.Type,
+			NodePort:             service.NodePort,
+			PortName:             service.PortName,
+			Annotations:          service.Annotations,
+			TargetPort:           service.TargetPort,
+			CertificateSecretRef: service.CertificateSecretRef,
+			Ports:                service.Ports,
+			Bindable:             service.Bindable,
+		}
+		if service.Certificate != nil || service.SessionAffinity != nil || service.DisableTopologyRouting != nil {
+			data.Spec.Service = &olv1.OpenLibertyApplicationService{
+				Certificate:            service.Certificate,
+				SessionAffinity:        service.SessionAffinity,
+				DisableTopologyRouting: service.DisableTopologyRouting,
+			}
+		}
+	}
+	dst.Spec.Route = (*OpenLibertyApplicationRoute)(src.Spec.Route)
+	dst.Spec.Serviceability = nil
+	if serviceability := src.Spec.Serviceability; serviceability != nil {
+		dst.Spec.Serviceability = &OpenLibertyApplicationServiceability{
+			Size:             serviceability.Size,
+			VolumeClaimName:  serviceability.VolumeClaimName,
+			StorageClassName: serviceability.StorageClassName,
+		}
+		if serviceability.AutoDump != nil || serviceability.Retention != nil {
+			data.Spec.Serviceability = &olv1.OpenLibertyApplicationServiceability{
+				AutoDump:  serviceability.AutoDump,
+				Retention: serviceability.Retention,
+			}
+		}
+	}
+	dst.Spec.SSO = nil
+	if sso := src.Spec.SSO; sso != nil {
+		dst.Spec.SSO = &OpenLibertyApplicationSSO{
+			Github:                  (*GithubLogin)(sso.Github),
+			RedirectToRPHostAndPort: sso.RedirectToRPHostAndPort,
+			MapToUserRegistry:       sso.MapToUserRegistry,
+		}
+		if sso.OIDC != nil {
+			dst.Spec.SSO.OIDC = make([]OidcClient, len(sso.OIDC))
+			for i := range sso.OIDC {
+				dst.Spec.SSO.OIDC[i] = OidcClient(sso.OIDC[i])
+			}
+		}
+		if sso.Oauth2 != nil {
+			dst.Spec.SSO.Oauth2 = make([]OAuth2Client, len(sso.Oauth2))
+			for i := range sso.Oauth2 {
+				dst.Spec.SSO.Oauth2[i] = OAuth2Client(sso.Oauth2[i])
+			}
+		}
+	}
+	dst.Spec.Monitoring = (*OpenLibertyApplicationMonitoring)(src.Spec.Monitoring)
+	dst.Spec.Env = src.Spec.Env
+	dst.Spec.EnvFrom = src.Spec.EnvFrom
+	dst.Spec.Volumes = src.Spec.Volumes
+	dst.Spec.VolumeMounts = src.Spec.VolumeMounts
+	dst.Spec.InitContainers = src.Spec.InitContainers
+	dst.Spec.SidecarContainers = src.Spec.SidecarContainers
+	dst.Spec.Affinity = (*OpenLibertyApplicationAffinity)(src.Spec.Affinity)
+
+	dst.Status.Conditions = nil
+	if src.Status.Conditions != nil {
+		dst.Status.Conditions = make([]StatusCondition, len(src.Status.Conditions))
+		for i, condition := range src.Status.Conditions {
+			dst.Status.Conditions[i] = StatusCondition{
+				LastTransitionTime: condition.LastTransitionTime,
+				Reason:             condition.Reason,
+				Message:            condition.Message,
+				Status:             condition.Status,
+				Type:               StatusConditionType(condition.Type),
+			}
+		}
+	}
+	dst.Status.RouteAvailable = src.Status.RouteAvailable
+	dst.Status.ImageReference = src.Status.ImageReference
+	dst.Status.Binding = src.Status.Binding
+
+	data.Spec.ServiceAccount = src.Spec.ServiceAccount
+	data.Spec.ManagePasswordEncryption = src.Spec.ManagePasswordEncryption
+	data.Spec.ManageLTPA = src.Spec.ManageLTPA
+	data.Spec.LTPA = src.Spec.LTPA
+	data.Spec.ManageTLS = src.Spec.ManageTLS
+	data.Spec.SemeruCloudCompiler = src.Spec.SemeruCloudCompiler
+	data.Spec.NetworkPolicy = src.Spec.NetworkPolicy
+	data.Spec.SecurityContext = src.Spec.SecurityContext
+	data.Spec.TopologySpreadConstraints = src.Spec.TopologySpreadConstraints
+	data.Spec.DisableServiceLinks = src.Spec.DisableServiceLinks
+	data.Spec.Tolerations = src.Spec.Tolerations
+	data.Spec.DNS = src.Spec.DNS
+	data.Spec.HostAliases = src.Spec.HostAliases
+	data.Spec.PriorityClassName = src.Spec.PriorityClassName
+	data.Status.Endpoints = src.Status.Endpoints
+	data.Status.PulledImageReference = src.Status.PulledImageReference
+	data.Status.Versions = src.Status.Versions
+	data.Status.References = src.Status.References
+	data.Status.SemeruCompiler = src.Status.SemeruCompiler
+	data.Status.PasswordEncryption = src.Status.PasswordEncryption
+	data.Status.ObservedGeneration = src.Status.ObservedGeneration
+	data.Status.ReconcileInterval = src.Status.ReconcileInterval
+	data.Status.ServiceabilityPods = src.Status.ServiceabilityPods
+	return marshalConversionData(dst, data)
+}
@@ -0,0 +1,91 @@
+package v1beta2
+
+import (
+	olv1 "github.com/OpenLiberty/open-liberty-operator/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// The fields of a v1 OpenLibertyDump that v1beta2 does not have
+type openLibertyDumpConversionData struct {
+	Spec   olv1.OpenLibertyDumpSpec   `json:"spec"`
+	Status olv1.OpenLibertyDumpStatus `json:"status"`
+}
+
+// ConvertTo converts this OpenLibertyDump to the hub version (v1)
+func (src *OpenLibertyDump) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*olv1.OpenLibertyDump)
+	dst.ObjectMeta = src.ObjectMeta
+
+	dst.Spec.PodName = src.Spec.PodName
+	dst.Spec.Include = nil
+	if src.Spec.Include != nil {
+		dst.Spec.Include = make([]olv1.OpenLibertyDumpInclude, len(src.Spec.Include))
+		for i, include := range src.Spec.Include {
+			dst.Spec.Include[i] = olv1.OpenLibertyDumpInclude(include)
+		}
+	}
+
+	dst.Status.Conditions = convertOperationStatusConditionsTo(src.Status.Conditions)
+	dst.Status.DumpFile = src.Status.DumpFile
+
+	restored := &openLibertyDumpConversionData{}
+	if ok, err := unmarshalConversionData(src, dst, restored); err != nil || !ok {
+		return err
+	}
+	dst.Spec.ApplicationRef = restored.Spec.ApplicationRef
+	dst.Spec.PodSelector = restored.Spec.PodSelector
+	dst.Spec.MaxConcurrentDumps = restored.Spec.MaxConcurrentDumps
+	dst.Spec.Schedule = restored.Spec.Schedule
+	dst.Spec.RetentionCount = restored.Spec.RetentionCount
+	dst.Spec.TimeoutSeconds = restored.Spec.TimeoutSeconds
+	dst.Spec.Export = restored.Spec.Export
+	dst.Status.Versions = restored.Status.Versions
+	dst.Status.PodName = restored.Status.PodName
+	dst.Status.Export = restored.Status.Export
+	dst.Status.ObservedGeneration = restored.Status.ObservedGeneration
+	dst.Status.LastScheduleTime = restored.Status.LastScheduleTime
+	dst.Status.NextScheduleTime = restored.Status.NextScheduleTime
+	dst.Status.History = restored.Status.History
+	dst.Status.Pods = restored.Status.Pods
+	return nil
+}
+
+// ConvertFrom converts the hub version (v1) of an OpenLibertyDump to this version
+func (dst *OpenLibertyDump) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*olv1.OpenLibertyDump)
+	dst.ObjectMeta = src.ObjectMeta
+
+	dst.Spec.PodName = src.Spec.PodName
+	dst.Spec.Include = nil
+	if src.Spec.Include != nil {
+		dst.Spec.Include = make([]OpenLibertyDumpInclude, len(src.Spec.Include))
+		for i, include := range src.Spec.Include {
+			dst.Spec.Include[i] = OpenLibertyDumpInclude(include)
+		}
+	}
+
+	dst.Status.Conditions = convertOperationStatusConditionsFrom(src.Status.Conditions)
+	dst.Status.DumpFile = src.Status.DumpFile
+
+	return marshalConversionData(dst, &openLibertyDumpConversionData{
+		Spec: olv1.OpenLibertyDumpSpec{
+			ApplicationRef:     src.Spec.ApplicationRef,
+			PodSelector:        src.Spec.PodSelector,
+			MaxConcurrentDumps: src.Spec.MaxConcurrentDumps,
+			Schedule:           src.Spec.Schedule,
+			RetentionCount:     src.Spec.RetentionCount,
+			TimeoutSeconds:     src.Spec.TimeoutSeconds,
+			Export:             src.Spec.Export,
+		},
+		Status: olv1.OpenLibertyDumpStatus{
+			Versions:           src.Status.Versions,
+			PodName:            src.Status.PodName,
+			Export:             src.Status.Export,
+			ObservedGeneration: src.Status.ObservedGeneration,
+			LastScheduleTime:   src.Status.LastScheduleTime,
+			NextScheduleTime:   src.Status.NextScheduleTime,
+			History:            src.Status.History,
+			Pods:               src.Status.Pods,
+		},
+	})
+}
@@ -0,0 +1,95 @@
+package v1beta2
+
+import (
+	olv1 "github.com/OpenLiberty/open-liberty-operator/api/v1"
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+)
+
+// The fields of a v1 OpenLibertyTrace that v1beta2 does not have
+type openLibertyTraceConversionData struct {
+	Spec   olv1.OpenLibertyTraceSpec   `json:"spec"`
+	Status olv1.OpenLibertyTraceStatus `json:"status"`
+}
+
+// ConvertTo converts this OpenLibertyTrace to the hub version (v1)
+func (src *OpenLibertyTrace) ConvertTo(dstRaw conversion.Hub) error {
+	dst := dstRaw.(*olv1.OpenLibertyTrace)
+	dst.ObjectMeta = src.ObjectMeta
+
+	dst.Spec.PodName = src.Spec.PodName
+	dst.Spec.TraceSpecification = src.Spec.TraceSpecification
+	dst.Spec.MaxFileSize = src.Spec.MaxFileSize
+	dst.Spec.MaxFiles = src.Spec.MaxFiles
+	dst.Spec.Disable = src.Spec.Disable
+
+	dst.Status.Conditions = convertOperationStatusConditionsTo(src.Status.Conditions)
+	dst.Status.OperatedResource = olv1.OperatedResource{
+		ResourceType: src.Status.OperatedResource.ResourceType,
+		ResourceName: src.Status.OperatedResource.ResourceName,
+	}
+
+	restored := &openLibertyTraceConversionData{}
+	if ok, err := unmarshalConversionData(src, dst, restored); err != nil || !ok {
+		return err
+	}
+	dst.Spec.ApplicationRef = restored.Spec.ApplicationRef
+	dst.Spec.TraceFormat = restored.Spec.TraceFormat
+	dst.Spec.ConsoleLogLevel = restored.Spec.ConsoleLogLevel
+	dst.Spec.MessageFormat = restored.Spec.MessageFormat
+	dst.Spec.DurationSeconds = restored.Spec.DurationSeconds
+	dst.Spec.ExpiresAt = restored.Spec.ExpiresAt
+	dst.Spec.Export = restored.Spec.Export
+	dst.Status.OperatedResource.LogDirectory = restored.Status.OperatedResource.LogDirectory
+	dst.Status.OperatedResource.Export = restored.Status.OperatedResource.Export
+	dst.Status.OperatedResource.Conditions = restored.Status.OperatedResource.Conditions
+	dst.Status.Versions = restored.Status.Versions
+	dst.Status.OperatedResources = restored.Status.OperatedResources
+	dst.Status.ExpiresAt = restored.Status.ExpiresAt
+	dst.Status.LogDirectory = restored.Status.LogDirectory
+	dst.Status.Export = restored.Status.Export
+	dst.Status.ObservedGeneration = restored.Status.ObservedGeneration
+	return nil
+}
+
+// ConvertFrom converts the hub version (v1) of an OpenLibertyTrace to this version
+func (dst *OpenLibertyTrace) ConvertFrom(srcRaw conversion.Hub) error {
+	src := srcRaw.(*olv1.OpenLibertyTrace)
+	dst.ObjectMeta = src.ObjectMeta
+
+	dst.Spec.PodName = src.Spec.PodName
+	dst.Spec.TraceSpecification = src.Spec.TraceSpecification
+	dst.Spec.MaxFileSize = src.Spec.MaxFileSize
+	dst.Spec.MaxFiles = src.Spec.MaxFiles
+	dst.Spec.Disable = src.Spec.Disable
+
+	dst.Status.Conditions = convertOperationStatusConditionsFrom(src.Status.Conditions)
+	dst.Status.OperatedResource = OperatedResource{
+		ResourceType: src.Status.OperatedResource.ResourceType,
+		ResourceName: src.Status.OperatedResource.ResourceName,
+	}
+
+	return marshalConversionData(dst, &openLibertyTraceConversionData{
+		Spec: olv1.OpenLibertyTraceSpec{
+			ApplicationRef:  src.Spec.ApplicationRef,
+			TraceFormat:     src.Spec.TraceFormat,
+			ConsoleLogLevel: src.Spec.ConsoleLogLevel,
+			MessageFormat:   src.Spec.MessageFormat,
+			DurationSeconds: src.Spec.DurationSeconds,
+			ExpiresAt:       src.Spec.ExpiresAt,
+			Export:          src.Spec.Export,
+		},
+		Status: olv1.OpenLibertyTraceStatus{
+			OperatedResource: olv1.OperatedResource{
+				LogDirectory: src.Status.OperatedResource.LogDirectory,
+				Export:       src.Status.OperatedResource.Export,
+				Conditions:   src.Status.OperatedResource.Conditions,
+			},
+			Versions:           src.Status.Versions,
+			OperatedResources:  src.Status.OperatedResources,
+			ExpiresAt:          src.Status.ExpiresAt,
+			LogDirectory:       src.Status.LogDirectory,
+			Export:             src.Status.Export,
+			ObservedGeneration: src.Status.ObservedGeneration,
+		},
+	})
+}
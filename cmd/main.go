@@ -17,9 +17,11 @@ limitations under the License.
 package main
 
 import (
+	"crypto/tls"
 	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
 	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -28,12 +30,17 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	openlibertyv1 "github.com/OpenLiberty/open-liberty-operator/api/v1"
+	openlibertyv1beta2 "github.com/OpenLiberty/open-liberty-operator/api/v1beta2"
 	"github.com/OpenLiberty/open-liberty-operator/internal/controller"
+	webhookv1 "github.com/OpenLiberty/open-liberty-operator/internal/webhook/v1"
 
 	"github.com/application-stacks/runtime-component-operator/common"
 	"github.com/application-stacks/runtime-component-operator/utils"
@@ -44,6 +51,7 @@ import (
 	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 
+	"github.com/OpenLiberty/open-liberty-operator/utils/artifacts"
 	"github.com/OpenLiberty/open-liberty-operator/utils/socket"
 )
 
@@ -56,6 +64,7 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(openlibertyv1.AddToScheme(scheme))
+	utilruntime.Must(openlibertyv1beta2.AddToScheme(scheme))
 
 	utilruntime.Must(routev1.AddToScheme(scheme))
 
@@ -73,30 +82,44 @@ func main() {
 	var metricsAddr string
 	var enableLeaderElection bool
 	var probeAddr string
-
+	var artifactsAddr string
+	var artifactsCertPath, artifactsCertName, artifactsCertKey string
+
+	var metricsCertPath, metricsCertName, metricsCertKey string
+	var webhookCertPath, webhookCertName, webhookCertKey string
+	var secureMetrics bool
+	var enableWebhooks bool
+	var enableHTTP2 bool
+	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "enable-leader-election", false,
 		"Enable leader election for controller manager. "+
 			"Enabling this will ensure there is only one active controller manager.")
-
-	// var metricsCertPath, metricsCertName, metricsCertKey string
-	// var webhookCertPath, webhookCertName, webhookCertKey string
-	// var secureMetrics bool
-	// var enableHTTP2 bool
-	// var tlsOpts []func(*tls.Config)
-	// flag.BoolVar(&secureMetrics, "metrics-secure", true,
-	// 	"If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
-	// flag.StringVar(&webhookCertPath, "webhook-cert-path", "", "The directory that contains the webhook certificate.")
-	// flag.StringVar(&webhookCertName, "webhook-cert-name", "tls.crt", "The name of the webhook certificate file.")
-	// flag.StringVar(&webhookCertKey, "webhook-cert-key", "tls.key", "The name of the webhook key file.")
-	// flag.StringVar(&metricsCertPath, "metrics-cert-path", "",
-	// 	"The directory that contains the metrics server certificate.")
-	// flag.StringVar(&metricsCertName, "metrics-cert-name", "tls.crt", "The name of the metrics server certificate file.")
-	// flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
-	// flag.BoolVar(&enableHTTP2, "enable-http2", false,
-	// 	"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.StringVar(&artifactsAddr, "artifacts-bind-address", "0", "The address the HTTPS endpoint serving the artifacts "+
+		"of day-2 operations binds to, e.g. :8444, or leave as 0 to disable the endpoint.")
+	flag.StringVar(&artifactsCertPath, "artifacts-cert-path", "",
+		"The directory that contains the artifacts server certificate. A self-signed certificate is used if not set.")
+	flag.StringVar(&artifactsCertName, "artifacts-cert-name", "tls.crt", "The name of the artifacts server certificate file.")
+	flag.StringVar(&artifactsCertKey, "artifacts-cert-key", "tls.key", "The name of the artifacts server key file.")
+
+	flag.BoolVar(&secureMetrics, "metrics-secure", true,
+		"If set, the metrics endpoint is served securely via HTTPS and requires an authorized bearer token. "+
+			"Use --metrics-secure=false to use HTTP instead.")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false,
+		"If set, the validating and defaulting webhooks for OpenLibertyApplication and the conversion webhooks "+
+			"between v1beta2 and v1 are served on port 9443. "+
+			"The webhook server requires a certificate, see --webhook-cert-path.")
+	flag.StringVar(&webhookCertPath, "webhook-cert-path", "", "The directory that contains the webhook certificate.")
+	flag.StringVar(&webhookCertName, "webhook-cert-name", "tls.crt", "The name of the webhook certificate file.")
+	flag.StringVar(&webhookCertKey, "webhook-cert-key", "tls.key", "The name of the webhook key file.")
+	flag.StringVar(&metricsCertPath, "metrics-cert-path", "",
+		"The directory that contains the metrics server certificate. A self-signed certificate is used if not set.")
+	flag.StringVar(&metricsCertName, "metrics-cert-name", "tls.crt", "The name of the metrics server certificate file.")
+	flag.StringVar(&metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
+	flag.BoolVar(&enableHTTP2, "enable-http2", false,
+		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
 
 	opts := zap.Options{
 		Level:           common.LevelFunc,
@@ -109,39 +132,40 @@ func main() {
 	utils.CreateConfigMap(controller.OperatorName)
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
-	// disableHTTP2 := func(c *tls.Config) {
-	// 	setupLog.Info("disabling http/2")
-	// 	c.NextProtos = []string{"http/1.1"}
-	// }
-
-	// if !enableHTTP2 {
-	// 	tlsOpts = append(tlsOpts, disableHTTP2)
-	// }
-
-	// // Create watchers for metrics and webhooks certificates
-	// var metricsCertWatcher, webhookCertWatcher *certwatcher.CertWatcher
-
-	// // Initial webhook TLS options
-	// webhookTLSOpts := tlsOpts
-
-	// if len(webhookCertPath) > 0 {
-	// 	setupLog.Info("Initializing webhook certificate watcher using provided certificates",
-	// 		"webhook-cert-path", webhookCertPath, "webhook-cert-name", webhookCertName, "webhook-cert-key", webhookCertKey)
+	// HTTP/2 is disabled by default to avoid the HTTP/2 Stream Cancellation and Rapid Reset vulnerabilities
+	disableHTTP2 := func(c *tls.Config) {
+		setupLog.Info("disabling http/2")
+		c.NextProtos = []string{"http/1.1"}
+	}
 
-	// 	var err error
-	// 	webhookCertWatcher, err = certwatcher.New(
-	// 		filepath.Join(webhookCertPath, webhookCertName),
-	// 		filepath.Join(webhookCertPath, webhookCertKey),
-	// 	)
-	// 	if err != nil {
-	// 		setupLog.Error(err, "Failed to initialize webhook certificate watcher")
-	// 		os.Exit(1)
-	// 	}
+	if !enableHTTP2 {
+		tlsOpts = append(tlsOpts, disableHTTP2)
+	}
 
-	// 	webhookTLSOpts = append(webhookTLSOpts, func(config *tls.Config) {
-	// 		config.GetCertificate = webhookCertWatcher.GetCertificate
-	// 	})
-	// }
+	// Create watchers for metrics and webhooks certificates, which reload the certificates when they are renewed
+	var metricsCertWatcher, webhookCertWatcher *certwatcher.CertWatcher
+
+	// Initial webhook TLS options
+	webhookTLSOpts := tlsOpts
+
+	if len(webhookCertPath) > 0 {
+		setupLog.Info("Initializing webhook certificate watcher using provided certificates",
+			"webhook-cert-path", webhookCertPath, "webhook-cert-name", webhookCertName, "webhook-cert-key", webhookCertKey)
+
+		var err error
+		webhookCertWatcher, err = certwatcher.New(
+			filepath.Join(webhookCertPath, webhookCertName),
+			filepath.Join(webhookCertPath, webhookCertKey),
+		)
+		if err != nil {
+			setupLog.Error(err, "Failed to initialize webhook certificate watcher")
+			os.Exit(1)
+		}
+
+		webhookTLSOpts = append(webhookTLSOpts, func(config *tls.Config) {
+			config.GetCertificate = webhookCertWatcher.GetCertificate
+		})
+	}
 
 	// see https://github.com/operator-framework/operator-sdk/issues/1813
 	leaseDuration := 30 * time.Second
@@ -154,40 +178,44 @@ func main() {
 	}
 
 	metricsServerOptions := metricsserver.Options{
-		BindAddress: metricsAddr,
-		// SecureServing: secureMetrics,
-		// TLSOpts:       tlsOpts,
+		BindAddress:   metricsAddr,
+		SecureServing: secureMetrics,
+		TLSOpts:       tlsOpts,
 	}
 
-	// if secureMetrics {
-	// 	metricsServerOptions.FilterProvider = filters.WithAuthenticationAndAuthorization
-	// }
-
-	// if len(metricsCertPath) > 0 {
-	// 	setupLog.Info("Initializing metrics certificate watcher using provided certificates",
-	// 		"metrics-cert-path", metricsCertPath, "metrics-cert-name", metricsCertName, "metrics-cert-key", metricsCertKey)
-
-	// 	var err error
-	// 	metricsCertWatcher, err = certwatcher.New(
-	// 		filepath.Join(metricsCertPath, metricsCertName),
-	// 		filepath.Join(metricsCertPath, metricsCertKey),
-	// 	)
-	// 	if err != nil {
-	// 		setupLog.Error(err, "to initialize metrics certificate watcher", "error", err)
-	// 		os.Exit(1)
-	// 	}
-
-	// 	metricsServerOptions.TLSOpts = append(metricsServerOptions.TLSOpts, func(config *tls.Config) {
-	// 		config.GetCertificate = metricsCertWatcher.GetCertificate
-	// 	})
-	// }
+	// The requests of the metrics endpoint are authenticated with TokenReviews and authorized with SubjectAccessReviews,
+	// which are granted by config/rbac/metrics_auth_role.yaml
+	if secureMetrics {
+		metricsServerOptions.FilterProvider = filters.WithAuthenticationAndAuthorization
+	}
+
+	// A self-signed certificate is generated for the metrics endpoint unless a certificate is provided
+	if len(metricsCertPath) > 0 {
+		setupLog.Info("Initializing metrics certificate watcher using provided certificates",
+			"metrics-cert-path", metricsCertPath, "metrics-cert-name", metricsCertName, "metrics-cert-key", metricsCertKey)
+
+		var err error
+		metricsCertWatcher, err = certwatcher.New(
+			filepath.Join(metricsCertPath, metricsCertName),
+			filepath.Join(metricsCertPath, metricsCertKey),
+		)
+		if err != nil {
+			setupLog.Error(err, "Failed to initialize metrics certificate watcher")
+			os.Exit(1)
+		}
+
+		metricsServerOptions.TLSOpts = append(metricsServerOptions.TLSOpts, func(config *tls.Config) {
+			config.GetCertificate = metricsCertWatcher.GetCertificate
+		})
+	}
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme:  scheme,
 		Metrics: metricsServerOptions,
 		WebhookServer: &webhook.DefaultServer{
 			Options: webhook.Options{
-				Port: 9443,
+				Port:    9443,
+				TLSOpts: webhookTLSOpts,
 			},
 		},
 		HealthProbeBindAddress: probeAddr,
@@ -221,9 +249,28 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "OpenLibertyDump")
 		os.Exit(1)
 	}
+	// The LTPA key groups mirror Secrets into namespaces across the cluster, so they are only managed by an operator that watches all namespaces.
+	// The Secrets are read and written without the cache of the manager, which would otherwise hold every Secret of the cluster.
+	if watchNamespace == "" {
+		uncachedClient, err := client.New(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme(), Mapper: mgr.GetRESTMapper()})
+		if err != nil {
+			setupLog.Error(err, "unable to create client", "controller", "OpenLibertyLTPAKeyGroup")
+			os.Exit(1)
+		}
+		if err = (&controller.ReconcileOpenLibertyLTPAKeyGroup{
+			ReconcilerBase: utils.NewReconcilerBase(mgr.GetAPIReader(), uncachedClient, mgr.GetScheme(), mgr.GetConfig(), mgr.GetEventRecorderFor("open-liberty-operator")),
+			Log:            ctrl.Log.WithName("controller").WithName("OpenLibertyLTPAKeyGroup"),
+		}).SetupWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "OpenLibertyLTPAKeyGroup")
+			os.Exit(1)
+		}
+	} else {
+		setupLog.Info("OpenLibertyLTPAKeyGroups are not managed because the operator does not watch all namespaces", "watchNamespace", watchNamespace)
+	}
 	if err = (&controller.ReconcileOpenLibertyPerformanceData{
 		ReconcilerBase:    utils.NewReconcilerBase(mgr.GetAPIReader(), mgr.GetClient(), mgr.GetScheme(), mgr.GetConfig(), mgr.GetEventRecorderFor("open-liberty-operator")),
 		Log:               ctrl.Log.WithName("controller").WithName("OpenLibertyPerformanceData"),
+		RestConfig:        mgr.GetConfig(),
 		PodInjectorClient: socket.GetPodInjectorClient(ctrl.Log.WithName("controller").WithName("PodInjectorClient").V(common.LogLevelDebug)),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "OpenLibertyPerformanceData")
@@ -239,22 +286,55 @@ func main() {
 		setupLog.Error(err, "unable to create controller", "controller", "OpenLibertyTrace")
 		os.Exit(1)
 	}
+	if err = (&controller.ReconcileOpenLibertyAutoDump{
+		Log:       ctrl.Log.WithName("controller").WithName("OpenLibertyAutoDump"),
+		Client:    mgr.GetClient(),
+		APIReader: mgr.GetAPIReader(),
+		Scheme:    mgr.GetScheme(),
+		Recorder:  mgr.GetEventRecorderFor("open-liberty-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpenLibertyAutoDump")
+		os.Exit(1)
+	}
+	if err = (&controller.ReconcileOpenLibertyRetention{
+		Log:        ctrl.Log.WithName("controller").WithName("OpenLibertyRetention"),
+		Client:     mgr.GetClient(),
+		RestConfig: mgr.GetConfig(),
+		Recorder:   mgr.GetEventRecorderFor("open-liberty-operator"),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "OpenLibertyRetention")
+		os.Exit(1)
+	}
+	if enableWebhooks {
+		if err = webhookv1.SetupOpenLibertyApplicationWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "OpenLibertyApplication")
+			os.Exit(1)
+		}
+		if err = webhookv1.SetupOpenLibertyDumpWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "OpenLibertyDump")
+			os.Exit(1)
+		}
+		if err = webhookv1.SetupOpenLibertyTraceWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "OpenLibertyTrace")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
-	// if metricsCertWatcher != nil {
-	// 	setupLog.Info("Adding metrics certificate watcher to manager")
-	// 	if err := mgr.Add(metricsCertWatcher); err != nil {
-	// 		setupLog.Error(err, "unable to add metrics certificate watcher to manager")
-	// 		os.Exit(1)
-	// 	}
-	// }
-
-	// if webhookCertWatcher != nil {
-	// 	setupLog.Info("Adding webhook certificate watcher to manager")
-	// 	if err := mgr.Add(webhookCertWatcher); err != nil {
-	// 		setupLog.Error(err, "unable to add webhook certificate watcher to manager")
-	// 		os.Exit(1)
-	// 	}
-	// }
+	if metricsCertWatcher != nil {
+		setupLog.Info("Adding metrics certificate watcher to manager")
+		if err := mgr.Add(metricsCertWatcher); err != nil {
+			setupLog.Error(err, "unable to add metrics certificate watcher to manager")
+			os.Exit(1)
+		}
+	}
+
+	if webhookCertWatcher != nil {
+		setupLog.Info("Adding webhook certificate watcher to manager")
+		if err := mgr.Add(webhookCertWatcher); err != nil {
+			setupLog.Error(err, "unable to add webhook certificate watcher to manager")
+			os.Exit(1)
+		}
+	}
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
@@ -273,6 +353,38 @@ func main() {
 	}
 	defer listener.Close()
 
+	if artifactsAddr != "0" {
+		var artifactsTLSConfig *tls.Config
+		if len(artifactsCertPath) > 0 {
+			setupLog.Info("Initializing artifacts certificate watcher using provided certificates",
+				"artifacts-cert-path", artifactsCertPath, "artifacts-cert-name", artifactsCertName, "artifacts-cert-key", artifactsCertKey)
+			artifactsCertWatcher, err := certwatcher.New(
+				filepath.Join(artifactsCertPath, artifactsCertName),
+				filepath.Join(artifactsCertPath, artifactsCertKey),
+			)
+			if err != nil {
+				setupLog.Error(err, "unable to initialize artifacts certificate watcher")
+				os.Exit(1)
+			}
+			if err := mgr.Add(artifactsCertWatcher); err != nil {
+				setupLog.Error(err, "unable to add artifacts certificate watcher to manager")
+				os.Exit(1)
+			}
+			artifactsTLSConfig = &tls.Config{GetCertificate: artifactsCertWatcher.GetCertificate, MinVersion: tls.VersionTLS12}
+		} else if artifactsTLSConfig, err = artifacts.NewSelfSignedTLSConfig(); err != nil {
+			setupLog.Error(err, "unable to generate artifacts server certificate")
+			os.Exit(1)
+		}
+
+		setupLog.Info("starting artifacts server")
+		artifactsListener, err := artifacts.ServeArtifacts(mgr, artifactsAddr, artifactsTLSConfig, ctrl.Log.WithName("controller").WithName("ArtifactsServer"))
+		if err != nil {
+			setupLog.Error(err, "problem running artifacts server")
+			os.Exit(1)
+		}
+		defer artifactsListener.Close()
+	}
+
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
 		setupLog.Error(err, "problem running manager")
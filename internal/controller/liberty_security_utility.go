@@ -1,99 +1,37 @@
 package controller
 
 import (
-	"fmt"
-	"os/exec"
-
-	"github.com/OpenLiberty/open-liberty-operator/utils"
+	"github.com/OpenLiberty/open-liberty-operator/utils/security"
 )
 
-const SECURITY_UTILITY_BINARY = "liberty/bin/securityUtility"
-const SECURITY_UTILITY_ENCODE = "encode"
-const SECURITY_UTILITY_CREATE_LTPA_KEYS = "createLTPAKeys"
-const SECURITY_UTILITY_OUTPUT_FOLDER = "liberty/output"
-
-var validPasswordEncodingTypes = []string{"aes", "aes-128"}
+var validPasswordEncodingTypes = []string{security.EncodingAES, security.EncodingAES128}
 
+// Encodes the password like the Liberty security utility encode command, using the AES key in passwordBase64AESKey or else the key string in passwordKey
 func encode(password string, passwordKey *string, passwordBase64AESKey *string, passwordEncodingType string) ([]byte, error) {
-	params := []string{}
-	params = append(params, SECURITY_UTILITY_ENCODE)
-	params = append(params, fmt.Sprintf("--encoding=%s", parsePasswordEncodingType(passwordEncodingType)))
+	key, base64Key := "", ""
 	if passwordBase64AESKey != nil && *passwordBase64AESKey != "" {
-		params = append(params, fmt.Sprintf("--base64Key=%s", *passwordBase64AESKey))
+		base64Key = *passwordBase64AESKey
 	} else if passwordKey != nil && *passwordKey != "" {
-		params = append(params, fmt.Sprintf("--key=%s", *passwordKey))
+		key = *passwordKey
 	}
-	params = append(params, password)
-	return callSecurityUtility(params)
-}
-
-func createLTPAKeys(password string, passwordKey *string, passwordBase64AESKey *string, passwordEncodingType string) ([]byte, error) {
-	tmpFileName := fmt.Sprintf("ltpa-keys-%s.keys", utils.GetRandomAlphanumeric(15))
-	tmpFilePath := fmt.Sprintf("%s/%s", SECURITY_UTILITY_OUTPUT_FOLDER, tmpFileName)
-
-	// delete possible colliding file
-	callDeleteFile(tmpFilePath)
-
-	// mkdir if not exists
-	// callMkdir(SECURITY_UTILITY_OUTPUT_FOLDER)
-
-	// create the key
-	params := []string{}
-	params = append(params, SECURITY_UTILITY_CREATE_LTPA_KEYS)
-	params = append(params, fmt.Sprintf("--file=%s", tmpFilePath))
-	params = append(params, fmt.Sprintf("--passwordEncoding=%s", parsePasswordEncodingType(passwordEncodingType))) // use aes encoding
-	if passwordBase64AESKey != nil && *passwordBase64AESKey != "" {
-		params = append(params, fmt.Sprintf("--passwordBase64Key=%s", *passwordBase64AESKey))
-	} else if passwordKey != nil && *passwordKey != "" {
-		params = append(params, fmt.Sprintf("--passwordKey=%s", *passwordKey))
+	encoded, err := security.Encode(password, parsePasswordEncodingType(passwordEncodingType), key, base64Key)
+	if err != nil {
+		return []byte{}, err
 	}
-	params = append(params, fmt.Sprintf("--password=%s", password))
-	callSecurityUtility(params)
-
-	// read the key
-	params = []string{}
-	params = append(params, "-c")
-	params = append(params, fmt.Sprintf("cat %s | base64", tmpFilePath))
-	bytesOut, err := callCommand("/bin/bash", params)
+	return []byte(encoded), nil
+}
 
-	// delete the key
-	callDeleteFile(tmpFilePath)
-	return bytesOut, err
+// Returns the content of a new ltpa.keys file protected by the password, like the Liberty security utility createLTPAKeys command
+func createLTPAKeys(password string) ([]byte, error) {
+	return security.CreateLTPAKeys(password)
 }
 
-// Returns the password encoding type to use for Liberty security utility encode and createLTPAKeys. Defaults to "aes" when invalid or undefined.
+// Returns the password encoding type to use for encode. Defaults to "aes" when invalid or undefined.
 func parsePasswordEncodingType(passwordEncodingType string) string {
 	for _, validType := range validPasswordEncodingTypes {
 		if validType == passwordEncodingType {
 			return validType
 		}
 	}
-	return "aes"
-}
-
-// func callMkdir(folderPath string) {
-// 	params := []string{}
-// 	params = append(params, "-c")
-// 	params = append(params, fmt.Sprintf("mkdir -p %s", folderPath))
-// 	callCommand("/bin/bash", params)
-// }
-
-func callDeleteFile(filePath string) {
-	params := []string{}
-	params = append(params, "-c")
-	params = append(params, fmt.Sprintf("rm -f %s", filePath))
-	callCommand("/bin/bash", params)
-}
-
-func callSecurityUtility(params []string) ([]byte, error) {
-	return callCommand(SECURITY_UTILITY_BINARY, params)
-}
-
-func callCommand(binary string, params []string) ([]byte, error) {
-	cmd := exec.Command(binary, params...)
-	stdout, err := cmd.Output()
-	if err != nil {
-		return []byte{}, err
-	}
-	return stdout, nil
+	return security.EncodingAES
 }
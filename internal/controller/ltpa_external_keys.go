@@ -0,0 +1,176 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+
+	olv1 "github.com/OpenLiberty/open-liberty-operator/api/v1"
+	lutils "github.com/OpenLiberty/open-liberty-operator/utils"
+	"github.com/OpenLiberty/open-liberty-operator/utils/security"
+	"github.com/application-stacks/runtime-component-operator/common"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Field of the Secret in .spec.ltpa.keysSecretRef with the password of the LTPA keys
+const LTPAExternalKeysPasswordKey = "password"
+
+// Field of the imported LTPA keys Secret with the hash of the keys, password and encryption key that the Secret was written from
+const ltpaExternalKeysSourceHashKey = "sourceHash"
+
+// Returns true if the instance uses the existing LTPA keys of the Secret in .spec.ltpa.keysSecretRef
+func (r *ReconcileOpenLiberty) isUsingExternalLTPAKeys(instance *olv1.OpenLibertyApplication) bool {
+	keysSecretRef := instance.GetLTPAKeysSecretRef()
+	return keysSecretRef != nil && *keysSecretRef != ""
+}
+
+// Returns true if the Liberty server of the instance imports LTPA keys managed by the operator, either generated or existing
+func (r *ReconcileOpenLiberty) isLTPAEnabled(instance *olv1.OpenLibertyApplication) bool {
+	return r.isLTPAKeySharingEnabled(instance) || r.isUsingExternalLTPAKeys(instance)
+}
+
+// Returns the suffix of the operator managed Secrets for the existing LTPA keys of the Secret keysSecretName. The name of the
+// Secret is hashed so that the names of the managed Secrets stay short enough to be used as label values.
+func getExternalLTPASuffix(keysSecretName string) string {
+	hash := sha256.Sum256([]byte(keysSecretName))
+	return "-external-" + hex.EncodeToString(hash[:])[:lutils.ResourceSuffixLength*2]
+}
+
+// Returns the suffix of the operator managed LTPA config Secrets of the instance
+func (r *ReconcileOpenLiberty) getLTPAConfigSuffix(instance *olv1.OpenLibertyApplication, ltpaConfigMetadata *lutils.LTPAMetadata) string {
+	if r.isUsingExternalLTPAKeys(instance) {
+		return getExternalLTPASuffix(*instance.GetLTPAKeysSecretRef())
+	}
+	if ltpaConfigMetadata != nil {
+		return ltpaConfigMetadata.Name
+	}
+	return ""
+}
+
+// Validates the existing LTPA keys of the Secret in .spec.ltpa.keysSecretRef and imports them into an operator managed Secret with the encoded password
+// and the last rotation time, then returns the name and the last rotation time of the managed Secret. The keys are not generated, so no leader is elected
+// and every instance that references the same Secret writes the same managed Secret. The last rotation time only changes when the keys, the password or
+// the password encryption key change, so that the pods are only rolled for a new key.
+func (r *ReconcileOpenLiberty) reconcileExternalLTPAKeys(instance *olv1.OpenLibertyApplication, passwordEncryptionMetadata *lutils.PasswordEncryptionMetadata) (string, string, error) {
+	keysSecretName := *instance.GetLTPAKeysSecretRef()
+	keysSecret := &corev1.Secret{}
+	if err := r.GetClient().Get(context.TODO(), types.NamespacedName{Name: keysSecretName, Namespace: instance.GetNamespace()}, keysSecret); err != nil {
+		if kerrors.IsNotFound(err) {
+			return "", "", fmt.Errorf("the LTPA keys Secret '%s' was not found in namespace '%s'", keysSecretName, instance.GetNamespace())
+		}
+		return "", "", err
+	}
+	keys, foundKeys := keysSecret.Data[lutils.LTPAKeysFileName]
+	password, foundPassword := keysSecret.Data[LTPAExternalKeysPasswordKey]
+	if !foundKeys || !foundPassword {
+		return "", "", fmt.Errorf("the LTPA keys Secret '%s' must have the fields '%s' and '%s'", keysSecretName, lutils.LTPAKeysFileName, LTPAExternalKeysPasswordKey)
+	}
+
+	// The password is encoded with the same password encryption key as the password of the generated LTPA keys
+	encryptionKey, encryptionKeyLastRotation, encryptionKeySharingEnabled, usingAES, err := r.getInternalEncryptionKeyState(instance, passwordEncryptionMetadata)
+	if encryptionKeySharingEnabled && err != nil {
+		return "", "", err
+	}
+	var currentPasswordEncryptionKey, currentAESEncryptionKey *string
+	if encryptionKey != "" && usingAES {
+		currentAESEncryptionKey = &encryptionKey
+	} else if encryptionKey != "" {
+		currentPasswordEncryptionKey = &encryptionKey
+	}
+
+	// An encoded password is decoded with the password encryption key, or else with the default key
+	decodeKey, decodeBase64Key := "", ""
+	if currentAESEncryptionKey != nil {
+		decodeBase64Key = encryptionKey
+	} else if currentPasswordEncryptionKey != nil {
+		decodeKey = encryptionKey
+	}
+	rawPassword, err := security.Decode(string(password), decodeKey, decodeBase64Key)
+	if err != nil && encryptionKey != "" {
+		rawPassword, err = security.Decode(string(password), "", "")
+	}
+	if err != nil {
+		return "", "", fmt.Errorf("failed to decode the password in the LTPA keys Secret '%s': %w", keysSecretName, err)
+	}
+	if _, err := security.ParseLTPAKeys(keys, rawPassword); err != nil {
+		return "", "", fmt.Errorf("the LTPA keys in Secret '%s' are not valid: %w", keysSecretName, err)
+	}
+
+	passwordEncodingType := parsePasswordEncodingType(common.LoadFromConfig(common.Config, lutils.OpConfigPasswordEncodingType))
+	hash := sha256.New()
+	for _, value := range [][]byte{keys, []byte(rawPassword), []byte(encryptionKeyLastRotation), []byte(strconv.FormatBool(usingAES)), []byte(passwordEncodingType)} {
+		hash.Write(value)
+		hash.Write([]byte{0})
+	}
+	sourceHash := hex.EncodeToString(hash.Sum(nil))
+
+	ltpaSecret := &corev1.Secret{}
+	ltpaSecretRootName := OperatorShortName + "-managed-ltpa-external"
+	ltpaSecret.Name = OperatorShortName + "-managed-ltpa" + getExternalLTPASuffix(keysSecretName)
+	ltpaSecret.Namespace = instance.GetNamespace()
+	err = r.GetClient().Get(context.TODO(), types.NamespacedName{Name: ltpaSecret.Name, Namespace: ltpaSecret.Namespace}, ltpaSecret)
+	if err == nil && string(ltpaSecret.Data[ltpaExternalKeysSourceHashKey]) == sourceHash {
+		return ltpaSecret.Name, string(ltpaSecret.Data["lastRotation"]), nil
+	} else if err != nil && !kerrors.IsNotFound(err) {
+		return "", "", err
+	}
+
+	encodedPassword, err := encode(rawPassword, currentPasswordEncryptionKey, currentAESEncryptionKey, passwordEncodingType)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to encode the password of the LTPA keys in Secret '%s': %w", keysSecretName, err)
+	}
+	lastRotation := strconv.FormatInt(time.Now().Unix(), 10)
+	data := map[string][]byte{
+		lutils.LTPAKeysFileName:       keys,
+		"password":                    encodedPassword,
+		"lastRotation":                []byte(lastRotation),
+		ltpaExternalKeysSourceHashKey: []byte(sourceHash),
+	}
+	if encryptionKey != "" && encryptionKeyLastRotation != "" {
+		data["encryptionKeyLastRotation"] = []byte(encryptionKeyLastRotation)
+	}
+	if err := r.CreateOrUpdate(ltpaSecret, nil, func() error {
+		if ltpaSecret.Labels == nil {
+			ltpaSecret.Labels = make(map[string]string)
+		}
+		for key, value := range lutils.GetRequiredLabels(ltpaSecretRootName, ltpaSecret.Name) {
+			ltpaSecret.Labels[key] = value
+		}
+		ltpaSecret.Data = data
+		return nil
+	}); err != nil {
+		return "", "", err
+	}
+	return ltpaSecret.Name, lastRotation, nil
+}
+
+// Creates or updates the Liberty server XML Secrets that import the existing LTPA keys of the Secret in .spec.ltpa.keysSecretRef and returns the name of the XML Secret
+func (r *ReconcileOpenLiberty) generateExternalLTPAConfig(instance *olv1.OpenLibertyApplication) (string, error) {
+	suffix := getExternalLTPASuffix(*instance.GetLTPAKeysSecretRef())
+
+	ltpaXMLSecret := &corev1.Secret{}
+	ltpaXMLSecretRootName := OperatorShortName + lutils.LTPAServerXMLSuffix
+	ltpaXMLSecret.Name = ltpaXMLSecretRootName + suffix
+	ltpaXMLSecret.Namespace = instance.GetNamespace()
+	ltpaXMLSecret.Labels = lutils.GetRequiredLabels(ltpaXMLSecretRootName, ltpaXMLSecret.Name)
+
+	ltpaXMLMountSecret := &corev1.Secret{}
+	ltpaXMLMountSecretRootName := OperatorShortName + lutils.LTPAServerXMLMountSuffix
+	ltpaXMLMountSecret.Name = ltpaXMLMountSecretRootName + suffix
+	ltpaXMLMountSecret.Namespace = instance.GetNamespace()
+	ltpaXMLMountSecret.Labels = lutils.GetRequiredLabels(ltpaXMLMountSecretRootName, ltpaXMLSecret.Name)
+
+	ltpaSecret := &corev1.Secret{}
+	if err := r.GetClient().Get(context.TODO(), types.NamespacedName{Name: OperatorShortName + "-managed-ltpa" + suffix, Namespace: instance.GetNamespace()}, ltpaSecret); err != nil {
+		return ltpaXMLSecret.Name, err
+	}
+	if err := r.writeLTPAServerXML(instance, ltpaXMLSecret, ltpaXMLMountSecret, ltpaSecret.Data); err != nil {
+		return ltpaXMLSecret.Name, err
+	}
+	return ltpaXMLSecret.Name, nil
+}
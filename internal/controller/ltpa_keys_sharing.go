@@ -1,8 +1,8 @@
 package controller
 
 import (
+	"bytes"
 	"context"
-	"encoding/base64"
 	"fmt"
 	"strconv"
 	"strings"
@@ -11,6 +11,7 @@ import (
 
 	olv1 "github.com/OpenLiberty/open-liberty-operator/api/v1"
 	lutils "github.com/OpenLiberty/open-liberty-operator/utils"
+	"github.com/OpenLiberty/open-liberty-operator/utils/metrics"
 	tree "github.com/OpenLiberty/open-liberty-operator/utils/tree"
 	"github.com/application-stacks/runtime-component-operator/common"
 	corev1 "k8s.io/api/core/v1"
@@ -183,24 +184,33 @@ func hasLTPAConfigResourceSuffixesEnv(instance *olv1.OpenLibertyApplication) (st
 	return hasResourceSuffixesEnv(instance, "LTPA_CONFIG_RESOURCE_SUFFIXES")
 }
 
-// Create or use an existing LTPA Secret identified by LTPA metadata for the OpenLibertyApplication instance
-func (r *ReconcileOpenLiberty) reconcileLTPAKeys(instance *olv1.OpenLibertyApplication, ltpaKeysMetadata *lutils.LTPAMetadata) (string, string, string, error) {
+// Create or use an existing LTPA Secret identified by LTPA metadata for the OpenLibertyApplication instance. Also returns the time until the LTPA keys
+// are due for rotation or their validation keys expire, or 0 if neither is scheduled.
+func (r *ReconcileOpenLiberty) reconcileLTPAKeys(instance *olv1.OpenLibertyApplication, ltpaKeysMetadata *lutils.LTPAMetadata, passwordEncryptionMetadata *lutils.PasswordEncryptionMetadata) (string, string, string, time.Duration, error) {
 	ltpaSecretName := ""
 	ltpaKeysLastRotation := ""
+	var requeueAfter time.Duration
 	if r.isLTPAKeySharingEnabled(instance) {
-		ltpaSecretNameTemp, ltpaKeysLastRotationTemp, _, err := r.generateLTPAKeys(instance, ltpaKeysMetadata)
+		ltpaSecretNameTemp, ltpaKeysLastRotationTemp, _, requeueAfterTemp, err := r.generateLTPAKeys(instance, ltpaKeysMetadata)
 		ltpaKeysLastRotation = ltpaKeysLastRotationTemp
 		ltpaSecretName = ltpaSecretNameTemp
+		requeueAfter = requeueAfterTemp
 		if err != nil {
-			return "Failed to generate the shared LTPA keys Secret", ltpaSecretName, ltpaKeysLastRotation, err
+			return "Failed to generate the shared LTPA keys Secret", ltpaSecretName, ltpaKeysLastRotation, requeueAfter, err
 		}
 	} else {
 		err := r.RemoveLeaderTrackerReference(instance, LTPA_RESOURCE_SHARING_FILE_NAME)
 		if err != nil {
-			return "Failed to remove leader tracking reference to the LTPA keys", ltpaSecretName, ltpaKeysLastRotation, err
+			return "Failed to remove leader tracking reference to the LTPA keys", ltpaSecretName, ltpaKeysLastRotation, requeueAfter, err
+		}
+		if r.isUsingExternalLTPAKeys(instance) {
+			ltpaSecretName, ltpaKeysLastRotation, err = r.reconcileExternalLTPAKeys(instance, passwordEncryptionMetadata)
+			if err != nil {
+				return "Failed to import the LTPA keys from Secret " + *instance.GetLTPAKeysSecretRef(), ltpaSecretName, ltpaKeysLastRotation, requeueAfter, err
+			}
 		}
 	}
-	return "", ltpaSecretName, ltpaKeysLastRotation, nil
+	return "", ltpaSecretName, ltpaKeysLastRotation, requeueAfter, nil
 }
 
 // Create or use an existing LTPA Secret identified by LTPA metadata for the OpenLibertyApplication instance
@@ -217,15 +227,20 @@ func (r *ReconcileOpenLiberty) reconcileLTPAConfig(instance *olv1.OpenLibertyApp
 		if err != nil {
 			return "Failed to remove leader tracking reference to the LTPA config", "", err
 		}
+		if r.isUsingExternalLTPAKeys(instance) {
+			ltpaXMLSecretName, err = r.generateExternalLTPAConfig(instance)
+			if err != nil {
+				return "Failed to generate the LTPA config for the keys in Secret " + *instance.GetLTPAKeysSecretRef(), ltpaXMLSecretName, err
+			}
+		}
 	}
 	return "", ltpaXMLSecretName, nil
 }
 
-// Generates the LTPA keys file and returns the name of the Secret storing its metadata
-func (r *ReconcileOpenLiberty) generateLTPAKeys(instance *olv1.OpenLibertyApplication, ltpaMetadata *lutils.LTPAMetadata) (string, string, string, error) {
+// Generates the LTPA keys file and returns the name of the Secret storing its metadata. The leader also returns the time until it must
+// rotate the LTPA keys or remove their expired validation keys.
+func (r *ReconcileOpenLiberty) generateLTPAKeys(instance *olv1.OpenLibertyApplication, ltpaMetadata *lutils.LTPAMetadata) (string, string, string, time.Duration, error) {
 	// Initialize LTPA resources
-	passwordEncryptionMetadata := &lutils.PasswordEncryptionMetadata{Name: ""}
-
 	ltpaXMLSecret := &corev1.Secret{}
 	ltpaXMLSecretRootName := OperatorShortName + lutils.LTPAServerXMLSuffix
 	ltpaXMLSecret.Name = ltpaXMLSecretRootName + ltpaMetadata.Name
@@ -248,69 +263,283 @@ func (r *ReconcileOpenLiberty) generateLTPAKeys(instance *olv1.OpenLibertyApplic
 	if err != nil && kerrors.IsNotFound(err) {
 		leaderName, thisInstanceIsLeader, _, err := r.reconcileLeader(instance, ltpaMetadata, LTPA_RESOURCE_SHARING_FILE_NAME, true)
 		if err != nil {
-			return "", "", leaderName, err
+			return "", "", leaderName, 0, err
 		}
 		// If this instance is not the leader, exit the reconcile loop
 		if !thisInstanceIsLeader {
-			return "", "", leaderName, fmt.Errorf("Waiting for OpenLibertyApplication instance '%s' to generate the shared LTPA keys file for the namespace '%s'.", leaderName, instance.Namespace)
+			return "", "", leaderName, 0, fmt.Errorf("Waiting for OpenLibertyApplication instance '%s' to generate the shared LTPA keys file for the namespace '%s'.", leaderName, instance.Namespace)
 		}
-
-		// Check the aes/password encryption key
-		encryptionKey, encryptionKeyLastRotation, encryptionKeySharingEnabled, usingAES, err := r.getInternalEncryptionKeyState(instance, passwordEncryptionMetadata)
-		if encryptionKeySharingEnabled && err != nil {
-			return "", "", "", err
+		groupSecret, err := r.getLTPAKeyGroupSecret(instance, ltpaMetadata)
+		if err != nil {
+			return "", "", leaderName, 0, err
 		}
-		keyExists := encryptionKey != ""
-		password := lutils.GetRandomAlphanumeric(15)
-
-		var currentPasswordEncryptionKey *string
-		if keyExists && !usingAES {
-			currentPasswordEncryptionKey = &encryptionKey
-		} else {
-			currentPasswordEncryptionKey = nil
+		rotationInterval, _, err := getLTPAKeysRotationPolicy()
+		if err != nil {
+			return "", "", leaderName, 0, err
 		}
-
-		var currentAESEncryptionKey *string
-		if keyExists && usingAES {
-			currentAESEncryptionKey = &encryptionKey
+		var lastRotation string
+		if groupSecret != nil {
+			lastRotation, err = r.writeLTPAKeyGroupKeys(instance, ltpaSecret, ltpaMetadata, groupSecret)
 		} else {
-			currentAESEncryptionKey = nil
+			lastRotation, err = r.writeLTPAKeys(instance, ltpaSecret, ltpaMetadata, nil)
 		}
-
-		rawLTPAKeysStringData, err := createLTPAKeys(password, currentPasswordEncryptionKey, currentAESEncryptionKey, common.LoadFromConfig(common.Config, lutils.OpConfigPasswordEncodingType))
 		if err != nil {
-			return "", "", "", err
+			return "", "", "", 0, err
+		}
+		return ltpaSecret.Name, lastRotation, leaderName, getLTPAKeysRequeueAfter(lastRotation, rotationInterval, string(ltpaSecret.Data["validationKeysValidUntil"]), time.Now()), nil
+	} else if err != nil {
+		return "", "", "", 0, err
+	}
+	leaderName, thisInstanceIsLeader, _, err := r.reconcileLeader(instance, ltpaMetadata, LTPA_RESOURCE_SHARING_FILE_NAME, true)
+	if err != nil {
+		return "", "", leaderName, 0, err
+	}
+	lastRotation := string(ltpaSecret.Data["lastRotation"])
+	// Only the leader rotates the LTPA keys, the other instances pick up the new keys when lastRotation changes
+	if !thisInstanceIsLeader {
+		return ltpaSecret.Name, lastRotation, leaderName, 0, nil
+	}
+	// The LTPA keys of an OpenLibertyLTPAKeyGroup that the namespace is a member of are adopted instead, since the group rotates them
+	groupSecret, err := r.getLTPAKeyGroupSecret(instance, ltpaMetadata)
+	if err != nil {
+		return ltpaSecret.Name, lastRotation, leaderName, 0, err
+	}
+	rotationInterval, validationPeriod, err := getLTPAKeysRotationPolicy()
+	if err != nil {
+		return ltpaSecret.Name, lastRotation, leaderName, 0, err
+	}
+	now := time.Now()
+	if groupSecret != nil {
+		if string(groupSecret.Data["lastRotation"]) != lastRotation {
+			adoptedLastRotation, err := r.writeLTPAKeyGroupKeys(instance, ltpaSecret, ltpaMetadata, groupSecret)
+			if err != nil {
+				return ltpaSecret.Name, lastRotation, leaderName, 0, err
+			}
+			lastRotation = adoptedLastRotation
 		}
-		ltpaKeysStringData, err := base64.StdEncoding.DecodeString(string(rawLTPAKeysStringData))
+	} else {
+		rotationDue, err := isLTPAKeysRotationDue(lastRotation, rotationInterval, now)
 		if err != nil {
-			return "", "", "", err
+			return ltpaSecret.Name, lastRotation, leaderName, 0, err
+		}
+		// The LTPA keys of a group that no longer mirrors them into the namespace are not rotated by the group anymore, so the namespace rotates them now
+		if _, adoptedGroupKeys := ltpaSecret.Data[ltpaKeyGroupDataKey]; rotationDue || adoptedGroupKeys {
+			rotatedLastRotation, err := r.writeLTPAKeys(instance, ltpaSecret, ltpaMetadata, getLTPAValidationKeys(ltpaSecret, validationPeriod, now))
+			if err != nil {
+				return ltpaSecret.Name, lastRotation, leaderName, 0, err
+			}
+			lastRotation = rotatedLastRotation
 		}
+	}
+	// Liberty no longer accepts the LTPA tokens signed with the validation keys after validationKeysValidUntil, so they are removed from the LTPA Secret
+	if data := getUnexpiredLTPAKeysData(ltpaSecret, now); data != nil {
+		if err := r.saveLTPAKeys(ltpaSecret, ltpaMetadata, data); err != nil {
+			return ltpaSecret.Name, lastRotation, leaderName, 0, err
+		}
+	}
+	return ltpaSecret.Name, lastRotation, leaderName, getLTPAKeysRequeueAfter(lastRotation, rotationInterval, string(ltpaSecret.Data["validationKeysValidUntil"]), now), nil
+}
 
-		ltpaSecret.Labels[lutils.ResourcePathIndexLabel] = ltpaMetadata.PathIndex
-		ltpaSecret.Data = make(map[string][]byte)
-		if keyExists && encryptionKeyLastRotation != "" {
-			ltpaSecret.Data["encryptionKeyLastRotation"] = []byte(encryptionKeyLastRotation)
+// Generates new LTPA keys and a new password into the LTPA Secret, together with the validationKeys fields, and returns the new last rotation time
+func (r *ReconcileOpenLiberty) writeLTPAKeys(instance *olv1.OpenLibertyApplication, ltpaSecret *corev1.Secret, ltpaMetadata *lutils.LTPAMetadata, validationKeys map[string][]byte) (string, error) {
+	passwordEncryptionMetadata := &lutils.PasswordEncryptionMetadata{Name: ""}
+
+	// Check the aes/password encryption key
+	encryptionKey, encryptionKeyLastRotation, encryptionKeySharingEnabled, _, err := r.getInternalEncryptionKeyState(instance, passwordEncryptionMetadata)
+	if encryptionKeySharingEnabled && err != nil {
+		return "", err
+	}
+	keyExists := encryptionKey != ""
+	password := lutils.GetRandomAlphanumeric(15)
+
+	ltpaKeysStringData, err := createLTPAKeys(password)
+	if err != nil {
+		return "", err
+	}
+
+	data := make(map[string][]byte)
+	for key, value := range validationKeys {
+		data[key] = value
+	}
+	if keyExists && encryptionKeyLastRotation != "" {
+		data["encryptionKeyLastRotation"] = []byte(encryptionKeyLastRotation)
+	}
+	lastRotation := strconv.FormatInt(time.Now().Unix(), 10)
+	data["lastRotation"] = []byte(lastRotation)
+	data["rawPassword"] = []byte(password)
+	data[lutils.LTPAKeysFileName] = ltpaKeysStringData
+
+	if err := r.saveLTPAKeys(ltpaSecret, ltpaMetadata, data); err != nil {
+		return "", err
+	}
+	metrics.CountKeyRotation(LTPA_RESOURCE_SHARING_FILE_NAME)
+	return lastRotation, nil
+}
+
+// Copies the LTPA keys of the OpenLibertyLTPAKeyGroup in groupSecret into the LTPA Secret, so that the LTPA config of the namespace is generated from them
+// like from generated LTPA keys, and returns the last rotation time of the group LTPA keys
+func (r *ReconcileOpenLiberty) writeLTPAKeyGroupKeys(instance *olv1.OpenLibertyApplication, ltpaSecret *corev1.Secret, ltpaMetadata *lutils.LTPAMetadata, groupSecret *corev1.Secret) (string, error) {
+	passwordEncryptionMetadata := &lutils.PasswordEncryptionMetadata{Name: ""}
+	encryptionKey, encryptionKeyLastRotation, encryptionKeySharingEnabled, _, err := r.getInternalEncryptionKeyState(instance, passwordEncryptionMetadata)
+	if encryptionKeySharingEnabled && err != nil {
+		return "", err
+	}
+
+	_, validationPeriod, err := getLTPAKeysRotationPolicy()
+	if err != nil {
+		return "", err
+	}
+	data := getLTPAKeyGroupAdoptionData(ltpaSecret, groupSecret, validationPeriod, time.Now())
+	if encryptionKey != "" && encryptionKeyLastRotation != "" {
+		data["encryptionKeyLastRotation"] = []byte(encryptionKeyLastRotation)
+	}
+	if err := r.saveLTPAKeys(ltpaSecret, ltpaMetadata, data); err != nil {
+		return "", err
+	}
+	return string(data["lastRotation"]), nil
+}
+
+// Returns the data of the LTPA Secret that adopts the LTPA keys of the OpenLibertyLTPAKeyGroup in groupSecret. When the namespace first adopts the keys of the group,
+// the LTPA keys that the namespace generated itself are kept as validation keys instead of the validation keys of the group, so that the LTPA tokens signed
+// with them stay valid for the validation period.
+func getLTPAKeyGroupAdoptionData(ltpaSecret *corev1.Secret, groupSecret *corev1.Secret, validationPeriod time.Duration, now time.Time) map[string][]byte {
+	data := make(map[string][]byte)
+	for _, key := range ltpaKeyGroupDataKeys {
+		if value, found := groupSecret.Data[key]; found {
+			data[key] = value
 		}
-		lastRotation := strconv.FormatInt(time.Now().Unix(), 10)
-		ltpaSecret.Data["lastRotation"] = []byte(lastRotation)
-		ltpaSecret.Data["rawPassword"] = []byte(password)
-		ltpaSecret.Data[lutils.LTPAKeysFileName] = ltpaKeysStringData
+	}
+	data[ltpaKeyGroupDataKey] = []byte(groupSecret.Annotations[ltpaKeyGroupAnnotation])
+	// the LTPA keys of the namespace are the previous keys of the group once the namespace has adopted the keys of the group
+	keys, found := ltpaSecret.Data[lutils.LTPAKeysFileName]
+	if !found || bytes.Equal(keys, groupSecret.Data[lutils.LTPAKeysFileName]) || bytes.Equal(keys, groupSecret.Data[lutils.LTPAValidationKeysFileName]) {
+		return data
+	}
+	for key, value := range getLTPAValidationKeys(ltpaSecret, validationPeriod, now) {
+		data[key] = value
+	}
+	return data
+}
 
-		if err := r.CreateOrUpdate(ltpaSecret, nil, func() error {
-			return nil
-		}); err != nil {
-			return "", "", "", err
+func (r *ReconcileOpenLiberty) saveLTPAKeys(ltpaSecret *corev1.Secret, ltpaMetadata *lutils.LTPAMetadata, data map[string][]byte) error {
+	return r.CreateOrUpdate(ltpaSecret, nil, func() error {
+		if ltpaSecret.Labels == nil {
+			ltpaSecret.Labels = make(map[string]string)
 		}
-		return ltpaSecret.Name, lastRotation, leaderName, nil
-	} else if err != nil {
-		return "", "", "", err
+		ltpaSecret.Labels[lutils.ResourcePathIndexLabel] = ltpaMetadata.PathIndex
+		ltpaSecret.Data = data
+		return nil
+	})
+}
+
+// Returns the LTPA keys that an OpenLibertyLTPAKeyGroup mirrors into the namespace of the instance, or nil if the namespace is not a member of a group
+func (r *ReconcileOpenLiberty) getLTPAKeyGroupSecret(instance *olv1.OpenLibertyApplication, ltpaMetadata *lutils.LTPAMetadata) (*corev1.Secret, error) {
+	groupSecret := &corev1.Secret{}
+	if err := r.GetClient().Get(context.TODO(), types.NamespacedName{Name: ltpaKeyGroupSecretRootName, Namespace: instance.GetNamespace()}, groupSecret); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	// the group labels its LTPA keys with the path index of the LTPA keys in the decision tree of the operator that generated them
+	if groupSecret.Labels[lutils.ResourcePathIndexLabel] != ltpaMetadata.PathIndex {
+		return nil, fmt.Errorf("Waiting for the OpenLibertyLTPAKeyGroup '%s' to update the shared LTPA keys for the namespace '%s'.", groupSecret.Annotations[ltpaKeyGroupAnnotation], instance.Namespace)
+	}
+	if _, found := groupSecret.Data["lastRotation"]; !found {
+		return nil, fmt.Errorf("the LTPA keys of the OpenLibertyLTPAKeyGroup '%s' do not contain field 'lastRotation'", groupSecret.Annotations[ltpaKeyGroupAnnotation])
+	}
+	return groupSecret, nil
+}
+
+// Returns the interval to rotate the LTPA keys at and the period that the previous LTPA keys stay valid for after a rotation, set in the operator ConfigMap
+func getLTPAKeysRotationPolicy() (time.Duration, time.Duration, error) {
+	return parseLTPAKeysRotationPolicy(common.LoadFromConfig(common.Config, lutils.OpConfigLTPAKeysRotationInterval),
+		common.LoadFromConfig(common.Config, lutils.OpConfigLTPAKeysValidationPeriod))
+}
+
+// Parses the LTPA keys rotation interval and validation period. An empty or zero rotation interval disables the rotation of the LTPA keys.
+func parseLTPAKeysRotationPolicy(rotationInterval string, validationPeriod string) (time.Duration, time.Duration, error) {
+	interval, err := parseNonNegativeDuration(lutils.OpConfigLTPAKeysRotationInterval, rotationInterval)
+	if err != nil {
+		return 0, 0, err
 	}
-	leaderName, _, _, err := r.reconcileLeader(instance, ltpaMetadata, LTPA_RESOURCE_SHARING_FILE_NAME, true)
+	period, err := parseNonNegativeDuration(lutils.OpConfigLTPAKeysValidationPeriod, validationPeriod)
 	if err != nil {
-		return "", "", leaderName, err
+		return 0, 0, err
 	}
-	lastRotation := string(ltpaSecret.Data["lastRotation"])
-	return ltpaSecret.Name, lastRotation, leaderName, nil
+	return interval, period, nil
+}
+
+func parseNonNegativeDuration(configKey string, value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("the operator ConfigMap field '%s' must be a non-negative duration such as '720h', but is '%s'", configKey, value)
+	}
+	return duration, nil
+}
+
+// Returns true if the LTPA keys that were last rotated at the Unix time lastRotation must be rotated at now
+func isLTPAKeysRotationDue(lastRotation string, rotationInterval time.Duration, now time.Time) (bool, error) {
+	if rotationInterval <= 0 {
+		return false, nil
+	}
+	lastRotationTime, err := strconv.ParseInt(lastRotation, 10, 64)
+	if err != nil {
+		return false, fmt.Errorf("failed to convert the LTPA keys last rotation time '%s' to an integer", lastRotation)
+	}
+	return !now.Before(time.Unix(lastRotationTime, 0).Add(rotationInterval)), nil
+}
+
+// Returns the fields that keep the current LTPA keys in the LTPA Secret as validation keys until validationPeriod after now, so that
+// Liberty still accepts the LTPA tokens that were signed with them. LTPA Secrets created by operator version 1.3.3 do not have
+// the rawPassword field, so their keys can't be kept as validation keys.
+func getLTPAValidationKeys(ltpaSecret *corev1.Secret, validationPeriod time.Duration, now time.Time) map[string][]byte {
+	keys, foundKeys := ltpaSecret.Data[lutils.LTPAKeysFileName]
+	rawPassword, foundRawPassword := ltpaSecret.Data["rawPassword"]
+	if validationPeriod <= 0 || !foundKeys || !foundRawPassword {
+		return nil
+	}
+	return map[string][]byte{
+		lutils.LTPAValidationKeysFileName: keys,
+		"validationRawPassword":           rawPassword,
+		"validationKeysValidUntil":        []byte(now.Add(validationPeriod).UTC().Format(time.RFC3339)),
+	}
+}
+
+// Returns the data of the LTPA Secret without the validation keys if they expired at now, or nil if the LTPA Secret has no expired validation keys
+func getUnexpiredLTPAKeysData(ltpaSecret *corev1.Secret, now time.Time) map[string][]byte {
+	validUntil, err := time.Parse(time.RFC3339, string(ltpaSecret.Data["validationKeysValidUntil"]))
+	if err != nil || now.Before(validUntil) {
+		return nil
+	}
+	data := make(map[string][]byte)
+	for key, value := range ltpaSecret.Data {
+		if key != lutils.LTPAValidationKeysFileName && key != "validationRawPassword" && key != "validationKeysValidUntil" {
+			data[key] = value
+		}
+	}
+	return data
+}
+
+// Returns the time from now until the LTPA keys that were last rotated at the Unix time lastRotation are due for rotation, or until their validation keys
+// expire at validationKeysValidUntil, whichever comes first. Returns 0 if neither is scheduled.
+func getLTPAKeysRequeueAfter(lastRotation string, rotationInterval time.Duration, validationKeysValidUntil string, now time.Time) time.Duration {
+	var requeueAfter time.Duration
+	requeueAt := func(t time.Time) {
+		if untilT := max(t.Sub(now), time.Second); requeueAfter == 0 || untilT < requeueAfter {
+			requeueAfter = untilT
+		}
+	}
+	if lastRotationTime, err := strconv.ParseInt(lastRotation, 10, 64); err == nil && rotationInterval > 0 {
+		requeueAt(time.Unix(lastRotationTime, 0).Add(rotationInterval))
+	}
+	if validUntil, err := time.Parse(time.RFC3339, validationKeysValidUntil); err == nil {
+		requeueAt(validUntil)
+	}
+	return requeueAfter
 }
 
 // Generates the LTPA keys file and returns the name of the Secret storing its metadata
@@ -427,48 +656,12 @@ func (r *ReconcileOpenLiberty) generateLTPAConfig(instance *olv1.OpenLibertyAppl
 			}
 
 		} else { // otherwise, create the LTPA Config
-			password := string(ltpaSecret.Data["rawPassword"])
-
-			// Check the aes/password encryption key
-			encryptionKey, encryptionKeyLastRotation, encryptionKeySharingEnabled, usingAES, err := r.getInternalEncryptionKeyState(instance, passwordEncryptionMetadata)
-			if encryptionKeySharingEnabled && err != nil {
-				return "", err
-			}
-
-			keyExists := encryptionKey != ""
-			var currentPasswordEncryptionKey *string
-			if keyExists && !usingAES {
-				currentPasswordEncryptionKey = &encryptionKey
-			} else {
-				currentPasswordEncryptionKey = nil
-			}
-
-			var currentAESEncryptionKey *string
-			if keyExists && usingAES {
-				currentAESEncryptionKey = &encryptionKey
-			} else {
-				currentAESEncryptionKey = nil
-			}
-
-			encodedPassword, err := encode(password, currentPasswordEncryptionKey, currentAESEncryptionKey, common.LoadFromConfig(common.Config, lutils.OpConfigPasswordEncodingType))
+			ltpaConfigData, err := r.encodeLTPAConfigData(instance, ltpaSecret, passwordEncryptionMetadata)
 			if err != nil {
-				var encodeErrorMessage string
-				if usingAES {
-					encodeErrorMessage = "failed to encode using the aes encryption key, verify the provided key in Secret 'wlp-aes-encryption-key' is a valid base64 encoded AES-256 key"
-				} else {
-					encodeErrorMessage = "failed to encode using the password encryption key"
-				}
-				return "", fmt.Errorf("%s: %+v", encodeErrorMessage, err)
+				return "", err
 			}
-
 			ltpaConfigSecret.Labels[lutils.ResourcePathIndexLabel] = ltpaConfigMetadata.PathIndex
-			ltpaConfigSecret.Data = make(map[string][]byte)
-			if keyExists && encryptionKeyLastRotation != "" {
-				ltpaConfigSecret.Data["encryptionKeyLastRotation"] = []byte(encryptionKeyLastRotation)
-			}
-			ltpaConfigSecret.Data["lastRotation"] = []byte(ltpaSecret.Data["lastRotation"])
-			ltpaConfigSecret.Data["password"] = encodedPassword
-
+			ltpaConfigSecret.Data = ltpaConfigData
 			if err := r.CreateOrUpdate(ltpaConfigSecret, nil, func() error {
 				return nil
 			}); err != nil {
@@ -479,9 +672,9 @@ func (r *ReconcileOpenLiberty) generateLTPAConfig(instance *olv1.OpenLibertyAppl
 		return ltpaXMLSecret.Name, err
 	}
 
-	// if the LTPA password is outdated from the LTPA Secret, delete the LTPA password
+	// if the LTPA password is outdated from the LTPA Secret or the expired validation keys were removed from the LTPA Secret, delete the LTPA password
 	lastRotation, found := ltpaConfigSecret.Data["lastRotation"]
-	if !found || string(lastRotation) != string(ltpaKeysLastRotation) {
+	if !found || string(lastRotation) != string(ltpaKeysLastRotation) || string(ltpaConfigSecret.Data["validationKeysValidUntil"]) != string(ltpaSecret.Data["validationKeysValidUntil"]) {
 		// lastRotation field is not present so the Secret was not initialized correctly
 		err := r.DeleteResource(ltpaConfigSecret)
 		if err != nil {
@@ -508,45 +701,119 @@ func (r *ReconcileOpenLiberty) generateLTPAConfig(instance *olv1.OpenLibertyAppl
 			}
 			return ltpaXMLSecret.Name, fmt.Errorf("the internal encryption key secret does not contain field 'lastRotation'")
 		}
-		if encryptionKeyLastRotation, found := ltpaConfigSecret.Data["encryptionKeyLastRotation"]; found {
-			if string(encryptionKeyLastRotation) != string(lastRotation) {
-				err := r.DeleteResource(ltpaConfigSecret)
-				if err != nil {
+		// the encryption key has been added or rotated, so re-encode the LTPA passwords with the new key before the pods are rolled out with it
+		if encryptionKeyLastRotation, found := ltpaConfigSecret.Data["encryptionKeyLastRotation"]; !found || string(encryptionKeyLastRotation) != string(lastRotation) {
+			// LTPA Secrets created by operator 1.3.3 have no rawPassword to re-encode, so the LTPA password is generated again through the 1.3.3 patch above
+			if _, foundRawPassword := ltpaSecret.Data["rawPassword"]; !foundRawPassword {
+				if err := r.DeleteResource(ltpaConfigSecret); err != nil {
 					return ltpaXMLSecret.Name, err
 				}
 				return ltpaXMLSecret.Name, fmt.Errorf("the encryption key has been modified; waiting for a new LTPA password to be generated")
 			}
+			ltpaConfigData, err := r.encodeLTPAConfigData(instance, ltpaSecret, passwordEncryptionMetadata)
+			if err != nil {
+				return ltpaXMLSecret.Name, err
+			}
+			if err := r.CreateOrUpdate(ltpaConfigSecret, nil, func() error {
+				ltpaConfigSecret.Data = ltpaConfigData
+				return nil
+			}); err != nil {
+				return ltpaXMLSecret.Name, err
+			}
 		}
 	}
 
+	if err := r.writeLTPAServerXML(instance, ltpaXMLSecret, ltpaXMLMountSecret, ltpaConfigSecret.Data); err != nil {
+		return ltpaXMLSecret.Name, err
+	}
+	return ltpaXMLSecret.Name, nil
+}
+
+// Returns the data of the LTPA password Secret, with the passwords of the LTPA keys and validation keys in ltpaSecret encoded with the current password encryption key
+func (r *ReconcileOpenLiberty) encodeLTPAConfigData(instance *olv1.OpenLibertyApplication, ltpaSecret *corev1.Secret, passwordEncryptionMetadata *lutils.PasswordEncryptionMetadata) (map[string][]byte, error) {
+	password := string(ltpaSecret.Data["rawPassword"])
+
+	// Check the aes/password encryption key
+	encryptionKey, encryptionKeyLastRotation, encryptionKeySharingEnabled, usingAES, err := r.getInternalEncryptionKeyState(instance, passwordEncryptionMetadata)
+	if encryptionKeySharingEnabled && err != nil {
+		return nil, err
+	}
+
+	keyExists := encryptionKey != ""
+	var currentPasswordEncryptionKey *string
+	if keyExists && !usingAES {
+		currentPasswordEncryptionKey = &encryptionKey
+	} else {
+		currentPasswordEncryptionKey = nil
+	}
+
+	var currentAESEncryptionKey *string
+	if keyExists && usingAES {
+		currentAESEncryptionKey = &encryptionKey
+	} else {
+		currentAESEncryptionKey = nil
+	}
+
+	encodedPassword, err := encode(password, currentPasswordEncryptionKey, currentAESEncryptionKey, common.LoadFromConfig(common.Config, lutils.OpConfigPasswordEncodingType))
+	if err != nil {
+		var encodeErrorMessage string
+		if usingAES {
+			encodeErrorMessage = "failed to encode using the aes encryption key, verify the provided key in Secret 'wlp-aes-encryption-key' is a valid base64 encoded AES-256 key"
+		} else {
+			encodeErrorMessage = "failed to encode using the password encryption key"
+		}
+		return nil, fmt.Errorf("%s: %+v", encodeErrorMessage, err)
+	}
+
+	ltpaConfigData := make(map[string][]byte)
+	if keyExists && encryptionKeyLastRotation != "" {
+		ltpaConfigData["encryptionKeyLastRotation"] = []byte(encryptionKeyLastRotation)
+	}
+	ltpaConfigData["lastRotation"] = []byte(ltpaSecret.Data["lastRotation"])
+	ltpaConfigData["password"] = encodedPassword
+	// the previous LTPA keys are kept as validation keys after a rotation, so their password is encoded as well
+	if validationRawPassword, found := ltpaSecret.Data["validationRawPassword"]; found {
+		encodedValidationPassword, err := encode(string(validationRawPassword), currentPasswordEncryptionKey, currentAESEncryptionKey, common.LoadFromConfig(common.Config, lutils.OpConfigPasswordEncodingType))
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode the password of the LTPA validation keys: %+v", err)
+		}
+		ltpaConfigData["validationPassword"] = encodedValidationPassword
+		ltpaConfigData["validationKeysValidUntil"] = []byte(ltpaSecret.Data["validationKeysValidUntil"])
+	}
+	return ltpaConfigData, nil
+}
+
+// Creates or updates the Secrets with the Liberty server XML that imports the LTPA keys using the encoded passwords in ltpaConfigData.
+// The XML Secret is labeled with the latest rotation time of the LTPA keys and of the encryption key.
+func (r *ReconcileOpenLiberty) writeLTPAServerXML(instance *olv1.OpenLibertyApplication, ltpaXMLSecret *corev1.Secret, ltpaXMLMountSecret *corev1.Secret, ltpaConfigData map[string][]byte) error {
 	// Create/update the Secret to hold the server.xml that will import the LTPA keys into the Liberty server
 	// This server.xml will be mounted in /config/configDropins/overrides/ltpaKeysMount.xml
 	serverXMLMountSecretErr := r.GetClient().Get(context.TODO(), types.NamespacedName{Name: ltpaXMLMountSecret.Name, Namespace: ltpaXMLMountSecret.Namespace}, ltpaXMLMountSecret)
 	if serverXMLMountSecretErr != nil && !kerrors.IsNotFound(serverXMLMountSecretErr) {
-		return ltpaXMLSecret.Name, serverXMLMountSecretErr
+		return serverXMLMountSecretErr
 	}
 	if err := r.CreateOrUpdate(ltpaXMLMountSecret, nil, func() error {
 		mountDir := strings.Replace(lutils.SecureMountPath+"/"+lutils.LTPAKeysXMLFileName, "/output", "${server.output.dir}", 1)
 		return lutils.CustomizeLibertyFileMountXML(ltpaXMLMountSecret, lutils.LTPAKeysMountXMLFileName, mountDir)
 	}); err != nil {
-		return ltpaXMLSecret.Name, err
+		return err
 	}
 
 	// Create/update the Liberty Server XML Secret
 	serverXMLSecretErr := r.GetClient().Get(context.TODO(), types.NamespacedName{Name: ltpaXMLSecret.Name, Namespace: ltpaXMLSecret.Namespace}, ltpaXMLSecret)
 	if serverXMLSecretErr != nil && !kerrors.IsNotFound(serverXMLSecretErr) {
-		return ltpaXMLSecret.Name, serverXMLSecretErr
+		return serverXMLSecretErr
 	}
 	// NOTE: Update is important here for compatibility with an operator upgrade from version 1,3,3 that did not use ltpaXMLMountSecret
-	if err := r.CreateOrUpdate(ltpaXMLSecret, nil, func() error {
+	return r.CreateOrUpdate(ltpaXMLSecret, nil, func() error {
 		// get the latest config rotation time, if it exists
 		var latestRotationTime int
-		lastRotationTime, err := strconv.Atoi(string(lastRotation))
+		lastRotationTime, err := strconv.Atoi(string(ltpaConfigData["lastRotation"]))
 		if err != nil {
 			return fmt.Errorf("failed to convert last rotation time from string to integer")
 		}
 		latestRotationTime = lastRotationTime
-		if encryptionKeyLastRotation, found := ltpaConfigSecret.Data["encryptionKeyLastRotation"]; found {
+		if encryptionKeyLastRotation, found := ltpaConfigData["encryptionKeyLastRotation"]; found {
 			encryptionKeyLastRotationTime, err := strconv.Atoi(string(encryptionKeyLastRotation))
 			if err != nil {
 				return fmt.Errorf("failed to convert encryption key last rotation time from string to integer")
@@ -556,14 +823,15 @@ func (r *ReconcileOpenLiberty) generateLTPAConfig(instance *olv1.OpenLibertyAppl
 			}
 		}
 		ltpaXMLSecret.Labels[lutils.GetLastRotationLabelKey(LTPA_CONFIG_RESOURCE_SHARING_FILE_NAME)] = strconv.Itoa(latestRotationTime)
-		return lutils.CustomizeLTPAServerXML(ltpaXMLSecret, instance, string(ltpaConfigSecret.Data["password"]))
-	}); err != nil {
-		return ltpaXMLSecret.Name, err
-	}
-	return ltpaXMLSecret.Name, nil
+		return lutils.CustomizeLTPAServerXML(ltpaXMLSecret, instance, string(ltpaConfigData["password"]), string(ltpaConfigData["validationPassword"]), string(ltpaConfigData["validationKeysValidUntil"]))
+	})
 }
 
+// Returns true if the instance shares the LTPA keys generated by the operator. Existing LTPA keys in .spec.ltpa.keysSecretRef take precedence over .spec.manageLTPA.
 func (r *ReconcileOpenLiberty) isLTPAKeySharingEnabled(instance *olv1.OpenLibertyApplication) bool {
+	if r.isUsingExternalLTPAKeys(instance) {
+		return false
+	}
 	if instance.GetManageLTPA() != nil && *instance.GetManageLTPA() {
 		return true
 	}
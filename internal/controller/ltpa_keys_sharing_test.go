@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"os"
 	"reflect"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	openlibertyv1 "github.com/OpenLiberty/open-liberty-operator/api/v1"
 	lutils "github.com/OpenLiberty/open-liberty-operator/utils"
@@ -563,6 +565,155 @@ func TestReconcileLeaderTrackerWhenLTPASecretsExistWithMultipleUpgradesAndDowngr
 	}
 }
 
+func TestLTPAKeysRotationPolicy(t *testing.T) {
+	interval, period, err := parseLTPAKeysRotationPolicy("720h", "24h")
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	disabledInterval, _, disabledErr := parseLTPAKeysRotationPolicy("", "")
+	_, _, invalidErr := parseLTPAKeysRotationPolicy("30d", "24h")
+	_, _, negativeErr := parseLTPAKeysRotationPolicy("720h", "-1h")
+
+	lastRotation := time.Unix(1700000000, 0)
+	lastRotationString := strconv.FormatInt(lastRotation.Unix(), 10)
+	notDue, _ := isLTPAKeysRotationDue(lastRotationString, interval, lastRotation.Add(interval-time.Second))
+	due, _ := isLTPAKeysRotationDue(lastRotationString, interval, lastRotation.Add(interval))
+	disabled, _ := isLTPAKeysRotationDue(lastRotationString, 0, lastRotation.Add(100*interval))
+	_, invalidLastRotationErr := isLTPAKeysRotationDue("", interval, lastRotation)
+
+	tests := []Test{
+		{"rotation interval", 720 * time.Hour, interval},
+		{"validation period", 24 * time.Hour, period},
+		{"rotation disabled when the interval is empty", time.Duration(0), disabledInterval},
+		{"empty policy is valid", nil, disabledErr},
+		{"interval that is not a Go duration is rejected", true, invalidErr != nil},
+		{"negative validation period is rejected", true, negativeErr != nil},
+		{"rotation not due before the interval has passed", false, notDue},
+		{"rotation due once the interval has passed", true, due},
+		{"rotation never due when disabled", false, disabled},
+		{"invalid last rotation time is rejected", true, invalidLastRotationErr != nil},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestGetLTPAValidationKeys(t *testing.T) {
+	now := time.Date(2026, time.October, 16, 12, 0, 0, 0, time.UTC)
+	ltpaSecret := &corev1.Secret{Data: map[string][]byte{
+		lutils.LTPAKeysFileName: []byte("keys"),
+		"rawPassword":           []byte("password"),
+		"lastRotation":          []byte("1700000000"),
+	}}
+	validationKeys := getLTPAValidationKeys(ltpaSecret, 24*time.Hour, now)
+	legacyValidationKeys := getLTPAValidationKeys(&corev1.Secret{Data: map[string][]byte{
+		lutils.LTPAKeysFileName: []byte("keys"),
+		"password":              []byte("{aes}encoded"),
+	}}, 24*time.Hour, now)
+
+	tests := []Test{
+		{"validation keys are the previous keys", "keys", string(validationKeys[lutils.LTPAValidationKeysFileName])},
+		{"validation keys password is the previous raw password", "password", string(validationKeys["validationRawPassword"])},
+		{"validation keys are valid for the validation period", "2026-10-17T12:00:00Z", string(validationKeys["validationKeysValidUntil"])},
+		{"no validation keys without a validation period", true, getLTPAValidationKeys(ltpaSecret, 0, now) == nil},
+		{"no validation keys for an LTPA Secret without rawPassword", true, legacyValidationKeys == nil},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestGetLTPAKeysRequeueAfter(t *testing.T) {
+	now := time.Date(2026, time.October, 16, 12, 0, 0, 0, time.UTC)
+	lastRotation := strconv.FormatInt(now.Add(-700*time.Hour).Unix(), 10)
+
+	tests := []Test{
+		{"requeue at the rotation", 20 * time.Hour, getLTPAKeysRequeueAfter(lastRotation, 720*time.Hour, "", now)},
+		{"requeue when the validation keys expire before the rotation", 6 * time.Hour, getLTPAKeysRequeueAfter(lastRotation, 720*time.Hour, "2026-10-16T18:00:00Z", now)},
+		{"requeue at the rotation before the validation keys expire", 20 * time.Hour, getLTPAKeysRequeueAfter(lastRotation, 720*time.Hour, "2026-10-20T12:00:00Z", now)},
+		{"requeue when the validation keys expire without rotation", 6 * time.Hour, getLTPAKeysRequeueAfter(lastRotation, 0, "2026-10-16T18:00:00Z", now)},
+		{"requeue soon when the rotation is overdue", time.Second, getLTPAKeysRequeueAfter(lastRotation, 24*time.Hour, "", now)},
+		{"no requeue without rotation or validation keys", time.Duration(0), getLTPAKeysRequeueAfter(lastRotation, 0, "", now)},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestGetUnexpiredLTPAKeysData(t *testing.T) {
+	now := time.Date(2026, time.October, 16, 12, 0, 0, 0, time.UTC)
+	ltpaSecret := func(validUntil string) *corev1.Secret {
+		return &corev1.Secret{Data: map[string][]byte{
+			lutils.LTPAKeysFileName:           []byte("keys"),
+			"rawPassword":                     []byte("password"),
+			"lastRotation":                    []byte("1700000000"),
+			lutils.LTPAValidationKeysFileName: []byte("previous-keys"),
+			"validationRawPassword":           []byte("previous-password"),
+			"validationKeysValidUntil":        []byte(validUntil),
+		}}
+	}
+
+	tests := []Test{
+		{"expired validation keys are removed", map[string][]byte{
+			lutils.LTPAKeysFileName: []byte("keys"),
+			"rawPassword":           []byte("password"),
+			"lastRotation":          []byte("1700000000"),
+		}, getUnexpiredLTPAKeysData(ltpaSecret("2026-10-16T12:00:00Z"), now)},
+		{"valid validation keys are kept", true, getUnexpiredLTPAKeysData(ltpaSecret("2026-10-16T12:00:01Z"), now) == nil},
+		{"LTPA Secret without validation keys", true, getUnexpiredLTPAKeysData(&corev1.Secret{Data: map[string][]byte{"lastRotation": []byte("1700000000")}}, now) == nil},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestGetLTPAKeyGroupAdoptionData(t *testing.T) {
+	now := time.Date(2026, time.October, 16, 12, 0, 0, 0, time.UTC)
+	groupSecret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{ltpaKeyGroupAnnotation: "group"}}, Data: map[string][]byte{
+		lutils.LTPAKeysFileName:           []byte("group-keys"),
+		"rawPassword":                     []byte("group-password"),
+		"lastRotation":                    []byte("1700000000"),
+		lutils.LTPAValidationKeysFileName: []byte("previous-group-keys"),
+		"validationRawPassword":           []byte("previous-group-password"),
+		"validationKeysValidUntil":        []byte("2026-10-16T18:00:00Z"),
+	}}
+	// the LTPA keys of the group, recording the group that the namespace adopted them from
+	adoptedGroupData := map[string][]byte{ltpaKeyGroupDataKey: []byte("group")}
+	for key, value := range groupSecret.Data {
+		adoptedGroupData[key] = value
+	}
+	namespaceKeys := &corev1.Secret{Data: map[string][]byte{
+		lutils.LTPAKeysFileName: []byte("namespace-keys"),
+		"rawPassword":           []byte("namespace-password"),
+		"lastRotation":          []byte("1600000000"),
+	}}
+	previousGroupKeys := &corev1.Secret{Data: map[string][]byte{
+		lutils.LTPAKeysFileName: []byte("previous-group-keys"),
+		"rawPassword":           []byte("previous-group-password"),
+	}}
+
+	firstAdoption := getLTPAKeyGroupAdoptionData(namespaceKeys, groupSecret, 24*time.Hour, now)
+	withoutValidationPeriod := getLTPAKeyGroupAdoptionData(namespaceKeys, groupSecret, 0, now)
+	groupRotation := getLTPAKeyGroupAdoptionData(previousGroupKeys, groupSecret, 24*time.Hour, now)
+	newSecret := getLTPAKeyGroupAdoptionData(&corev1.Secret{}, groupSecret, 24*time.Hour, now)
+
+	tests := []Test{
+		{"first adoption uses the group keys", "group-keys", string(firstAdoption[lutils.LTPAKeysFileName])},
+		{"first adoption uses the group password", "group-password", string(firstAdoption["rawPassword"])},
+		{"first adoption uses the group last rotation", "1700000000", string(firstAdoption["lastRotation"])},
+		{"first adoption keeps the namespace keys", "namespace-keys", string(firstAdoption[lutils.LTPAValidationKeysFileName])},
+		{"first adoption keeps the namespace password", "namespace-password", string(firstAdoption["validationRawPassword"])},
+		{"first adoption keeps the namespace keys for the validation period", "2026-10-17T12:00:00Z", string(firstAdoption["validationKeysValidUntil"])},
+		{"first adoption records the group", "group", string(firstAdoption[ltpaKeyGroupDataKey])},
+		{"first adoption without a validation period", adoptedGroupData, withoutValidationPeriod},
+		{"rotation of the group keys", adoptedGroupData, groupRotation},
+		{"namespace without LTPA keys", adoptedGroupData, newSecret},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
 func createReconcilerFromOpenLibertyApp(olapp *openlibertyv1.OpenLibertyApplication) *ReconcileOpenLiberty {
 	objs, s := []runtime.Object{olapp}, scheme.Scheme
 	s.AddKnownTypes(openlibertyv1.GroupVersion, olapp)
@@ -14,6 +14,7 @@ import (
 	openlibertyv1 "github.com/OpenLiberty/open-liberty-operator/api/v1"
 	lutils "github.com/OpenLiberty/open-liberty-operator/utils"
 	libertyimage "github.com/OpenLiberty/open-liberty-operator/utils/image"
+	"github.com/OpenLiberty/open-liberty-operator/utils/metrics"
 	oputils "github.com/application-stacks/runtime-component-operator/utils"
 
 	imagev1 "github.com/openshift/api/image/v1"
@@ -55,6 +56,9 @@ type ReconcileOpenLiberty struct {
 
 const applicationFinalizer = "finalizer.openlibertyapplications.apps.openliberty.io"
 
+// The controller label of the reconcile metrics of OpenLibertyApplications
+const applicationMetricsController = "openlibertyapplication"
+
 // +kubebuilder:rbac:groups=security.openshift.io,resources=securitycontextconstraints,resourceNames=restricted,verbs=use,namespace=open-liberty-operator
 // +kubebuilder:rbac:groups=apps.openliberty.io,resources=openlibertyapplications;openlibertyapplications/status;openlibertyapplications/finalizers,verbs=get;list;watch;create;update;patch;delete,namespace=open-liberty-operator
 // +kubebuilder:rbac:groups=apps,resources=deployments;statefulsets,verbs=get;list;watch;create;update;delete,namespace=open-liberty-operator
@@ -73,6 +77,7 @@ const applicationFinalizer = "finalizer.openlibertyapplications.apps.openliberty
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
 func (r *ReconcileOpenLiberty) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	defer metrics.ObserveReconcileDuration(applicationMetricsController, time.Now())
 	reqLogger := r.Log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
 	reqDebugLogger := reqLogger.V(common.LogLevelDebug)
 	reqLogger.Info("Reconcile OpenLibertyApplication - starting")
@@ -342,6 +347,7 @@ func (r *ReconcileOpenLiberty) Reconcile(ctx context.Context, request ctrl.Reque
 		if libertyVersion == "" || int(float64(secondsSinceLastPull)/60) >= imageVersionChecksRefreshIntervalMinutes {
 			pulledManifestDigest, pulledLibertyVersion, err := r.pullLibertyVersionFromManifest(reqLogger, instance, instance.Spec.ApplicationImage, image, isTagNamespace)
 			if err != nil {
+				metrics.CountImageMetadataPullFailure()
 				reqLogger.Error(err, failedToPullContainerMessage)
 				instance.Status.SetReference(lutils.StatusReferenceLibertyVersion, libertyimage.NilLibertyVersion)
 				// add a warning that displays to the user when the Liberty version couldn't be parsed
@@ -606,8 +612,14 @@ func (r *ReconcileOpenLiberty) Reconcile(ctx context.Context, request ctrl.Reque
 		return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
 	}
 
+	// Detect a rotation of the shared password encryption key by the hash of the key
+	if err := r.reconcilePasswordEncryptionKeyRotation(instance, passwordEncryptionMetadata); err != nil {
+		reqLogger.Error(err, "Failed to reconcile the rotation of the password encryption key")
+		return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
+	}
+
 	// Create and manage the shared LTPA keys Secret if the feature is enabled
-	message, ltpaSecretName, ltpaKeysLastRotation, err := r.reconcileLTPAKeys(instance, ltpaKeysMetadata)
+	message, ltpaSecretName, ltpaKeysLastRotation, ltpaKeysRequeueAfter, err := r.reconcileLTPAKeys(instance, ltpaKeysMetadata, passwordEncryptionMetadata)
 	if err != nil {
 		reqLogger.Error(err, message)
 		return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
@@ -626,6 +638,11 @@ func (r *ReconcileOpenLiberty) Reconcile(ctx context.Context, request ctrl.Reque
 		reqLogger.Error(err, message)
 		return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
 	}
+	// Roll out the pods with the new password encryption key once the operator managed passwords are re-encoded with it
+	if err := r.reconcilePasswordEncryptionKeyReEncoding(instance, ltpaConfigMetadata, passwordEncryptionMetadata); err != nil {
+		reqLogger.Error(err, "Failed to check the re-encoding of the LTPA password with the password encryption key")
+		return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
+	}
 
 	if instance.Spec.StatefulSet != nil {
 		// Delete Deployment if exists
@@ -692,7 +709,7 @@ func (r *ReconcileOpenLiberty) Reconcile(ctx context.Context, request ctrl.Reque
 				if err != nil {
 					return err
 				}
-				lutils.AddPodTemplateSpecAnnotation(&statefulSet.Spec.Template, lastRotationAnnotation)
+				addPasswordEncryptionKeyRotationAnnotation(&statefulSet.Spec.Template, instance, lastRotationAnnotation)
 				if instance.Status.GetReferences()[lutils.GetTrackedResourceName(PASSWORD_ENCRYPTION_RESOURCE_SHARING_FILE_NAME)] != encryptionSecretName {
 					instance.Status.SetReference(lutils.GetTrackedResourceName(PASSWORD_ENCRYPTION_RESOURCE_SHARING_FILE_NAME), encryptionSecretName)
 				}
@@ -701,8 +718,11 @@ func (r *ReconcileOpenLiberty) Reconcile(ctx context.Context, request ctrl.Reque
 				lutils.RemoveMapElementByKey(instance.Status.GetReferences(), lutils.GetTrackedResourceName(PASSWORD_ENCRYPTION_RESOURCE_SHARING_FILE_NAME))
 			}
 
-			if r.isLTPAKeySharingEnabled(instance) && ltpaSecretName != "" {
-				lutils.ConfigureLTPAConfig(&statefulSet.Spec.Template, instance, OperatorShortName, ltpaSecretName, ltpaConfigMetadata.Name)
+			if r.isLTPAEnabled(instance) && ltpaSecretName != "" {
+				lutils.ConfigureLTPAConfig(&statefulSet.Spec.Template, instance, OperatorShortName, ltpaSecretName, r.getLTPAConfigSuffix(instance, ltpaConfigMetadata))
+				if err := lutils.ConfigureLTPAValidationKeys(&statefulSet.Spec.Template, instance, r.GetClient(), ltpaSecretName); err != nil {
+					return err
+				}
 				// add LTPA key last rotation annotation
 				lastRotationAnnotation, err := lutils.GetSecretLastRotationAsLabelMap(instance, r.GetClient(), ltpaSecretName, LTPA_RESOURCE_SHARING_FILE_NAME)
 				if err != nil {
@@ -728,6 +748,9 @@ func (r *ReconcileOpenLiberty) Reconcile(ctx context.Context, request ctrl.Reque
 			reqLogger.Error(err, "Failed to reconcile StatefulSet")
 			return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
 		}
+		if isWorkloadRolledOut(statefulSet) {
+			setPasswordEncryptionKeyRotationPhase(instance, openlibertyv1.PasswordEncryptionKeyRotationPhaseRollingPods, openlibertyv1.PasswordEncryptionKeyRotationPhaseCompleted)
+		}
 
 	} else {
 		// Delete StatefulSet if exists
@@ -789,7 +812,7 @@ func (r *ReconcileOpenLiberty) Reconcile(ctx context.Context, request ctrl.Reque
 				if err != nil {
 					return err
 				}
-				lutils.AddPodTemplateSpecAnnotation(&deploy.Spec.Template, lastRotationAnnotation)
+				addPasswordEncryptionKeyRotationAnnotation(&deploy.Spec.Template, instance, lastRotationAnnotation)
 				if instance.Status.GetReferences()[lutils.GetTrackedResourceName(PASSWORD_ENCRYPTION_RESOURCE_SHARING_FILE_NAME)] != encryptionSecretName {
 					instance.Status.SetReference(lutils.GetTrackedResourceName(PASSWORD_ENCRYPTION_RESOURCE_SHARING_FILE_NAME), encryptionSecretName)
 				}
@@ -798,8 +821,11 @@ func (r *ReconcileOpenLiberty) Reconcile(ctx context.Context, request ctrl.Reque
 				lutils.RemoveMapElementByKey(instance.Status.GetReferences(), lutils.GetTrackedResourceName(PASSWORD_ENCRYPTION_RESOURCE_SHARING_FILE_NAME))
 			}
 
-			if r.isLTPAKeySharingEnabled(instance) && ltpaSecretName != "" {
-				lutils.ConfigureLTPAConfig(&deploy.Spec.Template, instance, OperatorShortName, ltpaSecretName, ltpaConfigMetadata.Name)
+			if r.isLTPAEnabled(instance) && ltpaSecretName != "" {
+				lutils.ConfigureLTPAConfig(&deploy.Spec.Template, instance, OperatorShortName, ltpaSecretName, r.getLTPAConfigSuffix(instance, ltpaConfigMetadata))
+				if err := lutils.ConfigureLTPAValidationKeys(&deploy.Spec.Template, instance, r.GetClient(), ltpaSecretName); err != nil {
+					return err
+				}
 				// add LTPA key last rotation annotation
 				lastRotationAnnotation, err := lutils.GetSecretLastRotationAsLabelMap(instance, r.GetClient(), ltpaSecretName, LTPA_RESOURCE_SHARING_FILE_NAME)
 				if err != nil {
@@ -825,6 +851,9 @@ func (r *ReconcileOpenLiberty) Reconcile(ctx context.Context, request ctrl.Reque
 			reqLogger.Error(err, "Failed to reconcile Deployment")
 			return r.ManageError(err, common.StatusConditionTypeReconciled, instance)
 		}
+		if isWorkloadRolledOut(deploy) {
+			setPasswordEncryptionKeyRotationPhase(instance, openlibertyv1.PasswordEncryptionKeyRotationPhaseRollingPods, openlibertyv1.PasswordEncryptionKeyRotationPhaseCompleted)
+		}
 
 	}
 
@@ -954,7 +983,18 @@ func (r *ReconcileOpenLiberty) Reconcile(ctx context.Context, request ctrl.Reque
 	instance.Status.ObservedGeneration = instance.GetObjectMeta().GetGeneration()
 	instance.Status.Versions.Reconciled = lutils.OperandVersion
 	reqLogger.Info("Reconcile OpenLibertyApplication - completed")
-	return r.ManageSuccess(common.StatusConditionTypeReconciled, instance)
+	result, err := r.ManageSuccess(common.StatusConditionTypeReconciled, instance)
+	// Requeue the LTPA keys leader when the LTPA keys are due for rotation or their validation keys expire
+	if err == nil && ltpaKeysRequeueAfter > 0 && (result.RequeueAfter == 0 || ltpaKeysRequeueAfter < result.RequeueAfter) {
+		result.RequeueAfter = ltpaKeysRequeueAfter
+	}
+	return result, err
+}
+
+// ManageError counts the reconcile error before reporting it in the status of the instance
+func (r *ReconcileOpenLiberty) ManageError(issue error, conditionType common.StatusConditionType, ba common.BaseComponent) (reconcile.Result, error) {
+	metrics.CountReconcileError(applicationMetricsController)
+	return r.ReconcilerBase.ManageError(issue, conditionType, ba)
 }
 
 func (r *ReconcileOpenLiberty) isOpenLibertyApplicationReady(ba common.BaseComponent) bool {
@@ -1092,7 +1132,7 @@ func (r *ReconcileOpenLiberty) IsLibertyVersionCheckNeeded(instance *openliberty
 	if lutils.IsLibertyVersionCheckNeeded(instance) {
 		return true
 	}
-	if instance.Spec.ManageLTPA != nil && *instance.Spec.ManageLTPA && r.isUsingAESPasswordEncryptionKeySharing(instance, nil) && !r.isUsingPlainPasswordEncryptionKeySharing(instance, nil) {
+	if r.isLTPAEnabled(instance) && r.isUsingAESPasswordEncryptionKeySharing(instance, nil) && !r.isUsingPlainPasswordEncryptionKeySharing(instance, nil) {
 		return true
 	}
 	return false
@@ -1175,9 +1215,9 @@ func (r *ReconcileOpenLiberty) checkLibertyVersionGuards(instance *openlibertyv1
 			return fmt.Errorf("Could not set .spec.probes.enableFileBased because the detected Liberty version is not running version 25.0.0.6 or higher")
 		}
 	}
-	// Only allow LTPA key creation with a BYO AES-256 encryption key on Liberty container images >=25.0.0.12
+	// Only allow generated or imported LTPA keys with a BYO AES-256 encryption key on Liberty container images >=25.0.0.12
 	// See https://openliberty.io/blog/2025/12/02/25.0.0.12.html#aes256 for additional context
-	if instance.Spec.ManageLTPA != nil && *instance.Spec.ManageLTPA && r.isUsingAESPasswordEncryptionKeySharing(instance, nil) && !r.isUsingPlainPasswordEncryptionKeySharing(instance, nil) {
+	if r.isLTPAEnabled(instance) && r.isUsingAESPasswordEncryptionKeySharing(instance, nil) && !r.isUsingPlainPasswordEncryptionKeySharing(instance, nil) {
 		isAESPasswordEncryptionKeyAllowed := lutils.CompareLibertyVersion(libertyVersion, "25.0.0.12") >= 0
 		if !isAESPasswordEncryptionKeyAllowed {
 			return fmt.Errorf("The LTPA key creation depends on an encryption feature that is not supported. Could not set .spec.managePasswordEncryption with Secret 'wlp-aes-encryption-key' because the detected Liberty version is not running version 25.0.0.12 or higher")
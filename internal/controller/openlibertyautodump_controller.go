@@ -0,0 +1,251 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	openlibertyv1 "github.com/OpenLiberty/open-liberty-operator/api/v1"
+	oputils "github.com/application-stacks/runtime-component-operator/utils"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+const (
+	// Label of the OpenLibertyDumps created by .spec.serviceability.autoDump, set to the name of the OpenLibertyApplication
+	autoDumpApplicationLabel = "openliberty.io/auto-dump-application"
+	// Annotation of the OpenLibertyDumps created by .spec.serviceability.autoDump, set to the reason of the dump
+	autoDumpReasonAnnotation = "openliberty.io/auto-dump-reason"
+
+	// Annotation of the pods of the application recording which readiness failure was already handled, so that it is
+	// not dumped again after the operator restarts
+	autoDumpNotReadySinceAnnotation = "openliberty.io/auto-dump-not-ready-since"
+
+	autoDumpReasonNotReady = "NotReady"
+
+	autoDumpRateLimitPeriod = time.Hour
+)
+
+// ReconcileOpenLibertyAutoDump watches the pods of OpenLibertyApplications and creates an OpenLibertyDump of a pod when
+// it stays not ready for .spec.serviceability.autoDump.notReadySeconds of its application. Dumps before the application
+// container is stopped or when the JVM runs out of memory are taken by the pod itself, see ConfigureServiceability.
+type ReconcileOpenLibertyAutoDump struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	Client client.Client
+	// Reads the OpenLibertyDumps of the rate limit without the delay of the cache
+	APIReader client.Reader
+	Scheme    *runtime.Scheme
+	Recorder  record.EventRecorder
+	Log       logr.Logger
+}
+
+// +kubebuilder:rbac:groups=apps.openliberty.io,resources=openlibertydumps,verbs=get;list;watch;create;update;patch;delete,namespace=open-liberty-operator
+// +kubebuilder:rbac:groups=core,resources=pods,verbs=get;list;watch;update;patch,namespace=open-liberty-operator
+
+func (r *ReconcileOpenLibertyAutoDump) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+
+	pod := &corev1.Pod{}
+	err := r.Client.Get(context.TODO(), request.NamespacedName, pod)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if pod.DeletionTimestamp != nil {
+		return ctrl.Result{}, nil
+	}
+
+	appName := pod.Labels["app.kubernetes.io/instance"]
+	app := &openlibertyv1.OpenLibertyApplication{}
+	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: appName, Namespace: pod.Namespace}, app)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if app.GetServiceability() == nil || app.GetServiceability().GetAutoDump() == nil {
+		return ctrl.Result{}, nil
+	}
+	autoDump := app.GetServiceability().GetAutoDump()
+
+	reason, requeueAfter := getAutoDumpReason(pod, autoDump, time.Now())
+	if reason == "" {
+		return ctrl.Result{RequeueAfter: requeueAfter}, nil
+	}
+	reqLogger.Info("Pod meets the conditions of the automatic dump policy", "reason", reason)
+
+	limited, err := r.isAutoDumpRateLimited(app, autoDump)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if limited {
+		r.Recorder.Event(app, "Warning", "AutoDumpRateLimited", fmt.Sprintf("Pod %s was not dumped (%s) because the application already had %d automatic dumps in the last hour", pod.Name, reason, autoDump.GetMaxDumpsPerHour()))
+	} else {
+		dump, err := r.createAutoDump(app, pod, autoDump, reason)
+		if err != nil {
+			r.Recorder.Event(app, "Warning", "ProcessingError", fmt.Sprintf("Failed to create the automatic dump of pod %s: %v", pod.Name, err))
+			return ctrl.Result{}, err
+		}
+		r.Recorder.Event(app, "Normal", "AutoDump", fmt.Sprintf("Created OpenLibertyDump %s of pod %s (%s)", dump.Name, pod.Name, reason))
+	}
+
+	// record the handled readiness failure, whether the pod was dumped or rate limited
+	patch := client.MergeFrom(pod.DeepCopy())
+	markAutoDumpHandled(pod)
+	if err := r.Client.Patch(context.TODO(), pod, patch); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
+// Returns the reason to dump the pod, or an empty reason and the time after which the pod should be checked again
+func getAutoDumpReason(pod *corev1.Pod, autoDump *openlibertyv1.OpenLibertyApplicationAutoDump, now time.Time) (string, time.Duration) {
+	container := getAppContainerStatus(pod)
+	// the server that is not ready is dumped while it is still running, before its liveness probe restarts it
+	if pod.Status.Phase != corev1.PodRunning || container == nil || container.State.Running == nil {
+		return "", 0
+	}
+
+	notReadyDuration := autoDump.GetNotReadyDuration()
+	notReadySince, notReady := getNotReadySince(pod, container)
+	if notReadyDuration == 0 || !notReady || pod.Annotations[autoDumpNotReadySinceAnnotation] == notReadySince.UTC().Format(time.RFC3339) {
+		return "", 0
+	}
+	if remaining := notReadySince.Add(notReadyDuration).Sub(now); remaining > 0 {
+		return "", remaining
+	}
+	return autoDumpReasonNotReady, 0
+}
+
+// Returns the status of the application container of the pod, or nil if it has not been created
+func getAppContainerStatus(pod *corev1.Pod) *corev1.ContainerStatus {
+	for i := range pod.Status.ContainerStatuses {
+		if pod.Status.ContainerStatuses[i].Name == "app" {
+			return &pod.Status.ContainerStatuses[i]
+		}
+	}
+	return nil
+}
+
+// Returns the time the running application container has been not ready since, or false if it is ready
+func getNotReadySince(pod *corev1.Pod, container *corev1.ContainerStatus) (time.Time, bool) {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type != corev1.PodReady || condition.Status == corev1.ConditionTrue {
+			continue
+		}
+		// the container may have restarted after the pod became not ready
+		notReadySince := condition.LastTransitionTime.Time
+		if container.State.Running.StartedAt.After(notReadySince) {
+			notReadySince = container.State.Running.StartedAt.Time
+		}
+		return notReadySince, true
+	}
+	return time.Time{}, false
+}
+
+// Records on the pod that its current readiness failure has been handled
+func markAutoDumpHandled(pod *corev1.Pod) {
+	notReadySince, _ := getNotReadySince(pod, getAppContainerStatus(pod))
+	if pod.Annotations == nil {
+		pod.Annotations = map[string]string{}
+	}
+	pod.Annotations[autoDumpNotReadySinceAnnotation] = notReadySince.UTC().Format(time.RFC3339)
+}
+
+// Returns whether the application already had the maximum number of automatic dumps in the last hour
+func (r *ReconcileOpenLibertyAutoDump) isAutoDumpRateLimited(app *openlibertyv1.OpenLibertyApplication, autoDump *openlibertyv1.OpenLibertyApplicationAutoDump) (bool, error) {
+	dumps := &openlibertyv1.OpenLibertyDumpList{}
+	err := r.APIReader.List(context.TODO(), dumps, client.InNamespace(app.Namespace), client.MatchingLabels{autoDumpApplicationLabel: app.Name})
+	if err != nil {
+		return false, err
+	}
+	return countRecentAutoDumps(dumps.Items, time.Now()) >= autoDump.GetMaxDumpsPerHour(), nil
+}
+
+// Returns the number of dumps created within the rate limit period before now
+func countRecentAutoDumps(dumps []openlibertyv1.OpenLibertyDump, now time.Time) int {
+	count := 0
+	for _, dump := range dumps {
+		if dump.CreationTimestamp.Add(autoDumpRateLimitPeriod).After(now) {
+			count++
+		}
+	}
+	return count
+}
+
+// Creates an OpenLibertyDump of the pod, owned by the application so that it is deleted with the application
+func (r *ReconcileOpenLibertyAutoDump) createAutoDump(app *openlibertyv1.OpenLibertyApplication, pod *corev1.Pod, autoDump *openlibertyv1.OpenLibertyApplicationAutoDump, reason string) (*openlibertyv1.OpenLibertyDump, error) {
+	dump := &openlibertyv1.OpenLibertyDump{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: app.Name + "-autodump-",
+			Namespace:    app.Namespace,
+			Labels:       map[string]string{autoDumpApplicationLabel: app.Name},
+			Annotations:  map[string]string{autoDumpReasonAnnotation: reason},
+		},
+		Spec: openlibertyv1.OpenLibertyDumpSpec{
+			PodName: pod.Name,
+			Include: autoDump.GetInclude(),
+		},
+	}
+	if err := controllerutil.SetOwnerReference(app, dump, r.Scheme); err != nil {
+		return nil, err
+	}
+	if err := r.Client.Create(context.TODO(), dump); err != nil {
+		return nil, err
+	}
+	return dump, nil
+}
+
+func (r *ReconcileOpenLibertyAutoDump) SetupWithManager(mgr ctrl.Manager) error {
+
+	watchNamespaces, err := oputils.GetWatchNamespaces()
+	if err != nil {
+		r.Log.Error(err, "Failed to get watch namespace")
+		os.Exit(1)
+	}
+
+	watchNamespacesMap := make(map[string]bool)
+	for _, ns := range watchNamespaces {
+		watchNamespacesMap[ns] = true
+	}
+	isClusterWide := len(watchNamespacesMap) == 1 && watchNamespacesMap[""]
+
+	isApplicationPod := func(obj client.Object) bool {
+		labels := obj.GetLabels()
+		return labels["app.kubernetes.io/instance"] != "" && labels["app.kubernetes.io/managed-by"] == OperatorName && (isClusterWide || watchNamespacesMap[obj.GetNamespace()])
+	}
+	pred := predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			return isApplicationPod(e.ObjectNew)
+		},
+		CreateFunc: func(e event.CreateEvent) bool {
+			return isApplicationPod(e.Object)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+	return ctrl.NewControllerManagedBy(mgr).Named("openlibertyapplication-autodump").For(&corev1.Pod{}, builder.WithPredicates(pred)).WithOptions(controller.Options{
+		MaxConcurrentReconciles: 1,
+	}).Complete(r)
+}
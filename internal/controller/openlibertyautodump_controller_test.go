@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	openlibertyv1 "github.com/OpenLiberty/open-liberty-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetAutoDumpReason(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+	notReadySeconds := int32(300)
+	longNotReadySeconds := int32(7200)
+	notReady := &openlibertyv1.OpenLibertyApplicationAutoDump{NotReadySeconds: &notReadySeconds}
+
+	// a running pod whose app container started an hour ago and became not ready at notReadySince
+	pod := func(restarts int32, notReadySince time.Time) *corev1.Pod {
+		return &corev1.Pod{
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{{
+					Name:         "app",
+					RestartCount: restarts,
+					State:        corev1.ContainerState{Running: &corev1.ContainerStateRunning{StartedAt: metav1.NewTime(now.Add(-time.Hour))}},
+				}},
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse, LastTransitionTime: metav1.NewTime(notReadySince)}},
+			},
+		}
+	}
+
+	restarted := pod(3, now.Add(-10*time.Minute))
+	restarted.Status.ContainerStatuses[0].LastTerminationState.Terminated = &corev1.ContainerStateTerminated{Reason: "OOMKilled"}
+	restartedReason, _ := getAutoDumpReason(restarted, &openlibertyv1.OpenLibertyApplicationAutoDump{OOMKilled: &trueValue, PreStop: &trueValue}, now)
+	ready := pod(0, time.Time{})
+	ready.Status.Conditions[0].Status = corev1.ConditionTrue
+	readyReason, _ := getAutoDumpReason(ready, notReady, now)
+	waiting := pod(1, now.Add(-10*time.Minute))
+	waiting.Status.ContainerStatuses[0].State = corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}
+	waitingReason, _ := getAutoDumpReason(waiting, notReady, now)
+	ignoredReason, _ := getAutoDumpReason(pod(0, now.Add(-10*time.Minute)), &openlibertyv1.OpenLibertyApplicationAutoDump{}, now)
+	notReadyReason, _ := getAutoDumpReason(pod(0, now.Add(-10*time.Minute)), notReady, now)
+	handled := pod(0, now.Add(-10*time.Minute))
+	markAutoDumpHandled(handled)
+	handledReason, _ := getAutoDumpReason(handled, notReady, now)
+	belowThresholdReason, belowThresholdRequeue := getAutoDumpReason(pod(0, now.Add(-time.Minute)), notReady, now)
+	// the not ready time is counted from the start of the container when it restarted while not ready
+	sinceRestartReason, _ := getAutoDumpReason(pod(1, now.Add(-2*time.Hour)), &openlibertyv1.OpenLibertyApplicationAutoDump{NotReadySeconds: &longNotReadySeconds}, now)
+
+	tests := []Test{
+		{"restarts are dumped by the pod", "", restartedReason},
+		{"ready", "", readyReason},
+		{"container not running", "", waitingReason},
+		{"not ready ignored", "", ignoredReason},
+		{"not ready", autoDumpReasonNotReady, notReadyReason},
+		{"not ready annotation", "2024-01-01T11:50:00Z", handled.Annotations[autoDumpNotReadySinceAnnotation]},
+		{"not ready handled", "", handledReason},
+		{"not ready below threshold", "", belowThresholdReason},
+		{"not ready requeue", 4 * time.Minute, belowThresholdRequeue},
+		{"not ready since restart", "", sinceRestartReason},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestCountRecentAutoDumps(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+	tests := []Test{
+		{"recent auto dumps", 2, countRecentAutoDumps([]openlibertyv1.OpenLibertyDump{
+			{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now.Add(-2 * time.Hour))}},
+			{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now.Add(-59 * time.Minute))}},
+			{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now.Add(-time.Minute))}},
+		}, now)},
+		{"no auto dumps", 0, countRecentAutoDumps(nil, now)},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
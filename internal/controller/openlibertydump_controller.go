@@ -2,16 +2,24 @@ package controller
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/OpenLiberty/open-liberty-operator/utils"
+	"github.com/OpenLiberty/open-liberty-operator/utils/metrics"
+	"github.com/OpenLiberty/open-liberty-operator/utils/s3"
 	oputils "github.com/application-stacks/runtime-component-operator/utils"
 	"github.com/go-logr/logr"
+	"github.com/robfig/cron/v3"
 
 	openlibertyv1 "github.com/OpenLiberty/open-liberty-operator/api/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/rest"
@@ -25,6 +33,11 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
+const dumpFinalizer = "finalizer.openlibertydumps.apps.openliberty.io"
+
+// Number of scheduled dumps kept in the status history when .spec.retentionCount is not set
+const defaultDumpHistoryLimit = 10
+
 // ReconcileOpenLibertyDump reconciles an OpenLibertyDump object
 type ReconcileOpenLibertyDump struct {
 	// This client, initialized using mgr.Client() above, is a split client
@@ -63,26 +76,38 @@ func (r *ReconcileOpenLibertyDump) Reconcile(ctx context.Context, request ctrl.R
 		return reconcile.Result{}, err
 	}
 
-	//do not reconcile if the dump already started
-	oc := openlibertyv1.GetOperationCondtion(instance.Status.Conditions, openlibertyv1.OperationStatusConditionTypeStarted)
-	if oc != nil && oc.Status == corev1.ConditionTrue {
-		return reconcile.Result{}, err
+	// Check if the OpenLibertyDump instance is marked to be deleted, which is
+	// indicated by the deletion timestamp being set.
+	isInstanceMarkedToBeDeleted := instance.GetDeletionTimestamp() != nil
+	if isInstanceMarkedToBeDeleted {
+		if utils.Contains(instance.GetFinalizers(), dumpFinalizer) {
+			r.finalizeOpenLibertyDump(reqLogger, instance)
+
+			// Remove dumpFinalizer. Once all finalizers have been removed, the object will be deleted.
+			instance.SetFinalizers(utils.Remove(instance.GetFinalizers(), dumpFinalizer))
+			err := r.Client.Update(context.TODO(), instance)
+			if err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		return reconcile.Result{}, nil
 	}
 
-	//check if Pod exists and running
-	pod := &corev1.Pod{}
+	// Add finalizer for this CR
+	if !utils.Contains(instance.GetFinalizers(), dumpFinalizer) {
+		if err := r.addFinalizer(reqLogger, instance); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
 
-	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: instance.Spec.PodName, Namespace: request.Namespace}, pod)
-	if err != nil || pod.Status.Phase != corev1.PodRunning {
-		//handle error
-		message := "Failed to find pod " + instance.Spec.PodName + " in namespace " + request.Namespace
-		reqLogger.Error(err, message)
-		r.Recorder.Event(instance, "Warning", "ProcessingError", message)
+	if err = validateDumpTarget(instance); err != nil {
+		reqLogger.Error(err, "Invalid dump target")
+		r.Recorder.Event(instance, "Warning", "ProcessingError", err.Error())
 		c := openlibertyv1.OperationStatusCondition{
 			Type:    openlibertyv1.OperationStatusConditionTypeStarted,
 			Status:  corev1.ConditionFalse,
-			Reason:  "Error",
-			Message: "Failed to find a pod or pod is not in running state",
+			Reason:  "InvalidTarget",
+			Message: err.Error(),
 		}
 		instance.Status.Conditions = openlibertyv1.SetOperationCondtion(instance.Status.Conditions, c)
 		instance.Status.ObservedGeneration = instance.GetObjectMeta().GetGeneration()
@@ -91,55 +116,610 @@ func (r *ReconcileOpenLibertyDump) Reconcile(ctx context.Context, request ctrl.R
 		return reconcile.Result{}, nil
 	}
 
-	currentTime := time.Now()
-	dumpFolder := "/serviceability/" + pod.Namespace + "/" + pod.Name + "/serverDumps"
-	dumpFileName := dumpFolder + "/" + "dump_" + currentTime.UTC().Format("2006.01.02_15.04.05") + "_utc.zip"
-	dumpCmd := "mkdir -p " + dumpFolder + " &&  server dump --archive=" + dumpFileName
-	if len(instance.Spec.Include) > 0 {
-		dumpCmd += " --include="
-		for i := range instance.Spec.Include {
-			dumpCmd += string(instance.Spec.Include[i]) + ","
-		}
+	if instance.Spec.Schedule != "" {
+		return r.reconcileScheduledDump(instance, reqLogger)
 	}
 
-	c := openlibertyv1.OperationStatusCondition{
-		Type:   openlibertyv1.OperationStatusConditionTypeStarted,
-		Status: corev1.ConditionTrue,
+	//do not reconcile if the dump already finished, unless a failed dump was updated so that it can be retried
+	if isDumpFinished(instance) {
+		return reconcile.Result{}, nil
+	}
+	if oc := openlibertyv1.GetOperationCondtion(instance.Status.Conditions, openlibertyv1.OperationStatusConditionTypeCompleted); oc != nil {
+		instance.Status.Conditions = openlibertyv1.RemoveOperationCondtion(instance.Status.Conditions, openlibertyv1.OperationStatusConditionTypeStarted)
+		clearWorkerResults(string(instance.UID))
 	}
 
-	instance.Status.Conditions = openlibertyv1.SetOperationCondtion(instance.Status.Conditions, c)
+	instance.Status.ObservedGeneration = instance.GetObjectMeta().GetGeneration()
+	instance.Status.Versions.Reconciled = utils.OperandVersion
+
+	if oc := openlibertyv1.GetOperationCondtion(instance.Status.Conditions, openlibertyv1.OperationStatusConditionTypeStarted); oc == nil || oc.Status != corev1.ConditionTrue {
+		if !r.startDumpRun(instance, reqLogger) {
+			r.Client.Status().Update(context.TODO(), instance)
+			return reconcile.Result{}, nil
+		}
+	}
+
+	// poll the dump workers until the dumps of all target pods have finished
+	finished := r.runInstanceDumps(instance, 0, reqLogger)
 	r.Client.Status().Update(context.TODO(), instance)
+	if !finished {
+		return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
+	}
+	return reconcile.Result{}, nil
+}
+
+// Takes server dumps each time the cron schedule in .spec.schedule activates, then requeues until the next activation
+func (r *ReconcileOpenLibertyDump) reconcileScheduledDump(instance *openlibertyv1.OpenLibertyDump, reqLogger logr.Logger) (ctrl.Result, error) {
+	instance.Status.ObservedGeneration = instance.GetObjectMeta().GetGeneration()
+	instance.Status.Versions.Reconciled = utils.OperandVersion
 
-	_, err = utils.ExecuteCommandInContainer(r.RestConfig, pod.Name, pod.Namespace, "app", []string{"/bin/sh", "-c", dumpCmd})
+	schedule, err := cron.ParseStandard(instance.Spec.Schedule)
 	if err != nil {
-		//handle error
-		reqLogger.Error(err, "Execute dump cmd failed ", "cmd", dumpCmd)
-		r.Recorder.Event(instance, "Warning", "ProcessingError", err.Error())
-		c = openlibertyv1.OperationStatusCondition{
-			Type:    openlibertyv1.OperationStatusConditionTypeCompleted,
+		message := "Failed to parse schedule " + instance.Spec.Schedule
+		reqLogger.Error(err, message)
+		r.Recorder.Event(instance, "Warning", "ProcessingError", message)
+		c := openlibertyv1.OperationStatusCondition{
+			Type:    openlibertyv1.OperationStatusConditionTypeStarted,
 			Status:  corev1.ConditionFalse,
-			Reason:  "Error",
+			Reason:  "InvalidSchedule",
 			Message: err.Error(),
 		}
 		instance.Status.Conditions = openlibertyv1.SetOperationCondtion(instance.Status.Conditions, c)
-		instance.Status.ObservedGeneration = instance.GetObjectMeta().GetGeneration()
-		instance.Status.Versions.Reconciled = utils.OperandVersion
+		instance.Status.NextScheduleTime = nil
 		r.Client.Status().Update(context.TODO(), instance)
 		return reconcile.Result{}, nil
+	}
 
+	// the dumps of an activation finish before the next activation is considered
+	if isScheduledDumpRunning(instance) {
+		if !r.runInstanceDumps(instance, instance.Status.History[0].StartTime.Unix(), reqLogger) {
+			r.Client.Status().Update(context.TODO(), instance)
+			return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
+		}
+		r.finishScheduledDump(instance, reqLogger)
 	}
 
-	c = openlibertyv1.OperationStatusCondition{
-		Type:   openlibertyv1.OperationStatusConditionTypeCompleted,
+	now := time.Now().UTC()
+	lastScheduleTime := instance.GetCreationTimestamp().Time.UTC()
+	if instance.Status.LastScheduleTime != nil {
+		lastScheduleTime = instance.Status.LastScheduleTime.Time.UTC()
+	}
+
+	// missed activations (e.g. while the operator was down) are collapsed into a single run
+	nextScheduleTime := schedule.Next(lastScheduleTime)
+	if nextScheduleTime.IsZero() || nextScheduleTime.After(now) {
+		return r.requeueScheduledDump(instance, nextScheduleTime, now)
+	}
+
+	// the start time is truncated to seconds so that it identifies the run once stored in the history
+	now = now.Truncate(time.Second)
+	instance.Status.LastScheduleTime = &metav1.Time{Time: now}
+	entry := openlibertyv1.DumpHistoryEntry{
+		StartTime: metav1.NewTime(now),
+		Status:    corev1.ConditionUnknown,
+	}
+	instance.Status.History = append([]openlibertyv1.DumpHistoryEntry{entry}, instance.Status.History...)
+	clearWorkerResults(string(instance.UID))
+	if !r.startDumpRun(instance, reqLogger) {
+		instance.Status.History[0].Status = corev1.ConditionFalse
+		if oc := openlibertyv1.GetOperationCondtion(instance.Status.Conditions, openlibertyv1.OperationStatusConditionTypeStarted); oc != nil {
+			instance.Status.History[0].Message = oc.Message
+		}
+		r.pruneScheduledDumps(instance, reqLogger)
+		return r.requeueScheduledDump(instance, schedule.Next(now), now)
+	}
+	if !r.runInstanceDumps(instance, now.Unix(), reqLogger) {
+		if next := schedule.Next(now); !next.IsZero() {
+			instance.Status.NextScheduleTime = &metav1.Time{Time: next}
+		}
+		r.Client.Status().Update(context.TODO(), instance)
+		return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
+	}
+	r.finishScheduledDump(instance, reqLogger)
+	return r.requeueScheduledDump(instance, schedule.Next(now), now)
+}
+
+// Returns true if the dumps of the most recent activation of the schedule have not finished
+func isScheduledDumpRunning(instance *openlibertyv1.OpenLibertyDump) bool {
+	return len(instance.Status.History) > 0 && instance.Status.History[0].Status == corev1.ConditionUnknown
+}
+
+// Records the outcome of the dumps of the most recent activation in its history entry and prunes expired dumps
+func (r *ReconcileOpenLibertyDump) finishScheduledDump(instance *openlibertyv1.OpenLibertyDump, reqLogger logr.Logger) {
+	entry := &instance.Status.History[0]
+	entry.Status = corev1.ConditionFalse
+	if oc := openlibertyv1.GetOperationCondtion(instance.Status.Conditions, openlibertyv1.OperationStatusConditionTypeCompleted); oc != nil {
+		entry.Status = oc.Status
+		entry.Message = oc.Message
+	}
+	if instance.Spec.PodName == "" {
+		entry.Pods = instance.Status.Pods
+	} else if entry.Status == corev1.ConditionTrue {
+		entry.DumpFile = instance.Status.DumpFile
+		entry.PodName = instance.Status.PodName
+		if oc := openlibertyv1.GetOperationCondtion(instance.Status.Conditions, openlibertyv1.OperationStatusConditionTypeExported); oc != nil && oc.Status == corev1.ConditionTrue {
+			entry.Export = instance.Status.Export
+		}
+	}
+	r.pruneScheduledDumps(instance, reqLogger)
+}
+
+func (r *ReconcileOpenLibertyDump) requeueScheduledDump(instance *openlibertyv1.OpenLibertyDump, nextScheduleTime time.Time, now time.Time) (ctrl.Result, error) {
+	if nextScheduleTime.IsZero() {
+		instance.Status.NextScheduleTime = nil
+		r.Client.Status().Update(context.TODO(), instance)
+		return reconcile.Result{}, nil
+	}
+	instance.Status.NextScheduleTime = &metav1.Time{Time: nextScheduleTime}
+	r.Client.Status().Update(context.TODO(), instance)
+	return reconcile.Result{RequeueAfter: nextScheduleTime.Sub(now)}, nil
+}
+
+// Trims the scheduled dump history and, when .spec.retentionCount is set, deletes the archives of the trimmed entries.
+// The history is left untouched if the archives could not be deleted so that pruning is retried after the next dump.
+func (r *ReconcileOpenLibertyDump) pruneScheduledDumps(instance *openlibertyv1.OpenLibertyDump, reqLogger logr.Logger) {
+	limit := defaultDumpHistoryLimit
+	if instance.Spec.RetentionCount != nil {
+		limit = int(*instance.Spec.RetentionCount)
+	}
+	if len(instance.Status.History) <= limit {
+		return
+	}
+	if instance.Spec.RetentionCount != nil {
+		dumpFiles := []string{}
+		for _, entry := range instance.Status.History[limit:] {
+			if entry.DumpFile != "" {
+				dumpFiles = append(dumpFiles, entry.DumpFile)
+			}
+			for _, podStatus := range entry.Pods {
+				if podStatus.DumpFile != "" {
+					dumpFiles = append(dumpFiles, podStatus.DumpFile)
+				}
+			}
+		}
+		if len(dumpFiles) > 0 {
+			pod, err := r.getRunningTargetPod(instance)
+			if err != nil {
+				return
+			}
+			pruneCmd := "rm -f " + strings.Join(dumpFiles, " ")
+			if _, err := utils.ExecuteCommandInContainer(r.RestConfig, pod.Name, pod.Namespace, "app", []string{"/bin/sh", "-c", pruneCmd}); err != nil {
+				reqLogger.Error(err, "Failed to delete expired dump files", "cmd", pruneCmd)
+				r.Recorder.Event(instance, "Warning", "ProcessingError", "Failed to delete expired dump files: "+err.Error())
+				return
+			}
+		}
+	}
+	instance.Status.History = instance.Status.History[:limit]
+}
+
+// Resets the conditions for a new run of the dumps and marks it as started. Unless .spec.podName is set, the running pods
+// selected by .spec.applicationRef or .spec.podSelector are recorded in the status. Returns false if no pods are selected.
+func (r *ReconcileOpenLibertyDump) startDumpRun(instance *openlibertyv1.OpenLibertyDump, reqLogger logr.Logger) bool {
+	for _, t := range []openlibertyv1.OperationStatusConditionType{openlibertyv1.OperationStatusConditionTypeRunning, openlibertyv1.OperationStatusConditionTypeCompleted, openlibertyv1.OperationStatusConditionTypeExported, openlibertyv1.OperationStatusConditionTypeFailed} {
+		instance.Status.Conditions = openlibertyv1.RemoveOperationCondtion(instance.Status.Conditions, t)
+	}
+
+	if instance.Spec.PodName == "" {
+		pods, err := r.getSelectedPods(instance)
+		if err != nil || len(pods) == 0 {
+			message := "Failed to find running pods selected by " + getDumpTargetDescription(instance) + " in namespace " + instance.Namespace
+			reqLogger.Error(err, message)
+			r.Recorder.Event(instance, "Warning", "ProcessingError", message)
+			c := openlibertyv1.OperationStatusCondition{
+				Type:    openlibertyv1.OperationStatusConditionTypeStarted,
+				Status:  corev1.ConditionFalse,
+				Reason:  "Error",
+				Message: "Failed to find running pods to dump",
+			}
+			instance.Status.Conditions = openlibertyv1.SetOperationCondtion(instance.Status.Conditions, c)
+			return false
+		}
+		instance.Status.Pods = make([]openlibertyv1.DumpPodStatus, len(pods))
+		for i := range pods {
+			instance.Status.Pods[i].PodName = pods[i].Name
+		}
+	}
+
+	c := openlibertyv1.OperationStatusCondition{
+		Type:   openlibertyv1.OperationStatusConditionTypeStarted,
 		Status: corev1.ConditionTrue,
 	}
+	instance.Status.Conditions = openlibertyv1.SetOperationCondtion(instance.Status.Conditions, c)
+	return true
+}
 
+// Advances the dumps of the current run, which is identified by runID, and updates the status conditions.
+// Returns true once the dumps of all target pods have finished.
+func (r *ReconcileOpenLibertyDump) runInstanceDumps(instance *openlibertyv1.OpenLibertyDump, runID int64, reqLogger logr.Logger) bool {
+	// the dump of .spec.podName is tracked by the conditions of the instance itself
+	if instance.Spec.PodName != "" {
+		targets := []openlibertyv1.DumpPodStatus{{
+			PodName:    instance.Spec.PodName,
+			DumpFile:   instance.Status.DumpFile,
+			Export:     instance.Status.Export,
+			Conditions: instance.Status.Conditions,
+		}}
+		finished := r.runDumps(instance, targets, runID, reqLogger)
+		if targets[0].DumpFile != instance.Status.DumpFile {
+			// .spec.podName can be changed later, so the pod holding the dump file is recorded with it
+			instance.Status.PodName = targets[0].PodName
+		}
+		instance.Status.Conditions = targets[0].Conditions
+		instance.Status.DumpFile = targets[0].DumpFile
+		instance.Status.Export = targets[0].Export
+		return finished
+	}
+
+	if !r.runDumps(instance, instance.Status.Pods, runID, reqLogger) {
+		instance.Status.Conditions = openlibertyv1.SetOperationCondtion(instance.Status.Conditions, openlibertyv1.OperationStatusCondition{
+			Type:   openlibertyv1.OperationStatusConditionTypeRunning,
+			Status: corev1.ConditionTrue,
+		})
+		return false
+	}
+	c := getDumpPodsCompletedCondition(instance.Status.Pods)
 	instance.Status.Conditions = openlibertyv1.SetOperationCondtion(instance.Status.Conditions, c)
-	instance.Status.DumpFile = dumpFileName
-	instance.Status.ObservedGeneration = instance.GetObjectMeta().GetGeneration()
-	instance.Status.Versions.Reconciled = utils.OperandVersion
-	r.Client.Status().Update(context.TODO(), instance)
-	return reconcile.Result{}, nil
+	instance.Status.Conditions = openlibertyv1.SetOperationCondtion(instance.Status.Conditions, openlibertyv1.OperationStatusCondition{
+		Type:   openlibertyv1.OperationStatusConditionTypeRunning,
+		Status: corev1.ConditionFalse,
+	})
+	if c.Status != corev1.ConditionTrue {
+		instance.Status.Conditions = openlibertyv1.SetOperationCondtion(instance.Status.Conditions, openlibertyv1.OperationStatusCondition{
+			Type:    openlibertyv1.OperationStatusConditionTypeFailed,
+			Status:  corev1.ConditionTrue,
+			Reason:  c.Reason,
+			Message: c.Message,
+		})
+	}
+	if instance.Spec.Export != nil {
+		instance.Status.Conditions = openlibertyv1.SetOperationCondtion(instance.Status.Conditions, getDumpPodsExportedCondition(instance.Status.Pods))
+	}
+	return true
+}
+
+// Collects the results of finished dump workers and starts workers for pending target pods, keeping at most
+// .spec.maxConcurrentDumps dumps running. Returns true once the dumps of all target pods have finished.
+func (r *ReconcileOpenLibertyDump) runDumps(instance *openlibertyv1.OpenLibertyDump, targets []openlibertyv1.DumpPodStatus, runID int64, reqLogger logr.Logger) bool {
+	var exportClient *s3.Client
+	var exportErr error
+	running := 0
+	for i := range targets {
+		if !isDumpRunning(targets[i].Conditions) {
+			continue
+		}
+		status, result := pollWorker(getWorkerKey(string(instance.UID), targets[i].PodName, runID))
+		switch status {
+		case workerStatusRunning:
+			running++
+		case workerStatusDone:
+			r.setDumpResult(instance, &targets[i], result, reqLogger)
+		default:
+			r.setDumpResult(instance, &targets[i], workerResult{err: fmt.Errorf("the dump was interrupted, possibly by a restart of the operator")}, reqLogger)
+		}
+	}
+
+	for i := range targets {
+		if running >= instance.GetMaxConcurrentDumps() {
+			break
+		}
+		if !isDumpPending(targets[i].Conditions) {
+			continue
+		}
+		pod, err := r.getRunningPod(instance.Namespace, targets[i].PodName)
+		if err != nil {
+			message := "Failed to find pod " + targets[i].PodName + " in namespace " + instance.Namespace
+			reqLogger.Error(err, message)
+			r.Recorder.Event(instance, "Warning", "ProcessingError", message)
+			targets[i].Conditions = openlibertyv1.SetOperationCondtion(targets[i].Conditions, openlibertyv1.OperationStatusCondition{
+				Type:    openlibertyv1.OperationStatusConditionTypeStarted,
+				Status:  corev1.ConditionFalse,
+				Reason:  "Error",
+				Message: "Failed to find a pod or pod is not in running state",
+			})
+			setDumpFailed(&targets[i], "Failed to find a pod or pod is not in running state")
+			continue
+		}
+
+		dumpFileName, dumpCmd := getDumpCommand(instance, pod, time.Now())
+		if instance.Spec.Export != nil && exportClient == nil && exportErr == nil {
+			exportClient, exportErr = getExportClient(r.Client, instance.Namespace, instance.Spec.Export)
+		}
+		if !startWorker(getWorkerKey(string(instance.UID), pod.Name, runID), instance.GetTimeout(), r.getDumpWorker(instance, pod.Name, dumpFileName, dumpCmd, exportClient, exportErr)) {
+			// the dump stays pending and is started by a later poll
+			targets[i].Conditions = openlibertyv1.SetOperationCondtion(targets[i].Conditions, openlibertyv1.OperationStatusCondition{
+				Type:    openlibertyv1.OperationStatusConditionTypeRunning,
+				Status:  corev1.ConditionFalse,
+				Reason:  "TooManyWorkers",
+				Message: fmt.Sprintf("Waiting for one of the %d dumps running in the operator to finish", maxOperationWorkers),
+			})
+			break
+		}
+		reqLogger.Info("Started dump", "pod", pod.Name, "cmd", dumpCmd)
+		targets[i].Conditions = openlibertyv1.SetOperationCondtion(targets[i].Conditions, openlibertyv1.OperationStatusCondition{
+			Type:   openlibertyv1.OperationStatusConditionTypeStarted,
+			Status: corev1.ConditionTrue,
+		})
+		targets[i].Conditions = openlibertyv1.SetOperationCondtion(targets[i].Conditions, openlibertyv1.OperationStatusCondition{
+			Type:   openlibertyv1.OperationStatusConditionTypeRunning,
+			Status: corev1.ConditionTrue,
+		})
+		running++
+	}
+
+	for i := range targets {
+		if openlibertyv1.GetOperationCondtion(targets[i].Conditions, openlibertyv1.OperationStatusConditionTypeCompleted) == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the worker that dumps the pod and, when .spec.export is set, uploads the dump file
+func (r *ReconcileOpenLibertyDump) getDumpWorker(instance *openlibertyv1.OpenLibertyDump, podName, dumpFileName, dumpCmd string, exportClient *s3.Client, exportErr error) func(ctx context.Context) workerResult {
+	export := instance.Spec.Export.DeepCopy()
+	podNamespace := instance.Namespace
+	return func(ctx context.Context) workerResult {
+		_, err := utils.ExecuteCommandInContainerWithContext(ctx, r.RestConfig, podName, podNamespace, "app", []string{"/bin/sh", "-c", dumpCmd})
+		if err != nil {
+			return workerResult{err: err}
+		}
+		result := workerResult{file: dumpFileName}
+		if export != nil {
+			if exportErr != nil {
+				result.exportErr = exportErr
+			} else {
+				result.export, result.exportErr = exportFile(ctx, r.RestConfig, podName, podNamespace, dumpFileName, export, exportClient)
+			}
+		}
+		return result
+	}
+}
+
+func (r *ReconcileOpenLibertyDump) setDumpResult(instance *openlibertyv1.OpenLibertyDump, target *openlibertyv1.DumpPodStatus, result workerResult, reqLogger logr.Logger) {
+	if result.err != nil {
+		reqLogger.Error(result.err, "Execute dump cmd failed ", "pod", target.PodName)
+		r.Recorder.Event(instance, "Warning", "ProcessingError", "Failed to dump pod "+target.PodName+": "+result.err.Error())
+		setDumpFailed(target, result.err.Error())
+		return
+	}
+	reqLogger.Info("Completed dump", "pod", target.PodName, "dumpFile", result.file)
+	metrics.CountOperation(metrics.OperationDump, true)
+	metrics.ObserveOperationDuration(metrics.OperationDump, result.duration)
+	target.DumpFile = result.file
+	target.Conditions = openlibertyv1.SetOperationCondtion(target.Conditions, openlibertyv1.OperationStatusCondition{
+		Type:   openlibertyv1.OperationStatusConditionTypeRunning,
+		Status: corev1.ConditionFalse,
+	})
+	target.Conditions = openlibertyv1.SetOperationCondtion(target.Conditions, openlibertyv1.OperationStatusCondition{
+		Type:   openlibertyv1.OperationStatusConditionTypeCompleted,
+		Status: corev1.ConditionTrue,
+	})
+	if result.exportErr != nil {
+		reqLogger.Error(result.exportErr, "Export of dump file failed", "pod", target.PodName)
+		r.Recorder.Event(instance, "Warning", "ProcessingError", "Failed to export the dump of pod "+target.PodName+": "+result.exportErr.Error())
+		target.Conditions = openlibertyv1.SetOperationCondtion(target.Conditions, openlibertyv1.OperationStatusCondition{
+			Type:    openlibertyv1.OperationStatusConditionTypeExported,
+			Status:  corev1.ConditionFalse,
+			Reason:  "Error",
+			Message: result.exportErr.Error(),
+		})
+	} else if result.export != nil {
+		target.Export = result.export
+		target.Conditions = openlibertyv1.SetOperationCondtion(target.Conditions, openlibertyv1.OperationStatusCondition{
+			Type:   openlibertyv1.OperationStatusConditionTypeExported,
+			Status: corev1.ConditionTrue,
+		})
+	}
+}
+
+func setDumpFailed(target *openlibertyv1.DumpPodStatus, message string) {
+	metrics.CountOperation(metrics.OperationDump, false)
+	target.Conditions = openlibertyv1.SetOperationCondtion(target.Conditions, openlibertyv1.OperationStatusCondition{
+		Type:   openlibertyv1.OperationStatusConditionTypeRunning,
+		Status: corev1.ConditionFalse,
+	})
+	target.Conditions = openlibertyv1.SetOperationCondtion(target.Conditions, openlibertyv1.OperationStatusCondition{
+		Type:    openlibertyv1.OperationStatusConditionTypeCompleted,
+		Status:  corev1.ConditionFalse,
+		Reason:  "Error",
+		Message: message,
+	})
+	target.Conditions = openlibertyv1.SetOperationCondtion(target.Conditions, openlibertyv1.OperationStatusCondition{
+		Type:    openlibertyv1.OperationStatusConditionTypeFailed,
+		Status:  corev1.ConditionTrue,
+		Reason:  "Error",
+		Message: message,
+	})
+}
+
+func isDumpRunning(conditions []openlibertyv1.OperationStatusCondition) bool {
+	oc := openlibertyv1.GetOperationCondtion(conditions, openlibertyv1.OperationStatusConditionTypeRunning)
+	return oc != nil && oc.Status == corev1.ConditionTrue
+}
+
+// Returns true if the dump of the instance completed, or if it failed and the instance has not been updated since.
+// A failed dump is retried after an update of the instance, for example to fix .spec.podName.
+func isDumpFinished(instance *openlibertyv1.OpenLibertyDump) bool {
+	oc := openlibertyv1.GetOperationCondtion(instance.Status.Conditions, openlibertyv1.OperationStatusConditionTypeCompleted)
+	if oc == nil {
+		return false
+	}
+	return oc.Status == corev1.ConditionTrue || instance.Status.ObservedGeneration == instance.GetObjectMeta().GetGeneration()
+}
+
+// A dump is pending until it is running or has finished
+func isDumpPending(conditions []openlibertyv1.OperationStatusCondition) bool {
+	return !isDumpRunning(conditions) && openlibertyv1.GetOperationCondtion(conditions, openlibertyv1.OperationStatusConditionTypeCompleted) == nil
+}
+
+// Returns a Completed condition that is true only if the dumps of all pods completed
+func getDumpPodsCompletedCondition(results []openlibertyv1.DumpPodStatus) openlibertyv1.OperationStatusCondition {
+	failed := 0
+	for i := range results {
+		oc := openlibertyv1.GetOperationCondtion(results[i].Conditions, openlibertyv1.OperationStatusConditionTypeCompleted)
+		if oc == nil || oc.Status != corev1.ConditionTrue {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return openlibertyv1.OperationStatusCondition{
+			Type:    openlibertyv1.OperationStatusConditionTypeCompleted,
+			Status:  corev1.ConditionFalse,
+			Reason:  "Error",
+			Message: fmt.Sprintf("%d of %d pod dumps failed", failed, len(results)),
+		}
+	}
+	return openlibertyv1.OperationStatusCondition{
+		Type:   openlibertyv1.OperationStatusConditionTypeCompleted,
+		Status: corev1.ConditionTrue,
+	}
+}
+
+// Returns an Exported condition that is true only if the files of all completed pod dumps were exported
+func getDumpPodsExportedCondition(results []openlibertyv1.DumpPodStatus) openlibertyv1.OperationStatusCondition {
+	completed, failed := 0, 0
+	for i := range results {
+		if oc := openlibertyv1.GetOperationCondtion(results[i].Conditions, openlibertyv1.OperationStatusConditionTypeCompleted); oc == nil || oc.Status != corev1.ConditionTrue {
+			continue
+		}
+		completed++
+		if oc := openlibertyv1.GetOperationCondtion(results[i].Conditions, openlibertyv1.OperationStatusConditionTypeExported); oc == nil || oc.Status != corev1.ConditionTrue {
+			failed++
+		}
+	}
+	if failed > 0 || completed == 0 {
+		return openlibertyv1.OperationStatusCondition{
+			Type:    openlibertyv1.OperationStatusConditionTypeExported,
+			Status:  corev1.ConditionFalse,
+			Reason:  "Error",
+			Message: fmt.Sprintf("%d of %d dump files failed to export", failed, completed),
+		}
+	}
+	return openlibertyv1.OperationStatusCondition{
+		Type:   openlibertyv1.OperationStatusConditionTypeExported,
+		Status: corev1.ConditionTrue,
+	}
+}
+
+// Returns the running pods selected by .spec.applicationRef or .spec.podSelector, sorted by name
+func (r *ReconcileOpenLibertyDump) getSelectedPods(instance *openlibertyv1.OpenLibertyDump) ([]corev1.Pod, error) {
+	var selector labels.Selector
+	if instance.Spec.ApplicationRef != nil {
+		app := &openlibertyv1.OpenLibertyApplication{}
+		if err := r.Client.Get(context.TODO(), types.NamespacedName{Name: instance.Spec.ApplicationRef.Name, Namespace: instance.Namespace}, app); err != nil {
+			return nil, err
+		}
+		selector = labels.SelectorFromSet(labels.Set{"app.kubernetes.io/instance": app.Name})
+	} else {
+		var err error
+		selector, err = metav1.LabelSelectorAsSelector(instance.Spec.PodSelector)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	podList := &corev1.PodList{}
+	if err := r.Client.List(context.TODO(), podList, client.InNamespace(instance.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return nil, err
+	}
+	pods := []corev1.Pod{}
+	for _, pod := range podList.Items {
+		if pod.Status.Phase == corev1.PodRunning && pod.DeletionTimestamp == nil {
+			pods = append(pods, pod)
+		}
+	}
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].Name < pods[j].Name
+	})
+	return pods, nil
+}
+
+// Returns an error unless exactly one of .spec.podName, .spec.applicationRef or .spec.podSelector is set
+func validateDumpTarget(instance *openlibertyv1.OpenLibertyDump) error {
+	targets := 0
+	if instance.Spec.PodName != "" {
+		targets++
+	}
+	if instance.Spec.ApplicationRef != nil {
+		targets++
+	}
+	if instance.Spec.PodSelector != nil {
+		targets++
+	}
+	if targets != 1 {
+		return fmt.Errorf("exactly one of podName, applicationRef or podSelector must be set")
+	}
+	return nil
+}
+
+func getDumpTargetDescription(instance *openlibertyv1.OpenLibertyDump) string {
+	if instance.Spec.ApplicationRef != nil {
+		return "application " + instance.Spec.ApplicationRef.Name
+	}
+	return "podSelector"
+}
+
+func (r *ReconcileOpenLibertyDump) getRunningPod(namespace string, name string) (*corev1.Pod, error) {
+	pod := &corev1.Pod{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, pod)
+	if err != nil {
+		return nil, err
+	}
+	if pod.Status.Phase != corev1.PodRunning {
+		return nil, fmt.Errorf("pod %s is in phase %s", pod.Name, pod.Status.Phase)
+	}
+	return pod, nil
+}
+
+// Returns a running target pod of the instance, which can access the serviceability folder holding its dumps
+func (r *ReconcileOpenLibertyDump) getRunningTargetPod(instance *openlibertyv1.OpenLibertyDump) (*corev1.Pod, error) {
+	if instance.Spec.PodName != "" {
+		return r.getRunningPod(instance.Namespace, instance.Spec.PodName)
+	}
+	pods, err := r.getSelectedPods(instance)
+	if err != nil {
+		return nil, err
+	}
+	if len(pods) == 0 {
+		return nil, fmt.Errorf("no running pods are selected by %s", getDumpTargetDescription(instance))
+	}
+	return &pods[0], nil
+}
+
+// Returns the archive file name and the command that writes a server dump of the pod into its serviceability folder
+func getDumpCommand(instance *openlibertyv1.OpenLibertyDump, pod *corev1.Pod, currentTime time.Time) (string, string) {
+	dumpFolder := "/serviceability/" + pod.Namespace + "/" + pod.Name + "/serverDumps"
+	dumpFileName := dumpFolder + "/" + "dump_" + currentTime.UTC().Format("2006.01.02_15.04.05") + "_utc.zip"
+	dumpCmd := "mkdir -p " + dumpFolder + " &&  server dump --archive=" + dumpFileName
+	if len(instance.Spec.Include) > 0 {
+		dumpCmd += " --include="
+		for i := range instance.Spec.Include {
+			dumpCmd += string(instance.Spec.Include[i]) + ","
+		}
+	}
+	return dumpFileName, dumpCmd
+}
+
+// Cancels the running dumps of the instance. The archives of completed dumps are kept in the serviceability folder.
+func (r *ReconcileOpenLibertyDump) finalizeOpenLibertyDump(reqLogger logr.Logger, instance *openlibertyv1.OpenLibertyDump) {
+	reqLogger.Info("Cancelling running dumps of OpenLibertyDump")
+	cancelWorkers(string(instance.UID))
+	clearWorkerResults(string(instance.UID))
+}
+
+func (r *ReconcileOpenLibertyDump) addFinalizer(reqLogger logr.Logger, instance *openlibertyv1.OpenLibertyDump) error {
+	reqLogger.Info("Adding Finalizer for OpenLibertyDump")
+	instance.SetFinalizers(append(instance.GetFinalizers(), dumpFinalizer))
+
+	// Update CR
+	err := r.Client.Update(context.TODO(), instance)
+	if err != nil {
+		reqLogger.Error(err, "Failed to update OpenLibertyDump with finalizer")
+		return err
+	}
+
+	return nil
 }
 
 func (r *ReconcileOpenLibertyDump) SetupWithManager(mgr ctrl.Manager) error {
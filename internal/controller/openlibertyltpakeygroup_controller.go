@@ -0,0 +1,507 @@
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	olv1 "github.com/OpenLiberty/open-liberty-operator/api/v1"
+	lutils "github.com/OpenLiberty/open-liberty-operator/utils"
+	"github.com/OpenLiberty/open-liberty-operator/utils/metrics"
+	tree "github.com/OpenLiberty/open-liberty-operator/utils/tree"
+	oputils "github.com/application-stacks/runtime-component-operator/utils"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// Name of the LTPA keys of a group in the key rotation metrics
+const ltpaKeyGroupMetricsKey = "ltpa-key-group"
+
+// Root name of the Secrets with the LTPA keys of a group. The authoritative Secret is in the operator namespace and is mirrored into every member namespace.
+const ltpaKeyGroupSecretRootName = OperatorShortName + "-managed-ltpa-group"
+
+// Annotation with the name of the OpenLibertyLTPAKeyGroup on the Secrets with the LTPA keys of the group
+const ltpaKeyGroupAnnotation = lutils.LibertyURI + "/ltpa-key-group"
+
+// Annotation with the namespace of the operator that manages an OpenLibertyLTPAKeyGroup, on the group and on the Secrets with the LTPA keys of the group
+const ltpaKeyGroupOperatorAnnotation = lutils.LibertyURI + "/ltpa-key-group-operator"
+
+// Field of the LTPA keys Secret of a namespace with the name of the group whose LTPA keys the namespace adopted. The LTPA keys leader of the namespace
+// rotates the LTPA keys as soon as the group no longer mirrors its LTPA keys into the namespace.
+const ltpaKeyGroupDataKey = "ltpaKeyGroup"
+
+// The fields of the LTPA keys Secret that are shared by the group, in the format of the LTPA keys Secret generated by generateLTPAKeys
+var ltpaKeyGroupDataKeys = []string{lutils.LTPAKeysFileName, "rawPassword", "lastRotation", lutils.LTPAValidationKeysFileName, "validationRawPassword", "validationKeysValidUntil"}
+
+// The time between the checks of the LTPA keys of a group, and between the checks while the member namespaces adopt new LTPA keys
+const ltpaKeyGroupSyncInterval = 5 * time.Minute
+const ltpaKeyGroupAdoptionInterval = 30 * time.Second
+
+const ltpaKeyGroupMetricsController = "openlibertyltpakeygroup"
+
+const ltpaKeyGroupFinalizer = "finalizer.openlibertyltpakeygroups.apps.openliberty.io"
+
+// ReconcileOpenLibertyLTPAKeyGroup reconciles an OpenLibertyLTPAKeyGroup object. It is only set up when the operator watches all namespaces, and its client
+// does not use the cache of the manager, so that the Secrets of the member namespaces are read and written without caching every Secret of the cluster.
+type ReconcileOpenLibertyLTPAKeyGroup struct {
+	oputils.ReconcilerBase
+	Log logr.Logger
+}
+
+// +kubebuilder:rbac:groups=apps.openliberty.io,resources=openlibertyltpakeygroups;openlibertyltpakeygroups/status;openlibertyltpakeygroups/finalizers,verbs=get;list;watch;create;update;patch;delete
+
+// Reconcile keeps one set of LTPA keys for the OpenLibertyLTPAKeyGroup in the operator namespace, rotates it with the LTPA keys rotation policy of
+// the operator ConfigMap and mirrors it into every member namespace, where the LTPA keys leader of the namespace adopts it.
+func (r *ReconcileOpenLibertyLTPAKeyGroup) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	defer metrics.ObserveReconcileDuration(ltpaKeyGroupMetricsController, time.Now())
+	reqLogger := r.Log.WithValues("Request.Name", request.Name)
+	reqLogger.Info("Reconciling OpenLibertyLTPAKeyGroup")
+
+	instance := &olv1.OpenLibertyLTPAKeyGroup{}
+	if err := r.GetClient().Get(context.TODO(), request.NamespacedName, instance); err != nil {
+		if kerrors.IsNotFound(err) {
+			// The Secret with the LTPA keys of the group in the operator namespace is owned by the group, so it is garbage collected
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	operatorNamespace, err := oputils.GetOperatorNamespace()
+	if err != nil {
+		return r.manageLTPAKeyGroupError(instance, "Error", err)
+	}
+	// Only one operator manages a group, since several operators would overwrite the LTPA keys that the others mirror
+	owner := instance.Annotations[ltpaKeyGroupOperatorAnnotation]
+	if owner != "" && owner != operatorNamespace {
+		reqLogger.Info("Skipping the OpenLibertyLTPAKeyGroup that is managed by the operator in another namespace", "operatorNamespace", owner)
+		return reconcile.Result{}, nil
+	}
+
+	// Check if the OpenLibertyLTPAKeyGroup instance is marked to be deleted, which is
+	// indicated by the deletion timestamp being set.
+	if instance.GetDeletionTimestamp() != nil {
+		if lutils.Contains(instance.GetFinalizers(), ltpaKeyGroupFinalizer) {
+			// Hand the LTPA keys back to the LTPA keys leader of each namespace by removing the LTPA keys of the group from the namespaces.
+			// If the finalization logic fails, don't remove the finalizer so that we can retry during the next reconciliation.
+			if err := r.deleteLTPAKeyGroupKeys(instance, getLTPAKeyGroupNamespaces(instance)); err != nil {
+				return reconcile.Result{}, err
+			}
+			instance.SetFinalizers(lutils.Remove(instance.GetFinalizers(), ltpaKeyGroupFinalizer))
+			if err := r.GetClient().Update(context.TODO(), instance); err != nil {
+				return reconcile.Result{}, err
+			}
+		}
+		return reconcile.Result{}, nil
+	}
+
+	if owner == "" || !lutils.Contains(instance.GetFinalizers(), ltpaKeyGroupFinalizer) {
+		reqLogger.Info("Adding Finalizer for OpenLibertyLTPAKeyGroup")
+		if instance.Annotations == nil {
+			instance.Annotations = make(map[string]string)
+		}
+		instance.Annotations[ltpaKeyGroupOperatorAnnotation] = operatorNamespace
+		if !lutils.Contains(instance.GetFinalizers(), ltpaKeyGroupFinalizer) {
+			instance.SetFinalizers(append(instance.GetFinalizers(), ltpaKeyGroupFinalizer))
+		}
+		if err := r.GetClient().Update(context.TODO(), instance); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	groups := &olv1.OpenLibertyLTPAKeyGroupList{}
+	if err := r.GetClient().List(context.TODO(), groups); err != nil {
+		return r.manageLTPAKeyGroupError(instance, "Error", err)
+	}
+	members, conflicts := getLTPAKeyGroupMembers(instance, groups.Items)
+
+	pathIndex, err := getLTPAKeysPathIndex()
+	if err != nil {
+		return r.manageLTPAKeyGroupError(instance, "Error", err)
+	}
+
+	// Generate the LTPA keys of the group once, then rotate them when all the member namespaces use the current LTPA keys
+	groupSecret, err := r.reconcileLTPAKeyGroupKeys(instance, operatorNamespace, pathIndex)
+	if err != nil {
+		return r.manageLTPAKeyGroupError(instance, "KeysFailed", err)
+	}
+	rotationInterval, validationPeriod, err := getLTPAKeysRotationPolicy()
+	if err != nil {
+		return r.manageLTPAKeyGroupError(instance, "KeysFailed", err)
+	}
+	lastRotation := string(groupSecret.Data["lastRotation"])
+	now := time.Now()
+	rotationDue, err := isLTPAKeysRotationDue(lastRotation, rotationInterval, now)
+	if err != nil {
+		return r.manageLTPAKeyGroupError(instance, "KeysFailed", err)
+	}
+	if rotationDue && isLTPAKeyGroupRotationAllowed(instance.Status.Namespaces, members, lastRotation) {
+		reqLogger.Info("Rotating the LTPA keys of the group", "lastRotation", lastRotation)
+		if err := r.writeLTPAKeyGroupKeys(instance, groupSecret, pathIndex, getLTPAValidationKeys(groupSecret, validationPeriod, now)); err != nil {
+			return r.manageLTPAKeyGroupError(instance, "KeysFailed", err)
+		}
+		lastRotation = string(groupSecret.Data["lastRotation"])
+	}
+
+	// Mirror the LTPA keys into the member namespaces and read the LTPA keys that each namespace uses
+	namespaceStatuses := []olv1.LTPAKeyGroupNamespaceStatus{}
+	for _, namespace := range members {
+		namespaceStatus := olv1.LTPAKeyGroupNamespaceStatus{Namespace: namespace}
+		if err := r.mirrorLTPAKeyGroupKeys(instance, groupSecret, namespace, pathIndex); err != nil {
+			namespaceStatus.Message = err.Error()
+		} else if namespaceStatus.LastRotation, err = r.getNamespaceLTPAKeysLastRotation(namespace, pathIndex); err != nil {
+			namespaceStatus.Message = err.Error()
+		}
+		namespaceStatuses = append(namespaceStatuses, namespaceStatus)
+	}
+	for _, namespace := range sortedKeys(conflicts) {
+		namespaceStatuses = append(namespaceStatuses, olv1.LTPAKeyGroupNamespaceStatus{
+			Namespace: namespace,
+			Message:   fmt.Sprintf("the namespace is a member of the OpenLibertyLTPAKeyGroup '%s'", conflicts[namespace]),
+		})
+	}
+	// Remove the LTPA keys of the group from the namespaces that are no longer members, so that their LTPA keys leader generates LTPA keys for the namespace again
+	formerMembers := getLTPAKeyGroupFormerMembers(&instance.Status, members)
+	if err := r.deleteLTPAKeyGroupKeys(instance, formerMembers); err != nil {
+		instance.Status.FormerNamespaces = formerMembers
+		return r.manageLTPAKeyGroupError(instance, "Error", err)
+	}
+
+	instance.Status.FormerNamespaces = nil
+	instance.Status.Namespaces = namespaceStatuses
+	instance.Status.LastRotation = lastRotation
+	instance.Status.ObservedGeneration = instance.GetGeneration()
+	synced, reason, message := getLTPAKeyGroupSyncedCondition(namespaceStatuses, lastRotation)
+	meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+		Type:               olv1.LTPAKeyGroupConditionTypeSynced,
+		Status:             synced,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: instance.GetGeneration(),
+	})
+	if err := r.GetClient().Status().Update(context.TODO(), instance); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if synced != metav1.ConditionTrue {
+		return reconcile.Result{RequeueAfter: ltpaKeyGroupAdoptionInterval}, nil
+	}
+	return reconcile.Result{RequeueAfter: getLTPAKeyGroupRequeueAfter(lastRotation, rotationInterval, now)}, nil
+}
+
+// Returns the member namespaces of the group and the namespaces of the group that are members of an older group, mapped to the name of that group.
+// A namespace can only adopt one set of LTPA keys, so it is a member of the oldest group that names it.
+func getLTPAKeyGroupMembers(instance *olv1.OpenLibertyLTPAKeyGroup, groups []olv1.OpenLibertyLTPAKeyGroup) ([]string, map[string]string) {
+	isOlder := func(group *olv1.OpenLibertyLTPAKeyGroup) bool {
+		if !group.CreationTimestamp.Equal(&instance.CreationTimestamp) {
+			return group.CreationTimestamp.Before(&instance.CreationTimestamp)
+		}
+		return group.Name < instance.Name
+	}
+	conflicts := map[string]string{}
+	for i := range groups {
+		group := &groups[i]
+		if group.Name == instance.Name || group.GetDeletionTimestamp() != nil || !isOlder(group) {
+			continue
+		}
+		for _, namespace := range group.Spec.Namespaces {
+			if _, found := conflicts[namespace]; !found {
+				conflicts[namespace] = group.Name
+			}
+		}
+	}
+	members := []string{}
+	memberConflicts := map[string]string{}
+	for _, namespace := range instance.Spec.Namespaces {
+		if group, found := conflicts[namespace]; found {
+			memberConflicts[namespace] = group
+		} else if !lutils.Contains(members, namespace) {
+			members = append(members, namespace)
+		}
+	}
+	return members, memberConflicts
+}
+
+// Returns the namespaces that the LTPA keys of the group were mirrored into, or could not be removed from, and that are not in members
+func getLTPAKeyGroupFormerMembers(status *olv1.OpenLibertyLTPAKeyGroupStatus, members []string) []string {
+	formerMembers := []string{}
+	for _, namespace := range status.FormerNamespaces {
+		if !lutils.Contains(members, namespace) && !lutils.Contains(formerMembers, namespace) {
+			formerMembers = append(formerMembers, namespace)
+		}
+	}
+	for _, namespaceStatus := range status.Namespaces {
+		if !lutils.Contains(members, namespaceStatus.Namespace) && !lutils.Contains(formerMembers, namespaceStatus.Namespace) {
+			formerMembers = append(formerMembers, namespaceStatus.Namespace)
+		}
+	}
+	sort.Strings(formerMembers)
+	return formerMembers
+}
+
+// Returns every namespace that the LTPA keys of the group can be mirrored into
+func getLTPAKeyGroupNamespaces(instance *olv1.OpenLibertyLTPAKeyGroup) []string {
+	namespaces := getLTPAKeyGroupFormerMembers(&instance.Status, nil)
+	for _, namespace := range instance.Spec.Namespaces {
+		if !lutils.Contains(namespaces, namespace) {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	sort.Strings(namespaces)
+	return namespaces
+}
+
+// Returns true if the LTPA keys of the group that were last rotated at lastRotation can be rotated. The LTPA keys are only rotated when every member namespace
+// uses them, so that the previous LTPA keys that are kept as validation keys are the LTPA keys that the namespaces were using.
+func isLTPAKeyGroupRotationAllowed(namespaceStatuses []olv1.LTPAKeyGroupNamespaceStatus, members []string, lastRotation string) bool {
+	for _, namespace := range members {
+		found := false
+		for _, namespaceStatus := range namespaceStatuses {
+			if namespaceStatus.Namespace != namespace {
+				continue
+			}
+			found = true
+			// a namespace without LTPA keys has no OpenLibertyApplications to roll
+			if namespaceStatus.Message != "" || (namespaceStatus.LastRotation != "" && namespaceStatus.LastRotation != lastRotation) {
+				return false
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns the status, reason and message of the Synced condition of the group
+func getLTPAKeyGroupSyncedCondition(namespaceStatuses []olv1.LTPAKeyGroupNamespaceStatus, lastRotation string) (metav1.ConditionStatus, string, string) {
+	for _, namespaceStatus := range namespaceStatuses {
+		if namespaceStatus.Message != "" {
+			return metav1.ConditionFalse, "MirrorFailed", fmt.Sprintf("Failed to mirror the LTPA keys into namespace '%s': %s", namespaceStatus.Namespace, namespaceStatus.Message)
+		}
+	}
+	for _, namespaceStatus := range namespaceStatuses {
+		if namespaceStatus.LastRotation != "" && namespaceStatus.LastRotation != lastRotation {
+			return metav1.ConditionFalse, "AdoptionPending", fmt.Sprintf("Waiting for the OpenLibertyApplications in namespace '%s' to use the current LTPA keys", namespaceStatus.Namespace)
+		}
+	}
+	return metav1.ConditionTrue, "Synced", "The LTPA keys are mirrored into all the member namespaces"
+}
+
+// Returns the time until the next check of the LTPA keys of the group, which is at the latest when the LTPA keys must be rotated
+func getLTPAKeyGroupRequeueAfter(lastRotation string, rotationInterval time.Duration, now time.Time) time.Duration {
+	requeueAfter := ltpaKeyGroupSyncInterval
+	lastRotationTime, err := strconv.ParseInt(lastRotation, 10, 64)
+	if rotationInterval <= 0 || err != nil {
+		return requeueAfter
+	}
+	if untilRotation := time.Unix(lastRotationTime, 0).Add(rotationInterval).Sub(now); untilRotation < requeueAfter {
+		requeueAfter = max(untilRotation, time.Second)
+	}
+	return requeueAfter
+}
+
+// Returns the path index of the LTPA keys in the LTPA decision tree. The Secrets with the LTPA keys of a group are labeled with it, like the LTPA keys
+// Secret of a namespace, so that a namespace only adopts LTPA keys for the same decision path.
+func getLTPAKeysPathIndex() (string, error) {
+	treeMap, _, err := tree.ParseDecisionTree(LTPA_RESOURCE_SHARING_FILE_NAME, nil)
+	if err != nil {
+		return "", err
+	}
+	latestOperandVersion, err := tree.GetLatestOperandVersion(treeMap, "")
+	if err != nil {
+		return "", err
+	}
+	// the path must match the LTPA key path of getLTPAPathOptionsAndChoices
+	labelString, err := tree.GetLabelFromDecisionPath(latestOperandVersion, []string{"key"}, []string{"true"})
+	if err != nil {
+		return "", err
+	}
+	validSubPath, err := tree.CanTraverseTree(treeMap, labelString, true)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s.%d", latestOperandVersion, tree.GetLeafIndex(treeMap, validSubPath)), nil
+}
+
+// Returns the name of the Secret with the authoritative LTPA keys of the group in the operator namespace. The name of the group is hashed so that the name
+// of the Secret stays short enough to be used as a label value.
+func getLTPAKeyGroupSecretName(groupName string) string {
+	hash := sha256.Sum256([]byte(groupName))
+	return ltpaKeyGroupSecretRootName + "-" + hex.EncodeToString(hash[:])[:lutils.ResourceSuffixLength*2]
+}
+
+// Returns the Secret with the authoritative LTPA keys of the group, generating the LTPA keys if they don't exist
+func (r *ReconcileOpenLibertyLTPAKeyGroup) reconcileLTPAKeyGroupKeys(instance *olv1.OpenLibertyLTPAKeyGroup, operatorNamespace string, pathIndex string) (*corev1.Secret, error) {
+	groupSecret := &corev1.Secret{}
+	groupSecret.Name = getLTPAKeyGroupSecretName(instance.Name)
+	groupSecret.Namespace = operatorNamespace
+	err := r.GetAPIReader().Get(context.TODO(), types.NamespacedName{Name: groupSecret.Name, Namespace: groupSecret.Namespace}, groupSecret)
+	if err == nil && groupSecret.Annotations[ltpaKeyGroupAnnotation] == instance.Name {
+		return groupSecret, nil
+	}
+	if err != nil && !kerrors.IsNotFound(err) {
+		return nil, err
+	}
+	return groupSecret, r.writeLTPAKeyGroupKeys(instance, groupSecret, pathIndex, nil)
+}
+
+// Generates new LTPA keys and a new password into the Secret with the authoritative LTPA keys of the group, together with the validationKeys fields
+func (r *ReconcileOpenLibertyLTPAKeyGroup) writeLTPAKeyGroupKeys(instance *olv1.OpenLibertyLTPAKeyGroup, groupSecret *corev1.Secret, pathIndex string, validationKeys map[string][]byte) error {
+	password := lutils.GetRandomAlphanumeric(15)
+	ltpaKeysStringData, err := createLTPAKeys(password)
+	if err != nil {
+		return err
+	}
+	data := make(map[string][]byte)
+	for key, value := range validationKeys {
+		data[key] = value
+	}
+	data["lastRotation"] = []byte(strconv.FormatInt(time.Now().Unix(), 10))
+	data["rawPassword"] = []byte(password)
+	data[lutils.LTPAKeysFileName] = ltpaKeysStringData
+
+	if err := r.CreateOrUpdate(groupSecret, instance, func() error {
+		customizeLTPAKeyGroupSecret(groupSecret, instance, pathIndex)
+		groupSecret.Data = data
+		return nil
+	}); err != nil {
+		return err
+	}
+	metrics.CountKeyRotation(ltpaKeyGroupMetricsKey)
+	return nil
+}
+
+// Copies the LTPA keys of the group into the namespace, where the LTPA keys leader of the namespace adopts them
+func (r *ReconcileOpenLibertyLTPAKeyGroup) mirrorLTPAKeyGroupKeys(instance *olv1.OpenLibertyLTPAKeyGroup, groupSecret *corev1.Secret, namespace string, pathIndex string) error {
+	mirrorSecret := &corev1.Secret{}
+	mirrorSecret.Name = ltpaKeyGroupSecretRootName
+	mirrorSecret.Namespace = namespace
+	err := r.GetAPIReader().Get(context.TODO(), types.NamespacedName{Name: mirrorSecret.Name, Namespace: mirrorSecret.Namespace}, mirrorSecret)
+	if err == nil {
+		if !isManagedLTPAKeyGroupSecret(mirrorSecret, instance) {
+			return fmt.Errorf("the Secret '%s' is not managed by this OpenLibertyLTPAKeyGroup", mirrorSecret.Name)
+		}
+	} else if !kerrors.IsNotFound(err) {
+		return err
+	}
+	return r.CreateOrUpdate(mirrorSecret, instance, func() error {
+		customizeLTPAKeyGroupSecret(mirrorSecret, instance, pathIndex)
+		mirrorSecret.Data = make(map[string][]byte)
+		for _, key := range ltpaKeyGroupDataKeys {
+			if value, found := groupSecret.Data[key]; found {
+				mirrorSecret.Data[key] = value
+			}
+		}
+		return nil
+	})
+}
+
+func customizeLTPAKeyGroupSecret(secret *corev1.Secret, instance *olv1.OpenLibertyLTPAKeyGroup, pathIndex string) {
+	if secret.Labels == nil {
+		secret.Labels = make(map[string]string)
+	}
+	for key, value := range lutils.GetRequiredLabels(ltpaKeyGroupSecretRootName, secret.Name) {
+		secret.Labels[key] = value
+	}
+	secret.Labels[lutils.ResourcePathIndexLabel] = pathIndex
+	if secret.Annotations == nil {
+		secret.Annotations = make(map[string]string)
+	}
+	secret.Annotations[ltpaKeyGroupAnnotation] = instance.Name
+	secret.Annotations[ltpaKeyGroupOperatorAnnotation] = instance.Annotations[ltpaKeyGroupOperatorAnnotation]
+}
+
+// Returns true if the Secret holds the LTPA keys of the group. The Secrets written before the groups were annotated with their operator belong to the group too.
+func isManagedLTPAKeyGroupSecret(secret *corev1.Secret, instance *olv1.OpenLibertyLTPAKeyGroup) bool {
+	if secret.Annotations[ltpaKeyGroupAnnotation] != instance.Name {
+		return false
+	}
+	operator, found := secret.Annotations[ltpaKeyGroupOperatorAnnotation]
+	return !found || operator == instance.Annotations[ltpaKeyGroupOperatorAnnotation]
+}
+
+// Returns the last rotation time of the LTPA keys that the OpenLibertyApplications in the namespace use, or an empty string if the namespace has no LTPA keys
+func (r *ReconcileOpenLibertyLTPAKeyGroup) getNamespaceLTPAKeysLastRotation(namespace string, pathIndex string) (string, error) {
+	ltpaSecrets := &corev1.SecretList{}
+	if err := r.GetAPIReader().List(context.TODO(), ltpaSecrets, client.InNamespace(namespace), client.MatchingLabels{
+		"app.kubernetes.io/name":      OperatorShortName + "-managed-ltpa",
+		lutils.ResourcePathIndexLabel: pathIndex,
+	}); err != nil {
+		return "", err
+	}
+	lastRotation := ""
+	for _, ltpaSecret := range ltpaSecrets.Items {
+		if secretLastRotation := string(ltpaSecret.Data["lastRotation"]); secretLastRotation > lastRotation {
+			lastRotation = secretLastRotation
+		}
+	}
+	return lastRotation, nil
+}
+
+// Deletes the LTPA keys of the group from the namespaces. The Secrets are read one namespace at a time, so that the operator does not need to list
+// the Secrets of the cluster.
+func (r *ReconcileOpenLibertyLTPAKeyGroup) deleteLTPAKeyGroupKeys(instance *olv1.OpenLibertyLTPAKeyGroup, namespaces []string) error {
+	for _, namespace := range namespaces {
+		mirrorSecret := &corev1.Secret{}
+		if err := r.GetAPIReader().Get(context.TODO(), types.NamespacedName{Name: ltpaKeyGroupSecretRootName, Namespace: namespace}, mirrorSecret); err != nil {
+			if kerrors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if !isManagedLTPAKeyGroupSecret(mirrorSecret, instance) {
+			continue
+		}
+		if err := r.DeleteResource(mirrorSecret); err != nil && !kerrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *ReconcileOpenLibertyLTPAKeyGroup) manageLTPAKeyGroupError(instance *olv1.OpenLibertyLTPAKeyGroup, reason string, err error) (ctrl.Result, error) {
+	r.Log.Error(err, "Failed to reconcile OpenLibertyLTPAKeyGroup", "Request.Name", instance.Name)
+	metrics.CountReconcileError(ltpaKeyGroupMetricsController)
+	r.GetRecorder().Event(instance, "Warning", "ProcessingError", err.Error())
+	instance.Status.ObservedGeneration = instance.GetGeneration()
+	meta.SetStatusCondition(&instance.Status.Conditions, metav1.Condition{
+		Type:               olv1.LTPAKeyGroupConditionTypeSynced,
+		Status:             metav1.ConditionFalse,
+		Reason:             reason,
+		Message:            err.Error(),
+		ObservedGeneration: instance.GetGeneration(),
+	})
+	if statusErr := r.GetClient().Status().Update(context.TODO(), instance); statusErr != nil {
+		return reconcile.Result{}, statusErr
+	}
+	return reconcile.Result{RequeueAfter: ltpaKeyGroupAdoptionInterval}, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (r *ReconcileOpenLibertyLTPAKeyGroup) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).For(&olv1.OpenLibertyLTPAKeyGroup{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).WithOptions(controller.Options{
+		MaxConcurrentReconciles: 1,
+	}).Complete(r)
+}
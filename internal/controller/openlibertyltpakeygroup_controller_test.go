@@ -0,0 +1,142 @@
+package controller
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	openlibertyv1 "github.com/OpenLiberty/open-liberty-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetLTPAKeyGroupMembers(t *testing.T) {
+	now := time.Date(2024, time.January, 10, 0, 0, 0, 0, time.UTC)
+	// a group created age before now
+	keyGroup := func(name string, age time.Duration, namespaces ...string) openlibertyv1.OpenLibertyLTPAKeyGroup {
+		return openlibertyv1.OpenLibertyLTPAKeyGroup{
+			ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(now.Add(-age))},
+			Spec:       openlibertyv1.OpenLibertyLTPAKeyGroupSpec{Namespaces: namespaces},
+		}
+	}
+
+	older := keyGroup("older", 2*time.Hour, "a", "b")
+	group := keyGroup("group", time.Hour, "b", "c", "d", "c")
+	sameAge := keyGroup("same-age", time.Hour, "d")
+	newer := keyGroup("newer", 0, "c")
+	groups := []openlibertyv1.OpenLibertyLTPAKeyGroup{newer, sameAge, group, older}
+
+	members, conflicts := getLTPAKeyGroupMembers(&group, groups)
+	olderMembers, olderConflicts := getLTPAKeyGroupMembers(&older, groups)
+	newerMembers, newerConflicts := getLTPAKeyGroupMembers(&newer, groups)
+	sameAgeMembers, sameAgeConflicts := getLTPAKeyGroupMembers(&sameAge, groups)
+
+	tests := []Test{
+		{"members", []string{"c", "d"}, members},
+		{"conflicts with older groups", map[string]string{"b": "older"}, conflicts},
+		{"oldest group members", []string{"a", "b"}, olderMembers},
+		{"oldest group conflicts", map[string]string{}, olderConflicts},
+		{"newer group members", []string{}, newerMembers},
+		{"newer group conflicts", map[string]string{"c": "group"}, newerConflicts},
+		{"group of the same age members", []string{}, sameAgeMembers},
+		{"group of the same age conflicts", map[string]string{"d": "group"}, sameAgeConflicts},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestGetLTPAKeyGroupFormerMembers(t *testing.T) {
+	status := openlibertyv1.OpenLibertyLTPAKeyGroupStatus{
+		FormerNamespaces: []string{"e", "b"},
+		Namespaces:       []openlibertyv1.LTPAKeyGroupNamespaceStatus{{Namespace: "c"}, {Namespace: "a", LastRotation: "100"}, {Namespace: "b", Message: "forbidden"}},
+	}
+	group := openlibertyv1.OpenLibertyLTPAKeyGroup{
+		Spec:   openlibertyv1.OpenLibertyLTPAKeyGroupSpec{Namespaces: []string{"a", "d"}},
+		Status: status,
+	}
+
+	tests := []Test{
+		{"namespaces removed from the group", []string{"b", "c", "e"}, getLTPAKeyGroupFormerMembers(&status, []string{"a", "d"})},
+		{"no former members", []string{}, getLTPAKeyGroupFormerMembers(&openlibertyv1.OpenLibertyLTPAKeyGroupStatus{Namespaces: status.Namespaces[:2]}, []string{"a", "c"})},
+		{"all the namespaces of a deleted group", []string{"a", "b", "c", "d", "e"}, getLTPAKeyGroupNamespaces(&group)},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestIsLTPAKeyGroupRotationAllowed(t *testing.T) {
+	members := []string{"a", "b"}
+	adopted := []openlibertyv1.LTPAKeyGroupNamespaceStatus{{Namespace: "a", LastRotation: "100"}, {Namespace: "b", LastRotation: "100"}}
+	withoutKeys := []openlibertyv1.LTPAKeyGroupNamespaceStatus{{Namespace: "a", LastRotation: "100"}, {Namespace: "b"}}
+	pending := []openlibertyv1.LTPAKeyGroupNamespaceStatus{{Namespace: "a", LastRotation: "100"}, {Namespace: "b", LastRotation: "50"}}
+	failed := []openlibertyv1.LTPAKeyGroupNamespaceStatus{{Namespace: "a", LastRotation: "100"}, {Namespace: "b", Message: "forbidden"}}
+
+	tests := []Test{
+		{"all namespaces use the keys", true, isLTPAKeyGroupRotationAllowed(adopted, members, "100")},
+		{"namespace without LTPA keys", true, isLTPAKeyGroupRotationAllowed(withoutKeys, members, "100")},
+		{"namespace uses the previous keys", false, isLTPAKeyGroupRotationAllowed(pending, members, "100")},
+		{"keys not mirrored into a namespace", false, isLTPAKeyGroupRotationAllowed(failed, members, "100")},
+		{"namespace without status", false, isLTPAKeyGroupRotationAllowed(adopted[:1], members, "100")},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestGetLTPAKeyGroupSyncedCondition(t *testing.T) {
+	synced, syncedReason, _ := getLTPAKeyGroupSyncedCondition([]openlibertyv1.LTPAKeyGroupNamespaceStatus{{Namespace: "a", LastRotation: "100"}, {Namespace: "b"}}, "100")
+	pending, pendingReason, _ := getLTPAKeyGroupSyncedCondition([]openlibertyv1.LTPAKeyGroupNamespaceStatus{{Namespace: "a", LastRotation: "50"}}, "100")
+	failed, failedReason, _ := getLTPAKeyGroupSyncedCondition([]openlibertyv1.LTPAKeyGroupNamespaceStatus{{Namespace: "a", LastRotation: "50"}, {Namespace: "b", Message: "forbidden"}}, "100")
+
+	tests := []Test{
+		{"synced", metav1.ConditionTrue, synced},
+		{"synced reason", "Synced", syncedReason},
+		{"adoption pending", metav1.ConditionFalse, pending},
+		{"adoption pending reason", "AdoptionPending", pendingReason},
+		{"mirror failed", metav1.ConditionFalse, failed},
+		{"mirror failed reason", "MirrorFailed", failedReason},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestGetLTPAKeyGroupRequeueAfter(t *testing.T) {
+	now := time.Date(2024, time.January, 10, 0, 0, 0, 0, time.UTC)
+	lastRotation := strconv.FormatInt(now.Unix(), 10)
+	interval := time.Hour
+
+	tests := []Test{
+		{"rotation disabled", ltpaKeyGroupSyncInterval, getLTPAKeyGroupRequeueAfter(lastRotation, 0, now)},
+		{"rotation later", ltpaKeyGroupSyncInterval, getLTPAKeyGroupRequeueAfter(lastRotation, interval, now)},
+		{"rotation soon", time.Minute, getLTPAKeyGroupRequeueAfter(lastRotation, interval, now.Add(interval-time.Minute))},
+		{"rotation due", time.Second, getLTPAKeyGroupRequeueAfter(lastRotation, interval, now.Add(2*interval))},
+		{"group secret name length", len(ltpaKeyGroupSecretRootName) + 11, len(getLTPAKeyGroupSecretName("group"))},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestIsManagedLTPAKeyGroupSecret(t *testing.T) {
+	group := openlibertyv1.OpenLibertyLTPAKeyGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: "group", Annotations: map[string]string{ltpaKeyGroupOperatorAnnotation: "operator-ns"}},
+		Spec:       openlibertyv1.OpenLibertyLTPAKeyGroupSpec{Namespaces: []string{"a"}},
+	}
+	createSecret := func(annotations map[string]string) *corev1.Secret {
+		return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: ltpaKeyGroupSecretRootName, Namespace: "a", Annotations: annotations}}
+	}
+
+	tests := []Test{
+		{"managed by the group", true, isManagedLTPAKeyGroupSecret(createSecret(map[string]string{ltpaKeyGroupAnnotation: "group", ltpaKeyGroupOperatorAnnotation: "operator-ns"}), &group)},
+		{"written before the operator annotation", true, isManagedLTPAKeyGroupSecret(createSecret(map[string]string{ltpaKeyGroupAnnotation: "group"}), &group)},
+		{"managed by another operator", false, isManagedLTPAKeyGroupSecret(createSecret(map[string]string{ltpaKeyGroupAnnotation: "group", ltpaKeyGroupOperatorAnnotation: "other-ns"}), &group)},
+		{"managed by another group", false, isManagedLTPAKeyGroupSecret(createSecret(map[string]string{ltpaKeyGroupAnnotation: "other", ltpaKeyGroupOperatorAnnotation: "operator-ns"}), &group)},
+		{"not a group Secret", false, isManagedLTPAKeyGroupSecret(createSecret(nil), &group)},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
@@ -2,12 +2,16 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/OpenLiberty/open-liberty-operator/utils"
+	"github.com/OpenLiberty/open-liberty-operator/utils/collector"
+	"github.com/OpenLiberty/open-liberty-operator/utils/metrics"
 	"github.com/application-stacks/runtime-component-operator/common"
 	oputils "github.com/application-stacks/runtime-component-operator/utils"
 	"github.com/go-logr/logr"
@@ -16,7 +20,9 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -27,18 +33,24 @@ import (
 
 const performanceDataFinalizer = "finalizer.openlibertyperformancedata.apps.openliberty.io"
 
+// The maximum time for reading and summarizing a performance data file
+const performanceDataSummaryTimeout = 5 * time.Minute
+
 // ReconcileOpenLibertyPerformanceData reconciles an OpenLibertyPerformanceData object
 type ReconcileOpenLibertyPerformanceData struct {
 	// This client, initialized using mgr.GetClient()() above, is a split client
 	// that reads objects from the cache and writes to the apiserver
 	oputils.ReconcilerBase
 	Log               logr.Logger
+	RestConfig        *rest.Config
 	PodInjectorClient utils.PodInjectorClient
 	watchNamespaces   []string
 }
 
 // +kubebuilder:rbac:groups=apps.openliberty.io,resources=openlibertyperformancedata;openlibertyperformancedata/status;openlibertyperformancedata/finalizers,verbs=get;list;watch;create;update;patch;delete,namespace=open-liberty-operator
 // +kubebuilder:rbac:groups=core,resources=pods;pods/exec,verbs=get;list;watch;create;update;patch;delete,namespace=open-liberty-operator
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;delete,namespace=open-liberty-operator
+// +kubebuilder:rbac:groups=apps.openliberty.io,resources=openlibertyapplications,verbs=get;list;watch,namespace=open-liberty-operator
 
 // Reconcile reads that state of the cluster for an OpenLibertyPerformanceData object and makes changes based on the state read
 // and what is in the OpenLibertyPerformanceData.Spec
@@ -92,9 +104,44 @@ func (r *ReconcileOpenLibertyPerformanceData) Reconcile(ctx context.Context, req
 		}
 	}
 
-	//do not reconcile if performance data collection already completed
+	//do not reconcile if performance data collection already completed, other than to summarize and export the performance data file
 	if oc := openlibertyv1.GetOperationCondtion(instance.Status.Conditions, openlibertyv1.OperationStatusConditionTypeCompleted); oc != nil && oc.Status == corev1.ConditionTrue {
-		return reconcile.Result{}, err
+		return r.reconcilePerformanceDataFile(reqLogger, instance)
+	}
+
+	perfCollector, err := collector.Get(instance.GetCollector())
+	if err != nil {
+		reqLogger.Error(err, "Invalid performance data collector")
+		r.GetRecorder().Event(instance, "Warning", "ProcessingError", err.Error())
+		instance.Status.SetCondition(openlibertyv1.OperationStatusCondition{
+			Type:    openlibertyv1.OperationStatusConditionTypeStarted,
+			Status:  corev1.ConditionFalse,
+			Reason:  "InvalidCollector",
+			Message: err.Error(),
+		})
+		instance.Status.ObservedGeneration = instance.GetObjectMeta().GetGeneration()
+		instance.Status.Versions.Reconciled = utils.OperandVersion
+		r.GetClient().Status().Update(context.TODO(), instance)
+		return reconcile.Result{}, nil
+	}
+
+	if err := validatePerformanceDataTarget(instance); err != nil {
+		reqLogger.Error(err, "Invalid OpenLibertyPerformanceData")
+		r.GetRecorder().Event(instance, "Warning", "ProcessingError", err.Error())
+		instance.Status.SetCondition(openlibertyv1.OperationStatusCondition{
+			Type:    openlibertyv1.OperationStatusConditionTypeStarted,
+			Status:  corev1.ConditionFalse,
+			Reason:  "Error",
+			Message: err.Error(),
+		})
+		instance.Status.ObservedGeneration = instance.GetObjectMeta().GetGeneration()
+		instance.Status.Versions.Reconciled = utils.OperandVersion
+		r.GetClient().Status().Update(context.TODO(), instance)
+		return reconcile.Result{}, nil
+	}
+
+	if instance.Spec.ApplicationRef != nil {
+		return r.reconcileApplicationPerformanceData(reqLogger, instance, perfCollector)
 	}
 
 	//check if Pod exists and running
@@ -107,6 +154,7 @@ func (r *ReconcileOpenLibertyPerformanceData) Reconcile(ctx context.Context, req
 		isWritingPerformanceData := isPerformanceDataRunning(instance)
 		if isWritingPerformanceData {
 			errMessage = utils.GetPerformanceDataConnectionLostMessage(instance.Spec.PodName)
+			metrics.CountOperation(metrics.OperationPerformanceData, false)
 		} else {
 			errMessage = "Failed to find a pod or pod is not in running state"
 		}
@@ -171,42 +219,12 @@ func (r *ReconcileOpenLibertyPerformanceData) Reconcile(ctx context.Context, req
 	}
 	defer r.PodInjectorClient.CloseConnection()
 
-	ns, err := oputils.GetOperatorNamespace()
-	if err != nil {
-		reqLogger.Info("Failed to get operator namespace, error: " + err.Error())
-	}
-
-	// When running the operator locally, `ns` will be empty string
-	if ns == "" {
-		// Since this method can be called directly from unit test, populate `watchNamespaces`.
-		if r.watchNamespaces == nil {
-			r.watchNamespaces, err = oputils.GetWatchNamespaces()
-			if err != nil {
-				reqLogger.Error(err, "Error getting watch namespace")
-				return reconcile.Result{}, err
-			}
-		}
-		// If the operator is running locally, use the first namespace in the `watchNamespaces`
-		// `watchNamespaces` must have at least one item
-		ns = r.watchNamespaces[0]
-	}
-
-	// Update setttings based on operator config map
-	configMap, err := r.GetOpConfigMap(OperatorName, ns)
-	if err != nil {
-		reqLogger.Info("Failed to get open-liberty-operator config map, error: " + err.Error())
-		oputils.CreateConfigMap(OperatorName)
-	} else {
-		common.LoadFromConfigMapWithAddedDefaults(common.Config, configMap, utils.DefaultLibertyOpConfig)
-	}
-
-	maxWorkers := common.LoadFromConfig(common.Config, utils.OpConfigPerformanceDataMaxWorkers)
-	if maxWorkers != "" {
-		r.PodInjectorClient.SetMaxWorkers("linperf", pod.Name, pod.Namespace, maxWorkers)
+	if err := r.setPodInjectorMaxWorkers(reqLogger, perfCollector.Name(), pod.Name, pod.Namespace); err != nil {
+		return reconcile.Result{}, err
 	}
 
-	encodedAttrs := utils.EncodeLinperfAttr(instance)
-	injectorStatus := r.PodInjectorClient.PollStatus("linperf", pod.Name, pod.Namespace, encodedAttrs)
+	encodedAttrs := collector.EncodeAttrs(perfCollector, instance)
+	injectorStatus := r.PodInjectorClient.PollStatus(perfCollector.Name(), pod.Name, pod.Namespace, encodedAttrs)
 	if injectorStatus != "done..." {
 		// exit on error
 		if strings.HasPrefix(injectorStatus, "error:") {
@@ -214,6 +232,7 @@ func (r *ReconcileOpenLibertyPerformanceData) Reconcile(ctx context.Context, req
 			err = fmt.Errorf("%s", errMessage)
 			reqLogger.Error(err, errMessage)
 			r.GetRecorder().Event(instance, "Warning", "ProcessingError", err.Error())
+			metrics.CountOperation(metrics.OperationPerformanceData, false)
 			instance.Status.SetCondition(openlibertyv1.OperationStatusCondition{
 				Type:   openlibertyv1.OperationStatusConditionTypeCompleted,
 				Status: corev1.ConditionFalse,
@@ -235,6 +254,7 @@ func (r *ReconcileOpenLibertyPerformanceData) Reconcile(ctx context.Context, req
 			err = fmt.Errorf("%s", errMessage)
 			reqLogger.Error(err, errMessage)
 			r.GetRecorder().Event(instance, "Warning", "ProcessingError", err.Error())
+			metrics.CountOperation(metrics.OperationPerformanceData, false)
 			instance.Status.SetCondition(openlibertyv1.OperationStatusCondition{
 				Type:   openlibertyv1.OperationStatusConditionTypeCompleted,
 				Status: corev1.ConditionFalse,
@@ -250,7 +270,7 @@ func (r *ReconcileOpenLibertyPerformanceData) Reconcile(ctx context.Context, req
 			r.GetClient().Status().Update(context.TODO(), instance)
 			return reconcile.Result{}, nil
 		} else if injectorStatus == "idle..." {
-			r.PodInjectorClient.StartScript("linperf", pod.Name, pod.Namespace, encodedAttrs)
+			r.PodInjectorClient.StartScript(perfCollector.Name(), pod.Name, pod.Namespace, encodedAttrs)
 		}
 
 		var errMessage string
@@ -282,7 +302,7 @@ func (r *ReconcileOpenLibertyPerformanceData) Reconcile(ctx context.Context, req
 	}
 
 	performanceDataFile := ""
-	fileNameOut := r.PodInjectorClient.PollLinperfFileName("linperf", pod.Name, pod.Namespace, encodedAttrs)
+	fileNameOut := r.PodInjectorClient.PollLinperfFileName(perfCollector.Name(), pod.Name, pod.Namespace, encodedAttrs)
 	if strings.HasPrefix(fileNameOut, "name:") {
 		performanceDataFile = strings.TrimPrefix(fileNameOut, "name:")
 		performanceDataFile = strings.TrimSuffix(performanceDataFile, "\n")
@@ -292,22 +312,608 @@ func (r *ReconcileOpenLibertyPerformanceData) Reconcile(ctx context.Context, req
 		Type:   openlibertyv1.OperationStatusConditionTypeCompleted,
 		Status: corev1.ConditionTrue,
 	})
+	metrics.CountOperation(metrics.OperationPerformanceData, true)
+	// the collection started when the pod injector was first reached
+	if started := instance.Status.GetCondition(openlibertyv1.OperationStatusConditionTypeStarted); started.LastTransitionTime != nil {
+		metrics.ObserveOperationDuration(metrics.OperationPerformanceData, time.Since(started.LastTransitionTime.Time))
+	}
 	instance.Status.PerformanceDataFile = performanceDataFile
 	instance.Status.ObservedGeneration = instance.GetObjectMeta().GetGeneration()
 	instance.Status.Versions.Reconciled = utils.OperandVersion
 	if err = r.GetClient().Status().Update(context.TODO(), instance); err == nil {
 		// cleanup pod refs
-		r.PodInjectorClient.CompleteScript("linperf", pod.Name, pod.Namespace, encodedAttrs)
+		r.PodInjectorClient.CompleteScript(perfCollector.Name(), pod.Name, pod.Namespace, encodedAttrs)
+		return r.reconcilePerformanceDataFile(reqLogger, instance)
+	}
+	return reconcile.Result{}, nil
+}
+
+// Returns an error unless exactly one of .spec.podName or .spec.applicationRef is set
+func validatePerformanceDataTarget(instance *openlibertyv1.OpenLibertyPerformanceData) error {
+	if (instance.Spec.PodName == "") == (instance.Spec.ApplicationRef == nil) {
+		return fmt.Errorf("exactly one of podName or applicationRef must be set")
+	}
+	return nil
+}
+
+// Loads the operator config map and applies performanceDataMaxWorkers to the pod injector
+func (r *ReconcileOpenLibertyPerformanceData) setPodInjectorMaxWorkers(reqLogger logr.Logger, collectorName string, podName string, podNamespace string) error {
+	ns, err := oputils.GetOperatorNamespace()
+	if err != nil {
+		reqLogger.Info("Failed to get operator namespace, error: " + err.Error())
+	}
+
+	// When running the operator locally, `ns` will be empty string
+	if ns == "" {
+		// Since this method can be called directly from unit test, populate `watchNamespaces`.
+		if r.watchNamespaces == nil {
+			r.watchNamespaces, err = oputils.GetWatchNamespaces()
+			if err != nil {
+				reqLogger.Error(err, "Error getting watch namespace")
+				return err
+			}
+		}
+		// If the operator is running locally, use the first namespace in the `watchNamespaces`
+		// `watchNamespaces` must have at least one item
+		ns = r.watchNamespaces[0]
+	}
+
+	// Update setttings based on operator config map
+	configMap, err := r.GetOpConfigMap(OperatorName, ns)
+	if err != nil {
+		reqLogger.Info("Failed to get open-liberty-operator config map, error: " + err.Error())
+		oputils.CreateConfigMap(OperatorName)
+	} else {
+		common.LoadFromConfigMapWithAddedDefaults(common.Config, configMap, utils.DefaultLibertyOpConfig)
+	}
+
+	maxWorkers := common.LoadFromConfig(common.Config, utils.OpConfigPerformanceDataMaxWorkers)
+	if maxWorkers != "" {
+		r.PodInjectorClient.SetMaxWorkers(collectorName, podName, podNamespace, maxWorkers)
+	}
+	return nil
+}
+
+// Gathers performance data from the pods of .spec.applicationRef that were running when the operation started, all at
+// the same time up to the performanceDataMaxWorkers limit of the pod injector. The outcome for each pod is recorded in
+// .status.pods and, once all pods have finished, summarized in the manifest ConfigMap.
+func (r *ReconcileOpenLibertyPerformanceData) reconcileApplicationPerformanceData(reqLogger logr.Logger, instance *openlibertyv1.OpenLibertyPerformanceData, perfCollector collector.Collector) (reconcile.Result, error) {
+	appName := instance.Spec.ApplicationRef.Name
+	if len(instance.Status.Pods) == 0 {
+		pods, err := getApplicationPods(r.GetClient(), instance.Namespace, appName)
+		message := fmt.Sprintf("Waiting for the pods of application '%s' to be in a running state.", appName)
+		if err != nil {
+			message = fmt.Sprintf("Failed to get the pods of application %s in namespace %s", appName, instance.Namespace)
+			reqLogger.Error(err, message)
+			r.GetRecorder().Event(instance, "Warning", "ProcessingError", message)
+		}
+		for _, pod := range pods {
+			if pod.Status.Phase == corev1.PodRunning && pod.DeletionTimestamp == nil {
+				instance.Status.Pods = append(instance.Status.Pods, openlibertyv1.PerformanceDataPodStatus{PodName: pod.Name})
+			}
+		}
+		if len(instance.Status.Pods) == 0 {
+			instance.Status.SetCondition(openlibertyv1.OperationStatusCondition{
+				Type:    openlibertyv1.OperationStatusConditionTypeStarted,
+				Status:  corev1.ConditionFalse,
+				Reason:  "Error",
+				Message: message,
+			})
+			instance.Status.ObservedGeneration = instance.GetObjectMeta().GetGeneration()
+			instance.Status.Versions.Reconciled = utils.OperandVersion
+			r.GetClient().Status().Update(context.TODO(), instance)
+			return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
+		}
+	}
+
+	var pendingPods []*openlibertyv1.PerformanceDataPodStatus
+	for i := range instance.Status.Pods {
+		if !isPerformanceDataPodFinished(&instance.Status.Pods[i]) {
+			pendingPods = append(pendingPods, &instance.Status.Pods[i])
+		}
+	}
+	if len(pendingPods) > 0 {
+		if r.PodInjectorClient.Connect() != nil {
+			message := "Failed to connect to the operator pod injector"
+			reqLogger.Info(message)
+			r.GetRecorder().Event(instance, "Warning", "ProcessingError", message)
+			instance.Status.SetCondition(openlibertyv1.OperationStatusCondition{
+				Type:    openlibertyv1.OperationStatusConditionTypeStarted,
+				Status:  corev1.ConditionFalse,
+				Reason:  "Error",
+				Message: message,
+			})
+			instance.Status.ObservedGeneration = instance.GetObjectMeta().GetGeneration()
+			instance.Status.Versions.Reconciled = utils.OperandVersion
+			r.GetClient().Status().Update(context.TODO(), instance)
+			return reconcile.Result{}, nil
+		}
+		defer r.PodInjectorClient.CloseConnection()
+		instance.Status.SetCondition(openlibertyv1.OperationStatusCondition{
+			Type:   openlibertyv1.OperationStatusConditionTypeStarted,
+			Status: corev1.ConditionTrue,
+		})
+
+		if err := r.setPodInjectorMaxWorkers(reqLogger, perfCollector.Name(), pendingPods[0].PodName, instance.Namespace); err != nil {
+			return reconcile.Result{}, err
+		}
+		for _, podStatus := range pendingPods {
+			r.reconcilePodPerformanceData(reqLogger, instance, perfCollector, podStatus)
+		}
+	}
+
+	for _, c := range getPerformanceDataPodsConditions(instance.Status.Pods) {
+		instance.Status.SetCondition(c)
+	}
+	instance.Status.ObservedGeneration = instance.GetObjectMeta().GetGeneration()
+	instance.Status.Versions.Reconciled = utils.OperandVersion
+	if oc := openlibertyv1.GetOperationCondtion(instance.Status.Conditions, openlibertyv1.OperationStatusConditionTypeCompleted); oc != nil && oc.Reason == "InProgress" {
+		// requeue when waiting on performance data collection
+		r.GetClient().Status().Update(context.TODO(), instance)
+		return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
+	}
+	return r.reconcileApplicationPerformanceDataFiles(reqLogger, instance)
+}
+
+// Starts gathering performance data from the pod or polls the pod injector for its progress, recording the outcome in
+// the conditions of the pod
+func (r *ReconcileOpenLibertyPerformanceData) reconcilePodPerformanceData(reqLogger logr.Logger, instance *openlibertyv1.OpenLibertyPerformanceData, perfCollector collector.Collector, podStatus *openlibertyv1.PerformanceDataPodStatus) {
+	pod := &corev1.Pod{}
+	err := r.GetClient().Get(context.TODO(), types.NamespacedName{Name: podStatus.PodName, Namespace: instance.Namespace}, pod)
+	if err != nil || pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+		message := "Failed to find a pod or pod is not in running state"
+		if podStatus.StartTime != nil {
+			message = utils.GetPerformanceDataConnectionLostMessage(podStatus.PodName)
+		}
+		r.setPerformanceDataPodFailed(reqLogger, instance, podStatus, "ConnectionLost", message)
+		// release the worker or the place in the queue of the pod injector that was held for the pod
+		r.PodInjectorClient.CompleteScript(perfCollector.Name(), podStatus.PodName, instance.Namespace, collector.EncodeAttrs(perfCollector, instance))
+		return
+	}
+
+	encodedAttrs := collector.EncodeAttrs(perfCollector, instance)
+	injectorStatus := r.PodInjectorClient.PollStatus(perfCollector.Name(), pod.Name, pod.Namespace, encodedAttrs)
+	switch {
+	case injectorStatus == "done...":
+		fileNameOut := r.PodInjectorClient.PollLinperfFileName(perfCollector.Name(), pod.Name, pod.Namespace, encodedAttrs)
+		if strings.HasPrefix(fileNameOut, "name:") {
+			podStatus.PerformanceDataFile = strings.TrimSuffix(strings.TrimPrefix(fileNameOut, "name:"), "\n")
+		}
+		podStatus.CompletionTime = &metav1.Time{Time: time.Now()}
+		podStatus.Conditions = openlibertyv1.SetOperationCondtion(podStatus.Conditions, openlibertyv1.OperationStatusCondition{
+			Type:   openlibertyv1.OperationStatusConditionTypeCompleted,
+			Status: corev1.ConditionTrue,
+		})
+		metrics.CountOperation(metrics.OperationPerformanceData, true)
+		if podStatus.StartTime != nil {
+			metrics.ObserveOperationDuration(metrics.OperationPerformanceData, podStatus.CompletionTime.Sub(podStatus.StartTime.Time))
+		}
+		// cleanup pod refs
+		r.PodInjectorClient.CompleteScript(perfCollector.Name(), pod.Name, pod.Namespace, encodedAttrs)
+	case strings.HasPrefix(injectorStatus, "error:"):
+		r.setPerformanceDataPodFailed(reqLogger, instance, podStatus, "Error", strings.TrimPrefix(injectorStatus, "error:"))
+	case injectorStatus == "idle..." && podStatus.StartTime != nil:
+		r.setPerformanceDataPodFailed(reqLogger, instance, podStatus, "ConnectionLost", utils.GetPerformanceDataConnectionLostMessage(pod.Name))
+	case injectorStatus == "toomanyworkers...":
+		podStatus.Conditions = openlibertyv1.SetOperationCondtion(podStatus.Conditions, openlibertyv1.OperationStatusCondition{
+			Type:    openlibertyv1.OperationStatusConditionTypeCompleted,
+			Status:  corev1.ConditionFalse,
+			Reason:  "TooManyWorkers",
+			Message: "The operator performance data queue is full. Waiting for a worker to become available...",
+		})
+	case injectorStatus == "idle..." || injectorStatus == "writing...":
+		if injectorStatus == "idle..." {
+			r.PodInjectorClient.StartScript(perfCollector.Name(), pod.Name, pod.Namespace, encodedAttrs)
+			podStatus.StartTime = &metav1.Time{Time: time.Now()}
+		}
+		podStatus.Conditions = openlibertyv1.SetOperationCondtion(podStatus.Conditions, openlibertyv1.OperationStatusCondition{
+			Type:    openlibertyv1.OperationStatusConditionTypeCompleted,
+			Status:  corev1.ConditionFalse,
+			Reason:  "InProgress",
+			Message: utils.GetPerformanceDataWritingMessage(pod.Name),
+		})
+	}
+}
+
+func (r *ReconcileOpenLibertyPerformanceData) setPerformanceDataPodFailed(reqLogger logr.Logger, instance *openlibertyv1.OpenLibertyPerformanceData, podStatus *openlibertyv1.PerformanceDataPodStatus, reason string, message string) {
+	err := fmt.Errorf("%s", message)
+	reqLogger.Error(err, "Failed to gather the performance data of pod "+podStatus.PodName)
+	r.GetRecorder().Event(instance, "Warning", "ProcessingError", err.Error())
+	metrics.CountOperation(metrics.OperationPerformanceData, false)
+	podStatus.Conditions = openlibertyv1.SetOperationCondtion(podStatus.Conditions, openlibertyv1.OperationStatusCondition{
+		Type:   openlibertyv1.OperationStatusConditionTypeCompleted,
+		Status: corev1.ConditionFalse,
+	})
+	podStatus.Conditions = openlibertyv1.SetOperationCondtion(podStatus.Conditions, openlibertyv1.OperationStatusCondition{
+		Type:    openlibertyv1.OperationStatusConditionTypeFailed,
+		Status:  corev1.ConditionTrue,
+		Reason:  reason,
+		Message: message,
+	})
+}
+
+// Returns true once the pod has generated its performance data file or has failed to
+func isPerformanceDataPodFinished(podStatus *openlibertyv1.PerformanceDataPodStatus) bool {
+	if oc := openlibertyv1.GetOperationCondtion(podStatus.Conditions, openlibertyv1.OperationStatusConditionTypeCompleted); oc != nil && oc.Status == corev1.ConditionTrue {
+		return true
+	}
+	oc := openlibertyv1.GetOperationCondtion(podStatus.Conditions, openlibertyv1.OperationStatusConditionTypeFailed)
+	return oc != nil && oc.Status == corev1.ConditionTrue
+}
+
+// Returns the conditions that summarize the performance data of the pods. The Completed condition is in progress while
+// any pod is gathering performance data. Once all pods have finished, it is true if any pod generated its performance
+// data file, listing the pods that failed in its message, or false along with a true Failed condition otherwise.
+func getPerformanceDataPodsConditions(podStatuses []openlibertyv1.PerformanceDataPodStatus) []openlibertyv1.OperationStatusCondition {
+	pending := 0
+	failedPods := []string{}
+	for i := range podStatuses {
+		if !isPerformanceDataPodFinished(&podStatuses[i]) {
+			pending++
+		} else if oc := openlibertyv1.GetOperationCondtion(podStatuses[i].Conditions, openlibertyv1.OperationStatusConditionTypeFailed); oc != nil && oc.Status == corev1.ConditionTrue {
+			failedPods = append(failedPods, podStatuses[i].PodName)
+		}
+	}
+	if pending > 0 {
+		return []openlibertyv1.OperationStatusCondition{{
+			Type:    openlibertyv1.OperationStatusConditionTypeCompleted,
+			Status:  corev1.ConditionFalse,
+			Reason:  "InProgress",
+			Message: fmt.Sprintf("Gathering performance data from %d of %d pods", pending, len(podStatuses)),
+		}}
+	}
+	if len(failedPods) == len(podStatuses) {
+		return []openlibertyv1.OperationStatusCondition{{
+			Type:   openlibertyv1.OperationStatusConditionTypeCompleted,
+			Status: corev1.ConditionFalse,
+		}, {
+			Type:    openlibertyv1.OperationStatusConditionTypeFailed,
+			Status:  corev1.ConditionTrue,
+			Reason:  "Error",
+			Message: "Failed to gather performance data from pods " + strings.Join(failedPods, ", "),
+		}}
+	}
+	completed := openlibertyv1.OperationStatusCondition{
+		Type:   openlibertyv1.OperationStatusConditionTypeCompleted,
+		Status: corev1.ConditionTrue,
+	}
+	if len(failedPods) > 0 {
+		completed.Message = "Failed to gather performance data from pods " + strings.Join(failedPods, ", ")
+	}
+	return []openlibertyv1.OperationStatusCondition{completed}
+}
+
+// The summary manifest of the performance data of the pods of an application
+type performanceDataManifest struct {
+	Name        string                       `json:"name"`
+	Application string                       `json:"application"`
+	Collector   string                       `json:"collector"`
+	Timespan    int                          `json:"timespan"`
+	Interval    int                          `json:"interval"`
+	Pods        []performanceDataManifestPod `json:"pods"`
+}
+
+type performanceDataManifestPod struct {
+	PodName             string                                `json:"podName"`
+	PerformanceDataFile string                                `json:"performanceDataFile,omitempty"`
+	StartTime           *metav1.Time                          `json:"startTime,omitempty"`
+	CompletionTime      *metav1.Time                          `json:"completionTime,omitempty"`
+	Export              *openlibertyv1.OperationExportStatus  `json:"export,omitempty"`
+	Summary             *openlibertyv1.PerformanceDataSummary `json:"summary,omitempty"`
+	Error               string                                `json:"error,omitempty"`
+}
+
+// Returns the summary manifest of the performance data of the pods, listing the file, time window and failure of each pod
+func getPerformanceDataManifest(instance *openlibertyv1.OpenLibertyPerformanceData) (string, error) {
+	manifest := performanceDataManifest{
+		Name:        instance.Name,
+		Application: instance.Spec.ApplicationRef.Name,
+		Collector:   instance.GetCollector(),
+		Timespan:    instance.GetTimespan(),
+		Interval:    instance.GetInterval(),
+		Pods:        []performanceDataManifestPod{},
+	}
+	for _, podStatus := range instance.Status.Pods {
+		pod := performanceDataManifestPod{
+			PodName:             podStatus.PodName,
+			PerformanceDataFile: podStatus.PerformanceDataFile,
+			StartTime:           podStatus.StartTime,
+			CompletionTime:      podStatus.CompletionTime,
+			Export:              podStatus.Export,
+			Summary:             podStatus.Summary,
+		}
+		if oc := openlibertyv1.GetOperationCondtion(podStatus.Conditions, openlibertyv1.OperationStatusConditionTypeFailed); oc != nil && oc.Status == corev1.ConditionTrue {
+			pod.Error = oc.Message
+		} else if oc := openlibertyv1.GetOperationCondtion(podStatus.Conditions, openlibertyv1.OperationStatusConditionTypeExported); oc != nil && oc.Status == corev1.ConditionFalse {
+			pod.Error = oc.Message
+		}
+		manifest.Pods = append(manifest.Pods, pod)
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// Writes the summary manifest to the <name>-manifest ConfigMap, which is owned by the instance
+func (r *ReconcileOpenLibertyPerformanceData) reconcilePerformanceDataManifest(instance *openlibertyv1.OpenLibertyPerformanceData) error {
+	manifest, err := getPerformanceDataManifest(instance)
+	if err != nil {
+		return err
+	}
+	configMap := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: instance.Name + "-manifest", Namespace: instance.Namespace}}
+	err = r.CreateOrUpdate(configMap, instance, func() error {
+		configMap.Data = map[string]string{"manifest.json": manifest}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	instance.Status.Manifest = configMap.Name
+	return nil
+}
+
+// Summarizes and uploads the performance data file of each pod, then refreshes the summary manifest so that it lists the
+// summaries and the uploaded files
+func (r *ReconcileOpenLibertyPerformanceData) reconcileApplicationPerformanceDataFiles(reqLogger logr.Logger, instance *openlibertyv1.OpenLibertyPerformanceData) (reconcile.Result, error) {
+	processing := false
+	podConditions := make([][]openlibertyv1.OperationStatusCondition, len(instance.Status.Pods))
+	for i := range instance.Status.Pods {
+		if r.reconcilePodPerformanceDataFile(reqLogger, instance, &instance.Status.Pods[i]) {
+			processing = true
+		}
+		podConditions[i] = instance.Status.Pods[i].Conditions
+	}
+	if oc, found := getPodsSummarizedCondition(podConditions); found {
+		instance.Status.Conditions = openlibertyv1.SetOperationCondtion(instance.Status.Conditions, oc)
+	}
+	if oc, found := getPodsExportedCondition(podConditions, "performance data files"); found {
+		instance.Status.Conditions = openlibertyv1.SetOperationCondtion(instance.Status.Conditions, oc)
+	}
+	if err := r.reconcilePerformanceDataManifest(instance); err != nil {
+		reqLogger.Error(err, "Failed to write the performance data manifest")
+		r.GetRecorder().Event(instance, "Warning", "ProcessingError", err.Error())
+	}
+	r.GetClient().Status().Update(context.TODO(), instance)
+	if processing {
+		return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
+	}
+	return reconcile.Result{}, nil
+}
+
+// Summarizes the performance data file when its collector supports it, then uploads it when .spec.export is set,
+// polling the workers until both have finished
+func (r *ReconcileOpenLibertyPerformanceData) reconcilePerformanceDataFile(reqLogger logr.Logger, instance *openlibertyv1.OpenLibertyPerformanceData) (reconcile.Result, error) {
+	if instance.Spec.ApplicationRef != nil {
+		return r.reconcileApplicationPerformanceDataFiles(reqLogger, instance)
+	}
+	target := &openlibertyv1.PerformanceDataPodStatus{
+		PodName:             instance.Spec.PodName,
+		PerformanceDataFile: instance.Status.PerformanceDataFile,
+		Export:              instance.Status.Export,
+		Summary:             instance.Status.Summary,
+		Conditions:          instance.Status.Conditions,
+	}
+	if !isPerformanceDataFilePending(instance, target) {
+		return reconcile.Result{}, nil
+	}
+	processing := r.reconcilePodPerformanceDataFile(reqLogger, instance, target)
+	instance.Status.Conditions = target.Conditions
+	instance.Status.Export = target.Export
+	instance.Status.Summary = target.Summary
+	r.GetClient().Status().Update(context.TODO(), instance)
+	if processing {
+		return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
 	}
 	return reconcile.Result{}, nil
 }
 
+// Returns true while the performance data file of the target is to be summarized or uploaded
+func isPerformanceDataFilePending(instance *openlibertyv1.OpenLibertyPerformanceData, target *openlibertyv1.PerformanceDataPodStatus) bool {
+	if target.PerformanceDataFile == "" {
+		return false
+	}
+	if perfCollector, err := collector.Get(instance.GetCollector()); err == nil {
+		if _, ok := perfCollector.(collector.Summarizer); ok {
+			if oc := openlibertyv1.GetOperationCondtion(target.Conditions, openlibertyv1.OperationStatusConditionTypeSummarized); oc == nil || oc.Status == corev1.ConditionUnknown {
+				return true
+			}
+		}
+	}
+	oc := openlibertyv1.GetOperationCondtion(target.Conditions, openlibertyv1.OperationStatusConditionTypeExported)
+	return instance.Spec.Export != nil && (oc == nil || oc.Status == corev1.ConditionUnknown)
+}
+
+// Summarizes and then uploads the performance data file of the target pod. The summary and the upload run one after
+// the other in the worker of the pod. Returns true while either is in progress.
+func (r *ReconcileOpenLibertyPerformanceData) reconcilePodPerformanceDataFile(reqLogger logr.Logger, instance *openlibertyv1.OpenLibertyPerformanceData, target *openlibertyv1.PerformanceDataPodStatus) bool {
+	if r.reconcilePodPerformanceDataSummary(reqLogger, instance, target) {
+		return true
+	}
+	return r.reconcilePodPerformanceDataExport(reqLogger, instance, target)
+}
+
+// Parses the performance data file of the target pod into a summary when its collector supports it, recording the
+// outcome in the conditions and summary of the target. Returns true while the summary is in progress.
+func (r *ReconcileOpenLibertyPerformanceData) reconcilePodPerformanceDataSummary(reqLogger logr.Logger, instance *openlibertyv1.OpenLibertyPerformanceData, target *openlibertyv1.PerformanceDataPodStatus) bool {
+	perfCollector, err := collector.Get(instance.GetCollector())
+	if err != nil {
+		return false
+	}
+	summarizer, ok := perfCollector.(collector.Summarizer)
+	oc := openlibertyv1.GetOperationCondtion(target.Conditions, openlibertyv1.OperationStatusConditionTypeSummarized)
+	if !ok || target.PerformanceDataFile == "" || (oc != nil && oc.Status != corev1.ConditionUnknown) {
+		return false
+	}
+
+	workerKey := getWorkerKey(string(instance.UID), target.PodName, 0)
+	status, result := pollWorker(workerKey)
+	switch status {
+	case workerStatusRunning:
+		return true
+	case workerStatusDone:
+		clearWorkerResult(workerKey)
+		if result.err != nil {
+			reqLogger.Error(result.err, "Failed to summarize the performance data file of pod "+target.PodName)
+			r.GetRecorder().Event(instance, "Warning", "ProcessingError", result.err.Error())
+			target.Conditions = openlibertyv1.SetOperationCondtion(target.Conditions, openlibertyv1.OperationStatusCondition{
+				Type:    openlibertyv1.OperationStatusConditionTypeSummarized,
+				Status:  corev1.ConditionFalse,
+				Reason:  "Error",
+				Message: result.err.Error(),
+			})
+		} else {
+			target.Summary = result.summary
+			target.Conditions = openlibertyv1.SetOperationCondtion(target.Conditions, openlibertyv1.OperationStatusCondition{
+				Type:   openlibertyv1.OperationStatusConditionTypeSummarized,
+				Status: corev1.ConditionTrue,
+			})
+		}
+		return false
+	}
+
+	podName, podNamespace, file := target.PodName, instance.Namespace, target.PerformanceDataFile
+	started := startWorker(workerKey, performanceDataSummaryTimeout, func(ctx context.Context) workerResult {
+		reader, writer := io.Pipe()
+		// closing the reader stops the copy from the pod if the file can not be parsed
+		defer reader.Close()
+		go func() {
+			_, err := utils.StreamCommandInContainer(ctx, r.RestConfig, podName, podNamespace, "app", []string{"cat", file}, writer)
+			writer.CloseWithError(err)
+		}()
+		result := workerResult{file: file}
+		result.summary, result.err = summarizer.Summarize(reader)
+		return result
+	})
+	c := openlibertyv1.OperationStatusCondition{
+		Type:    openlibertyv1.OperationStatusConditionTypeSummarized,
+		Status:  corev1.ConditionUnknown,
+		Reason:  "InProgress",
+		Message: "Summarizing the performance data file " + file,
+	}
+	if !started {
+		c.Reason = "TooManyWorkers"
+		c.Message = "The operator worker queue is full. Waiting for a worker to become available..."
+	}
+	target.Conditions = openlibertyv1.SetOperationCondtion(target.Conditions, c)
+	return true
+}
+
+// Returns a Summarized condition that is unknown while the performance data file of any pod is being summarized, true
+// once the files of all pods are summarized, or false if the file of any pod could not be summarized
+func getPodsSummarizedCondition(podConditions [][]openlibertyv1.OperationStatusCondition) (openlibertyv1.OperationStatusCondition, bool) {
+	summaries, pending, failed := 0, 0, 0
+	for _, conditions := range podConditions {
+		oc := openlibertyv1.GetOperationCondtion(conditions, openlibertyv1.OperationStatusConditionTypeSummarized)
+		if oc == nil {
+			continue
+		}
+		summaries++
+		switch oc.Status {
+		case corev1.ConditionUnknown:
+			pending++
+		case corev1.ConditionFalse:
+			failed++
+		}
+	}
+	if summaries == 0 {
+		return openlibertyv1.OperationStatusCondition{}, false
+	}
+	c := openlibertyv1.OperationStatusCondition{
+		Type:   openlibertyv1.OperationStatusConditionTypeSummarized,
+		Status: corev1.ConditionTrue,
+	}
+	if pending > 0 {
+		c.Status, c.Reason, c.Message = corev1.ConditionUnknown, "InProgress", fmt.Sprintf("Summarizing the performance data files of %d of %d pods", pending, summaries)
+	} else if failed > 0 {
+		c.Status, c.Reason, c.Message = corev1.ConditionFalse, "Error", fmt.Sprintf("The performance data files of %d of %d pods failed to summarize", failed, summaries)
+	}
+	return c, true
+}
+
+// Uploads the performance data file of the target pod, recording the outcome in the conditions and export of the
+// target. Returns true while the export is in progress.
+func (r *ReconcileOpenLibertyPerformanceData) reconcilePodPerformanceDataExport(reqLogger logr.Logger, instance *openlibertyv1.OpenLibertyPerformanceData, target *openlibertyv1.PerformanceDataPodStatus) bool {
+	oc := openlibertyv1.GetOperationCondtion(target.Conditions, openlibertyv1.OperationStatusConditionTypeExported)
+	if instance.Spec.Export == nil || target.PerformanceDataFile == "" || (oc != nil && oc.Status != corev1.ConditionUnknown) {
+		return false
+	}
+
+	workerKey := getWorkerKey(string(instance.UID), target.PodName, 0)
+	status, result := pollWorker(workerKey)
+	switch status {
+	case workerStatusRunning:
+		return true
+	case workerStatusDone:
+		clearWorkerResult(workerKey)
+		if result.exportErr != nil {
+			r.setPerformanceDataExportFailed(reqLogger, instance, target, result.exportErr)
+		} else {
+			target.Export = result.export
+			target.Conditions = openlibertyv1.SetOperationCondtion(target.Conditions, openlibertyv1.OperationStatusCondition{
+				Type:   openlibertyv1.OperationStatusConditionTypeExported,
+				Status: corev1.ConditionTrue,
+			})
+		}
+		return false
+	}
+
+	exportClient, err := getExportClient(r.GetClient(), instance.Namespace, instance.Spec.Export)
+	if err != nil {
+		r.setPerformanceDataExportFailed(reqLogger, instance, target, err)
+		return false
+	}
+	podName, podNamespace, file := target.PodName, instance.Namespace, target.PerformanceDataFile
+	export := instance.Spec.Export.DeepCopy()
+	started := startWorker(workerKey, 0, func(ctx context.Context) workerResult {
+		result := workerResult{file: file}
+		result.export, result.exportErr = exportFile(ctx, r.RestConfig, podName, podNamespace, file, export, exportClient)
+		return result
+	})
+	c := openlibertyv1.OperationStatusCondition{
+		Type:    openlibertyv1.OperationStatusConditionTypeExported,
+		Status:  corev1.ConditionUnknown,
+		Reason:  "InProgress",
+		Message: "Uploading the performance data file to bucket " + instance.Spec.Export.Bucket,
+	}
+	if !started {
+		c.Reason = "TooManyWorkers"
+		c.Message = "The operator export queue is full. Waiting for a worker to become available..."
+	}
+	target.Conditions = openlibertyv1.SetOperationCondtion(target.Conditions, c)
+	return true
+}
+
+func (r *ReconcileOpenLibertyPerformanceData) setPerformanceDataExportFailed(reqLogger logr.Logger, instance *openlibertyv1.OpenLibertyPerformanceData, target *openlibertyv1.PerformanceDataPodStatus, err error) {
+	reqLogger.Error(err, "Failed to export the performance data file of pod "+target.PodName)
+	r.GetRecorder().Event(instance, "Warning", "ProcessingError", err.Error())
+	target.Conditions = openlibertyv1.SetOperationCondtion(target.Conditions, openlibertyv1.OperationStatusCondition{
+		Type:    openlibertyv1.OperationStatusConditionTypeExported,
+		Status:  corev1.ConditionFalse,
+		Reason:  "Error",
+		Message: err.Error(),
+	})
+}
+
 func (r *ReconcileOpenLibertyPerformanceData) finalizeOpenLibertyPerformanceData(reqLogger logr.Logger, olpd *openlibertyv1.OpenLibertyPerformanceData) error {
+	cancelWorkers(string(olpd.UID))
+	clearWorkerResults(string(olpd.UID))
+	perfCollector, err := collector.Get(olpd.GetCollector())
+	if err != nil {
+		return nil
+	}
 	if connErr := r.PodInjectorClient.Connect(); connErr != nil {
 		return connErr
 	}
-	encodedAttrs := utils.EncodeLinperfAttr(olpd)
-	r.PodInjectorClient.CompleteScript("linperf", olpd.Spec.PodName, olpd.Namespace, encodedAttrs)
+	encodedAttrs := collector.EncodeAttrs(perfCollector, olpd)
+	if olpd.Spec.ApplicationRef != nil {
+		for _, podStatus := range olpd.Status.Pods {
+			r.PodInjectorClient.CompleteScript(perfCollector.Name(), podStatus.PodName, olpd.Namespace, encodedAttrs)
+		}
+	} else {
+		r.PodInjectorClient.CompleteScript(perfCollector.Name(), olpd.Spec.PodName, olpd.Namespace, encodedAttrs)
+	}
 	r.PodInjectorClient.CloseConnection()
 	return nil
 }
@@ -0,0 +1,127 @@
+package controller
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	openlibertyv1 "github.com/OpenLiberty/open-liberty-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func getPerformanceDataPodStatus(name string, conditions ...openlibertyv1.OperationStatusCondition) openlibertyv1.PerformanceDataPodStatus {
+	return openlibertyv1.PerformanceDataPodStatus{PodName: name, Conditions: conditions}
+}
+
+var (
+	performanceDataCompleted  = openlibertyv1.OperationStatusCondition{Type: openlibertyv1.OperationStatusConditionTypeCompleted, Status: corev1.ConditionTrue}
+	performanceDataInProgress = openlibertyv1.OperationStatusCondition{Type: openlibertyv1.OperationStatusConditionTypeCompleted, Status: corev1.ConditionFalse, Reason: "InProgress"}
+	performanceDataFailed     = openlibertyv1.OperationStatusCondition{Type: openlibertyv1.OperationStatusConditionTypeFailed, Status: corev1.ConditionTrue, Reason: "Error", Message: "failed"}
+)
+
+func TestValidatePerformanceDataTarget(t *testing.T) {
+	app := &corev1.LocalObjectReference{Name: "app"}
+	validate := func(spec openlibertyv1.OpenLibertyPerformanceDataSpec) bool {
+		return validatePerformanceDataTarget(&openlibertyv1.OpenLibertyPerformanceData{Spec: spec}) == nil
+	}
+
+	tests := []Test{
+		{"pod name", true, validate(openlibertyv1.OpenLibertyPerformanceDataSpec{PodName: "app-0"})},
+		{"application", true, validate(openlibertyv1.OpenLibertyPerformanceDataSpec{ApplicationRef: app})},
+		{"no target", false, validate(openlibertyv1.OpenLibertyPerformanceDataSpec{})},
+		{"both targets", false, validate(openlibertyv1.OpenLibertyPerformanceDataSpec{PodName: "app-0", ApplicationRef: app})},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestGetPerformanceDataPodsConditions(t *testing.T) {
+	inProgress := getPerformanceDataPodsConditions([]openlibertyv1.PerformanceDataPodStatus{
+		getPerformanceDataPodStatus("app-0", performanceDataCompleted),
+		getPerformanceDataPodStatus("app-1", performanceDataInProgress),
+		getPerformanceDataPodStatus("app-2"),
+	})
+	partial := getPerformanceDataPodsConditions([]openlibertyv1.PerformanceDataPodStatus{
+		getPerformanceDataPodStatus("app-0", performanceDataCompleted),
+		getPerformanceDataPodStatus("app-1", performanceDataFailed),
+	})
+	failed := getPerformanceDataPodsConditions([]openlibertyv1.PerformanceDataPodStatus{
+		getPerformanceDataPodStatus("app-0", performanceDataFailed),
+		getPerformanceDataPodStatus("app-1", performanceDataFailed),
+	})
+
+	tests := []Test{
+		{"in progress", corev1.ConditionFalse, inProgress[0].Status},
+		{"in progress message", "Gathering performance data from 2 of 3 pods", inProgress[0].Message},
+		{"partially failed", corev1.ConditionTrue, partial[0].Status},
+		{"partially failed message", "Failed to gather performance data from pods app-1", partial[0].Message},
+		{"failed conditions", 2, len(failed)},
+		{"failed", corev1.ConditionTrue, failed[1].Status},
+		{"failed message", "Failed to gather performance data from pods app-0, app-1", failed[1].Message},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestGetPerformanceDataManifest(t *testing.T) {
+	start := metav1.NewTime(time.Date(2024, time.January, 10, 9, 30, 0, 0, time.UTC))
+	end := metav1.NewTime(time.Date(2024, time.January, 10, 9, 34, 5, 0, time.UTC))
+	completed := getPerformanceDataPodStatus("app-0", performanceDataCompleted)
+	completed.PerformanceDataFile = "/serviceability/ns/app-0/performanceData/linperf_RESULTS_perf.2024110.9300.tar.gz"
+	completed.StartTime, completed.CompletionTime = &start, &end
+	instance := &openlibertyv1.OpenLibertyPerformanceData{
+		ObjectMeta: metav1.ObjectMeta{Name: "perf", Namespace: "ns"},
+		Spec:       openlibertyv1.OpenLibertyPerformanceDataSpec{ApplicationRef: &corev1.LocalObjectReference{Name: "app"}},
+		Status: openlibertyv1.OpenLibertyPerformanceDataStatus{
+			Pods: []openlibertyv1.PerformanceDataPodStatus{completed, getPerformanceDataPodStatus("app-1", performanceDataFailed)},
+		},
+	}
+	manifest, err := getPerformanceDataManifest(instance)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	tests := []Test{
+		{"application", true, strings.Contains(manifest, `"application": "app"`)},
+		{"collector", true, strings.Contains(manifest, `"collector": "linperf"`)},
+		{"file", true, strings.Contains(manifest, `"performanceDataFile": "`+completed.PerformanceDataFile+`"`)},
+		{"time window", true, strings.Contains(manifest, `"startTime": "2024-01-10T09:30:00Z",
+      "completionTime": "2024-01-10T09:34:05Z"`)},
+		{"failure", true, strings.Contains(manifest, `"podName": "app-1",
+      "error": "failed"`)},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestGetPodsSummarizedCondition(t *testing.T) {
+	summarized := func(status corev1.ConditionStatus) []openlibertyv1.OperationStatusCondition {
+		if status == "" {
+			return nil
+		}
+		return []openlibertyv1.OperationStatusCondition{{Type: openlibertyv1.OperationStatusConditionTypeSummarized, Status: status}}
+	}
+	status := func(podConditions ...[]openlibertyv1.OperationStatusCondition) corev1.ConditionStatus {
+		oc, found := getPodsSummarizedCondition(podConditions)
+		if !found {
+			return ""
+		}
+		return oc.Status
+	}
+	pending, _ := getPodsSummarizedCondition([][]openlibertyv1.OperationStatusCondition{summarized(corev1.ConditionUnknown), summarized(corev1.ConditionTrue), summarized("")})
+
+	tests := []Test{
+		{"no summaries", corev1.ConditionStatus(""), status(summarized(""))},
+		{"summarized", corev1.ConditionTrue, status(summarized(corev1.ConditionTrue), summarized(""))},
+		{"failed", corev1.ConditionFalse, status(summarized(corev1.ConditionTrue), summarized(corev1.ConditionFalse))},
+		{"in progress", corev1.ConditionUnknown, status(summarized(corev1.ConditionUnknown), summarized(corev1.ConditionFalse))},
+		{"in progress message", "Summarizing the performance data files of 1 of 2 pods", pending.Message},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
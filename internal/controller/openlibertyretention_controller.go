@@ -0,0 +1,350 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	openlibertyv1 "github.com/OpenLiberty/open-liberty-operator/api/v1"
+	"github.com/OpenLiberty/open-liberty-operator/utils"
+	oputils "github.com/application-stacks/runtime-component-operator/utils"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// Interval between the sweeps of the serviceability storage of an application
+const serviceabilityRetentionInterval = time.Hour
+
+// Maximum number of files deleted by one command
+const serviceabilityDeleteBatchSize = 100
+
+// Folders of the pod folders in the serviceability storage whose files are subject to .spec.serviceability.retention
+var serviceabilityFileTypes = []string{"serverDumps", "performanceData", "traces", "logs"}
+
+// Pod folders are named after the pod hostname, which is a DNS label
+var serviceabilityPodFolderRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// A file in the folder of a pod in the serviceability storage, i.e. /serviceability/<namespace>/<pod>/<fileType>/<name>
+type serviceabilityFile struct {
+	pod      string
+	fileType string
+	path     string
+	size     int64
+	modTime  time.Time
+}
+
+// ReconcileOpenLibertyRetention periodically deletes the files in the serviceability storage of an OpenLibertyApplication
+// that do not meet .spec.serviceability.retention
+type ReconcileOpenLibertyRetention struct {
+	// This client, initialized using mgr.Client() above, is a split client
+	// that reads objects from the cache and writes to the apiserver
+	Client     client.Client
+	Recorder   record.EventRecorder
+	RestConfig *rest.Config
+	Log        logr.Logger
+}
+
+// +kubebuilder:rbac:groups=apps.openliberty.io,resources=openlibertyapplications,verbs=get;list;watch,namespace=open-liberty-operator
+// +kubebuilder:rbac:groups=core,resources=pods;pods/exec,verbs=get;list;watch;create,namespace=open-liberty-operator
+
+func (r *ReconcileOpenLibertyRetention) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	reqLogger := r.Log.WithValues("Request.Namespace", request.Namespace, "Request.Name", request.Name)
+
+	instance := &openlibertyv1.OpenLibertyApplication{}
+	err := r.Client.Get(context.TODO(), request.NamespacedName, instance)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	if instance.GetServiceability() == nil || instance.GetServiceability().GetRetention() == nil || instance.GetDeletionTimestamp() != nil {
+		return ctrl.Result{}, nil
+	}
+	retention := instance.GetServiceability().GetRetention()
+
+	pods := &corev1.PodList{}
+	if err := r.Client.List(context.TODO(), pods, client.InNamespace(instance.Namespace)); err != nil {
+		return ctrl.Result{}, err
+	}
+	// only the folders of the pods of the application are swept, as other applications may share the storage
+	livePods := map[string]bool{}
+	var execPod *corev1.Pod
+	for i, pod := range pods.Items {
+		if pod.Labels["app.kubernetes.io/instance"] != instance.Name {
+			continue
+		}
+		livePods[pod.Name] = true
+		if execPod == nil && pod.DeletionTimestamp == nil {
+			if container := getAppContainerStatus(&pods.Items[i]); pod.Status.Phase == corev1.PodRunning && container != nil && container.State.Running != nil {
+				execPod = &pods.Items[i]
+			}
+		}
+	}
+	// the storage can only be read through a pod of the application that mounts it
+	if execPod == nil {
+		reqLogger.V(1).Info("No running pod to sweep the serviceability storage from")
+		return ctrl.Result{RequeueAfter: serviceabilityRetentionInterval}, nil
+	}
+
+	namespaceDir := serviceabilityDir + "/" + instance.Namespace
+	listCmd := fmt.Sprintf("for d in %[1]s/*; do [ -d \"$d\" ] && echo \"d $d\"; done; "+
+		"for f in %[1]s/*/*/*; do [ -f \"$f\" ] && stat -c 'f %%Y %%s %%n' \"$f\"; done; true", namespaceDir)
+	var out bytes.Buffer
+	if stderr, err := utils.StreamCommandInContainer(ctx, r.RestConfig, execPod.Name, execPod.Namespace, "app", []string{"/bin/sh", "-c", listCmd}, &out); err != nil {
+		r.setRetentionFailed(reqLogger, instance, fmt.Errorf("failed to list the files of the serviceability storage: %v: %s", err, stderr))
+		return ctrl.Result{RequeueAfter: serviceabilityRetentionInterval}, nil
+	}
+	podFolders, files := parseServiceabilityFiles(out.String(), namespaceDir)
+	podFolders, files = getApplicationServiceabilityFiles(podFolders, files, livePods, instance.Status.ServiceabilityPods)
+
+	expired, err := getExpiredServiceabilityFiles(files, retention, livePods, time.Now())
+	if err != nil {
+		r.setRetentionFailed(reqLogger, instance, err)
+		return ctrl.Result{RequeueAfter: serviceabilityRetentionInterval}, nil
+	}
+	var deletedSize int64
+	for start := 0; start < len(expired); start += serviceabilityDeleteBatchSize {
+		batch := expired[start:min(start+serviceabilityDeleteBatchSize, len(expired))]
+		deleteCmd := []string{"rm", "-f", "--"}
+		for _, file := range batch {
+			deleteCmd = append(deleteCmd, file.path)
+		}
+		if _, err := utils.ExecuteCommandInContainerWithContext(ctx, r.RestConfig, execPod.Name, execPod.Namespace, "app", deleteCmd); err != nil {
+			r.setRetentionFailed(reqLogger, instance, fmt.Errorf("failed to delete expired files of the serviceability storage: %v", err))
+			return ctrl.Result{RequeueAfter: serviceabilityRetentionInterval}, nil
+		}
+		for _, file := range batch {
+			deletedSize += file.size
+		}
+	}
+
+	// remove the folders of pods that no longer exist once they are empty
+	orphanedFolders := []string{}
+	for _, pod := range podFolders {
+		if !livePods[pod] && serviceabilityPodFolderRegexp.MatchString(pod) {
+			orphanedFolders = append(orphanedFolders, namespaceDir+"/"+pod+"/* "+namespaceDir+"/"+pod)
+		}
+	}
+	if len(orphanedFolders) > 0 {
+		rmdirCmd := "rmdir " + strings.Join(orphanedFolders, " ") + " 2>/dev/null; true"
+		if _, err := utils.ExecuteCommandInContainerWithContext(ctx, r.RestConfig, execPod.Name, execPod.Namespace, "app", []string{"/bin/sh", "-c", rmdirCmd}); err != nil {
+			reqLogger.Error(err, "Failed to delete the empty folders of deleted pods")
+		}
+	}
+
+	// remember the pods of the application, so that their folders are still swept once the pods are gone. The folders
+	// of deleted pods are dropped on the next sweep after they are removed.
+	serviceabilityPods := getServiceabilityPods(podFolders, livePods)
+	if !reflect.DeepEqual(serviceabilityPods, instance.Status.ServiceabilityPods) {
+		patch := client.MergeFrom(instance.DeepCopy())
+		instance.Status.ServiceabilityPods = serviceabilityPods
+		if err := r.Client.Status().Patch(context.TODO(), instance, patch); err != nil {
+			reqLogger.Error(err, "Failed to record the pods of the serviceability storage")
+		}
+	}
+
+	if len(expired) > 0 {
+		message := fmt.Sprintf("Deleted %d expired files (%s) from the serviceability storage", len(expired), resource.NewQuantity(deletedSize, resource.BinarySI).String())
+		reqLogger.Info(message)
+		r.Recorder.Event(instance, "Normal", "ServiceabilityRetention", message)
+	}
+	return ctrl.Result{RequeueAfter: serviceabilityRetentionInterval}, nil
+}
+
+func (r *ReconcileOpenLibertyRetention) setRetentionFailed(reqLogger logr.Logger, instance *openlibertyv1.OpenLibertyApplication, err error) {
+	reqLogger.Error(err, "Failed to apply the serviceability retention")
+	r.Recorder.Event(instance, "Warning", "ProcessingError", "Failed to apply the serviceability retention: "+err.Error())
+}
+
+// Returns the pod folders and files that belong to pods of the application, which are the pods that exist and the pods
+// recorded by earlier sweeps
+func getApplicationServiceabilityFiles(podFolders []string, files []serviceabilityFile, livePods map[string]bool, recordedPods []string) ([]string, []serviceabilityFile) {
+	applicationPods := map[string]bool{}
+	for _, pod := range recordedPods {
+		applicationPods[pod] = true
+	}
+	for pod := range livePods {
+		applicationPods[pod] = true
+	}
+	applicationFolders := []string{}
+	for _, pod := range podFolders {
+		if applicationPods[pod] {
+			applicationFolders = append(applicationFolders, pod)
+		}
+	}
+	applicationFiles := []serviceabilityFile{}
+	for _, file := range files {
+		if applicationPods[file.pod] {
+			applicationFiles = append(applicationFiles, file)
+		}
+	}
+	return applicationFolders, applicationFiles
+}
+
+// Returns the sorted names of the pods of the application that exist or still have a folder in the serviceability storage
+func getServiceabilityPods(podFolders []string, livePods map[string]bool) []string {
+	pods := []string{}
+	for pod := range livePods {
+		pods = append(pods, pod)
+	}
+	for _, pod := range podFolders {
+		if !livePods[pod] {
+			pods = append(pods, pod)
+		}
+	}
+	sort.Strings(pods)
+	if len(pods) == 0 {
+		return nil
+	}
+	return pods
+}
+
+// Parses the output of the listing of the serviceability folder of the namespace, returning the names of the pod folders and the files in them
+func parseServiceabilityFiles(out, namespaceDir string) ([]string, []serviceabilityFile) {
+	podFolders := []string{}
+	files := []serviceabilityFile{}
+	for _, line := range strings.Split(out, "\n") {
+		if folder, found := strings.CutPrefix(line, "d "+namespaceDir+"/"); found {
+			podFolders = append(podFolders, folder)
+			continue
+		}
+		fields := strings.SplitN(line, " ", 4)
+		if len(fields) != 4 || fields[0] != "f" {
+			continue
+		}
+		modTime, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		size, err := strconv.ParseInt(fields[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		relativePath := strings.Split(strings.TrimPrefix(fields[3], namespaceDir+"/"), "/")
+		if len(relativePath) != 3 {
+			continue
+		}
+		files = append(files, serviceabilityFile{
+			pod:      relativePath[0],
+			fileType: relativePath[1],
+			path:     fields[3],
+			size:     size,
+			modTime:  time.Unix(modTime, 0),
+		})
+	}
+	return podFolders, files
+}
+
+// Returns the files, sorted by path, that are older than the maximum age, are not among the most recent files of their type
+// for their pod, or are the oldest files exceeding the maximum total size. The logs of the pods that exist are never returned.
+func getExpiredServiceabilityFiles(files []serviceabilityFile, retention *openlibertyv1.OpenLibertyApplicationServiceabilityRetention, livePods map[string]bool, now time.Time) ([]serviceabilityFile, error) {
+	deletable := []serviceabilityFile{}
+	for _, file := range files {
+		if utils.Contains(serviceabilityFileTypes, file.fileType) && !(file.fileType == "logs" && livePods[file.pod]) {
+			deletable = append(deletable, file)
+		}
+	}
+	// most recent first
+	sort.SliceStable(deletable, func(i, j int) bool {
+		return deletable[i].modTime.After(deletable[j].modTime)
+	})
+
+	expired := map[string]bool{}
+	if retention.MaxAge != nil {
+		for _, file := range deletable {
+			if now.Sub(file.modTime) > retention.MaxAge.Duration {
+				expired[file.path] = true
+			}
+		}
+	}
+	kept := map[string]int{}
+	for _, file := range deletable {
+		keepLast := retention.GetKeepLast(file.fileType)
+		key := file.pod + "/" + file.fileType
+		if keepLast >= 0 && kept[key] >= keepLast {
+			expired[file.path] = true
+		}
+		kept[key]++
+	}
+	if retention.MaxTotalSize != "" {
+		maxTotalSize, err := resource.ParseQuantity(retention.MaxTotalSize)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse maxTotalSize '%v': %v", retention.MaxTotalSize, err)
+		}
+		var totalSize int64
+		for _, file := range files {
+			if !expired[file.path] {
+				totalSize += file.size
+			}
+		}
+		for i := len(deletable) - 1; i >= 0 && totalSize > maxTotalSize.Value(); i-- {
+			if !expired[deletable[i].path] {
+				expired[deletable[i].path] = true
+				totalSize -= deletable[i].size
+			}
+		}
+	}
+
+	expiredFiles := []serviceabilityFile{}
+	for _, file := range deletable {
+		if expired[file.path] {
+			expiredFiles = append(expiredFiles, file)
+		}
+	}
+	sort.Slice(expiredFiles, func(i, j int) bool {
+		return expiredFiles[i].path < expiredFiles[j].path
+	})
+	return expiredFiles, nil
+}
+
+func (r *ReconcileOpenLibertyRetention) SetupWithManager(mgr ctrl.Manager) error {
+
+	watchNamespaces, err := oputils.GetWatchNamespaces()
+	if err != nil {
+		r.Log.Error(err, "Failed to get watch namespace")
+		os.Exit(1)
+	}
+
+	watchNamespacesMap := make(map[string]bool)
+	for _, ns := range watchNamespaces {
+		watchNamespacesMap[ns] = true
+	}
+	isClusterWide := len(watchNamespacesMap) == 1 && watchNamespacesMap[""]
+
+	pred := predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			// Ignore updates to CR status in which case metadata.Generation does not change
+			return e.ObjectOld.GetGeneration() != e.ObjectNew.GetGeneration() && (isClusterWide || watchNamespacesMap[e.ObjectNew.GetNamespace()])
+		},
+		CreateFunc: func(e event.CreateEvent) bool {
+			return isClusterWide || watchNamespacesMap[e.Object.GetNamespace()]
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+	return ctrl.NewControllerManagedBy(mgr).Named("openlibertyapplication-retention").For(&openlibertyv1.OpenLibertyApplication{}, builder.WithPredicates(pred)).WithOptions(controller.Options{
+		MaxConcurrentReconciles: 1,
+	}).Complete(r)
+}
@@ -0,0 +1,118 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	openlibertyv1 "github.com/OpenLiberty/open-liberty-operator/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseServiceabilityFiles(t *testing.T) {
+	out := "d /serviceability/ns/app-0\n" +
+		"d /serviceability/ns/app-1\n" +
+		"f 1704844800 1024 /serviceability/ns/app-0/serverDumps/2024-01-10_00:00:00 1.zip\n" +
+		"f 1704844800 10 /serviceability/ns/app-0/other.txt\n" +
+		"f invalid 10 /serviceability/ns/app-1/logs/messages.log\n" +
+		"f 1704844800 2048 /serviceability/ns/app-1/logs/messages.log\n"
+	podFolders, files := parseServiceabilityFiles(out, "/serviceability/ns")
+
+	tests := []Test{
+		{"pod folders", []string{"app-0", "app-1"}, podFolders},
+		{"files", []serviceabilityFile{
+			{pod: "app-0", fileType: "serverDumps", path: "/serviceability/ns/app-0/serverDumps/2024-01-10_00:00:00 1.zip", size: 1024, modTime: time.Unix(1704844800, 0)},
+			{pod: "app-1", fileType: "logs", path: "/serviceability/ns/app-1/logs/messages.log", size: 2048, modTime: time.Unix(1704844800, 0)},
+		}, files},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestGetApplicationServiceabilityFiles(t *testing.T) {
+	modTime := time.Date(2024, time.January, 10, 0, 0, 0, 0, time.UTC)
+	livePods := map[string]bool{"app-1": true}
+	podFolders, files := getApplicationServiceabilityFiles(
+		[]string{"app-0", "app-1", "other-0"},
+		[]serviceabilityFile{
+			{pod: "app-0", fileType: "serverDumps", path: "/serviceability/ns/app-0/serverDumps/1.zip", size: 100, modTime: modTime},
+			{pod: "app-1", fileType: "serverDumps", path: "/serviceability/ns/app-1/serverDumps/1.zip", size: 100, modTime: modTime},
+			{pod: "other-0", fileType: "serverDumps", path: "/serviceability/ns/other-0/serverDumps/1.zip", size: 100, modTime: modTime},
+		},
+		livePods, []string{"app-0", "app-2"})
+
+	tests := []Test{
+		{"pod folders of the application", []string{"app-0", "app-1"}, podFolders},
+		{"files of the application", []serviceabilityFile{
+			{pod: "app-0", fileType: "serverDumps", path: "/serviceability/ns/app-0/serverDumps/1.zip", size: 100, modTime: modTime},
+			{pod: "app-1", fileType: "serverDumps", path: "/serviceability/ns/app-1/serverDumps/1.zip", size: 100, modTime: modTime},
+		}, files},
+		// app-2 is dropped as its folder was removed
+		{"serviceability pods", []string{"app-0", "app-1", "app-3"}, getServiceabilityPods(append(podFolders, "app-3"), livePods)},
+		{"no serviceability pods", []string(nil), getServiceabilityPods([]string{}, map[string]bool{})},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestGetExpiredServiceabilityFiles(t *testing.T) {
+	now := time.Date(2024, time.January, 10, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	files := []serviceabilityFile{
+		{pod: "app-0", fileType: "serverDumps", path: "/serviceability/ns/app-0/serverDumps/1.zip", size: 100, modTime: now.Add(-5 * day)},
+		{pod: "app-0", fileType: "serverDumps", path: "/serviceability/ns/app-0/serverDumps/2.zip", size: 100, modTime: now.Add(-3 * day)},
+		{pod: "app-0", fileType: "serverDumps", path: "/serviceability/ns/app-0/serverDumps/3.zip", size: 100, modTime: now.Add(-day)},
+		{pod: "app-0", fileType: "performanceData", path: "/serviceability/ns/app-0/performanceData/1.tar.gz", size: 50, modTime: now.Add(-4 * day)},
+		{pod: "app-0", fileType: "logs", path: "/serviceability/ns/app-0/logs/messages.log", size: 500, modTime: now.Add(-10 * day)},
+		{pod: "deleted-0", fileType: "logs", path: "/serviceability/ns/deleted-0/logs/messages.log", size: 200, modTime: now.Add(-10 * day)},
+		{pod: "deleted-0", fileType: "logs", path: "/serviceability/ns/deleted-0/logs/trace.log", size: 200, modTime: now.Add(-2 * day)},
+		{pod: "deleted-0", fileType: "custom", path: "/serviceability/ns/deleted-0/custom/notes.txt", size: 1000, modTime: now.Add(-10 * day)},
+	}
+	livePods := map[string]bool{"app-0": true}
+	one := int32(1)
+	zero := int32(0)
+
+	expiredPaths := func(retention *openlibertyv1.OpenLibertyApplicationServiceabilityRetention) []string {
+		expired, err := getExpiredServiceabilityFiles(files, retention, livePods, now)
+		if err != nil {
+			return []string{err.Error()}
+		}
+		paths := []string{}
+		for _, file := range expired {
+			paths = append(paths, file.path)
+		}
+		return paths
+	}
+
+	tests := []Test{
+		{"no limits", []string{}, expiredPaths(&openlibertyv1.OpenLibertyApplicationServiceabilityRetention{})},
+		{"max age", []string{
+			"/serviceability/ns/app-0/performanceData/1.tar.gz",
+			"/serviceability/ns/app-0/serverDumps/1.zip",
+			"/serviceability/ns/deleted-0/logs/messages.log",
+		}, expiredPaths(&openlibertyv1.OpenLibertyApplicationServiceabilityRetention{MaxAge: &metav1.Duration{Duration: 3*day + time.Hour}})},
+		{"keep last", []string{
+			"/serviceability/ns/app-0/serverDumps/1.zip",
+			"/serviceability/ns/app-0/serverDumps/2.zip",
+			"/serviceability/ns/deleted-0/logs/messages.log",
+			"/serviceability/ns/deleted-0/logs/trace.log",
+		}, expiredPaths(&openlibertyv1.OpenLibertyApplicationServiceabilityRetention{KeepLast: &openlibertyv1.OpenLibertyApplicationServiceabilityKeepLast{ServerDumps: &one, Logs: &zero}})},
+		// 2250 bytes in total, of which the logs of app-0 and the custom file cannot be deleted
+		{"max total size", []string{
+			"/serviceability/ns/app-0/performanceData/1.tar.gz",
+			"/serviceability/ns/app-0/serverDumps/1.zip",
+			"/serviceability/ns/deleted-0/logs/messages.log",
+		}, expiredPaths(&openlibertyv1.OpenLibertyApplicationServiceabilityRetention{MaxTotalSize: "1900"})},
+		{"combined", []string{
+			"/serviceability/ns/app-0/serverDumps/1.zip",
+			"/serviceability/ns/app-0/serverDumps/2.zip",
+			"/serviceability/ns/deleted-0/logs/messages.log",
+		}, expiredPaths(&openlibertyv1.OpenLibertyApplicationServiceabilityRetention{MaxAge: &metav1.Duration{Duration: 6 * day}, MaxTotalSize: "2000", KeepLast: &openlibertyv1.OpenLibertyApplicationServiceabilityKeepLast{ServerDumps: &one}})},
+		{"invalid max total size", []string{"cannot parse maxTotalSize 'lots': quantities must match the regular expression '^([+-]?[0-9.]+)([eEinumkKMGTP]*[-+]?[0-9]*)$'"},
+			expiredPaths(&openlibertyv1.OpenLibertyApplicationServiceabilityRetention{MaxTotalSize: "lots"})},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
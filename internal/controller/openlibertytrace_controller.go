@@ -1,12 +1,16 @@
 package controller
 
 import (
+	"bytes"
 	"context"
+	"fmt"
 	"os"
-	"strconv"
+	"sort"
 	"time"
 
 	lutils "github.com/OpenLiberty/open-liberty-operator/utils"
+	"github.com/OpenLiberty/open-liberty-operator/utils/metrics"
+	"github.com/OpenLiberty/open-liberty-operator/utils/trace"
 	oputils "github.com/application-stacks/runtime-component-operator/utils"
 	"github.com/go-logr/logr"
 
@@ -23,6 +27,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -109,6 +114,45 @@ func (r *ReconcileOpenLibertyTrace) Reconcile(ctx context.Context, request ctrl.
 		}
 	}
 
+	// Tracing that has expired stays stopped until the spec is changed
+	if instance.Status.ObservedGeneration != instance.GetGeneration() {
+		instance.Status.Conditions = openlibertyv1.RemoveOperationCondtion(instance.Status.Conditions, openlibertyv1.OperationStatusConditionTypeExpired)
+	}
+	if err := validateTraceTarget(instance); err != nil {
+		reqLogger.Error(err, "Invalid OpenLibertyTrace")
+		return r.UpdateStatus(err, openlibertyv1.OperationStatusConditionTypeEnabled, *instance, corev1.ConditionFalse, prevPodName, false, "")
+	}
+	expired, requeueAfter := checkTraceExpiry(instance, podChanged, prevTraceEnabled, time.Now())
+	if !*instance.Spec.Disable && !expired {
+		if err := validateTraceConfig(instance); err != nil {
+			//The previous trace configuration, if any, stays in place
+			reqLogger.Error(err, "Rejected the trace configuration")
+			newStatus := corev1.ConditionFalse
+			if prevTraceEnabled == corev1.ConditionTrue {
+				newStatus = corev1.ConditionTrue
+			}
+			return r.UpdateStatus(err, openlibertyv1.OperationStatusConditionTypeEnabled, *instance, newStatus, prevPodName, false, "")
+		}
+	}
+
+	if instance.Spec.ApplicationRef != nil {
+		//If tracing was previously pinned to a pod, then stop tracing on it (if trace was enabled on it)
+		if prevPodName != "" {
+			if prevTraceEnabled == corev1.ConditionTrue {
+				r.disableTraceOnPrevPod(reqLogger, prevPodName, podNamespace)
+			}
+			instance.Status.LogDirectory = ""
+			instance.Status.Export = nil
+		}
+		return r.reconcileApplicationTrace(reqLogger, instance, podChanged, expired, requeueAfter)
+	}
+
+	//If the pods of an application were previously traced, then stop tracing on them
+	if len(instance.Status.OperatedResources) > 0 {
+		r.disableTraceOnOperatedPods(reqLogger, instance)
+		instance.Status.OperatedResources = nil
+	}
+
 	//If pod name changed, then stop tracing on previous pod (if trace was enabled on it)
 	if podChanged && (prevTraceEnabled == corev1.ConditionTrue) {
 		r.disableTraceOnPrevPod(reqLogger, prevPodName, podNamespace)
@@ -121,7 +165,7 @@ func (r *ReconcileOpenLibertyTrace) Reconcile(ctx context.Context, request ctrl.
 		return r.UpdateStatus(err, openlibertyv1.OperationStatusConditionTypeEnabled, *instance, corev1.ConditionFalse, podName, podChanged, "")
 	}
 
-	if instance.Spec.Disable != nil && *instance.Spec.Disable {
+	if (instance.Spec.Disable != nil && *instance.Spec.Disable) || expired {
 		//Disable trace if trace was previously enabled on the same pod
 		if !podChanged && prevTraceEnabled == corev1.ConditionTrue {
 			_, err = lutils.ExecuteCommandInContainer(r.RestConfig, podName, podNamespace, "app", []string{"/bin/sh", "-c", "rm -f " + traceConfigFile})
@@ -130,41 +174,83 @@ func (r *ReconcileOpenLibertyTrace) Reconcile(ctx context.Context, request ctrl.
 				return r.UpdateStatus(err, openlibertyv1.OperationStatusConditionTypeEnabled, *instance, corev1.ConditionTrue, podName, podChanged, "")
 			}
 			reqLogger.Info("Disabled trace for pod " + podName + " in namespace " + podNamespace)
+			observeTraceDuration(instance.Status.Conditions, time.Now())
+			if expired {
+				r.Recorder.Event(instance, "Normal", "TraceExpired", "Stopped tracing of pod "+podName+" because it expired")
+			}
+			if instance.Spec.Export != nil {
+				// the trace logs are exported once tracing has stopped
+				instance.Status.Conditions = setTraceExportPending(instance.Status.Conditions)
+			}
 		}
+		exporting := r.reconcileTraceExport(reqLogger, instance, podName)
 		r.UpdateStatus(nil, openlibertyv1.OperationStatusConditionTypeEnabled, *instance, corev1.ConditionFalse, podName, podChanged, "")
-	} else {
-		traceOutputDir := serviceabilityDir + "/" + podNamespace + "/" + podName + "/logs"
-		traceConfig := "<server><logging traceSpecification=\"" + instance.Spec.TraceSpecification + "\" logDirectory=\"" + traceOutputDir + "\""
-		if instance.Spec.MaxFileSize != nil {
-			traceConfig += " maxFileSize=\"" + strconv.Itoa(int(*instance.Spec.MaxFileSize)) + "\""
-		}
-		if instance.Spec.MaxFiles != nil {
-			traceConfig += " maxFiles=\"" + strconv.Itoa(int(*instance.Spec.MaxFiles)) + "\""
+		if exporting {
+			return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
 		}
-		traceConfig += "/></server>"
-
-		_, err = lutils.ExecuteCommandInContainer(r.RestConfig, podName, podNamespace, "app", []string{"/bin/sh", "-c", "mkdir -p " + traceOutputDir + " && echo '" + traceConfig + "' > " + traceConfigFile})
+	} else {
+		traceOutputDir := getTraceOutputDir(podNamespace, podName)
+		err = r.applyTraceConfig(instance, podName, traceOutputDir)
 		if err != nil {
 			reqLogger.Error(err, "Encountered error while setting up trace for pod "+podName+" in namespace "+podNamespace)
+			metrics.CountOperation(metrics.OperationTrace, false)
 			return r.UpdateStatus(err, openlibertyv1.OperationStatusConditionTypeEnabled, *instance, corev1.ConditionFalse, podName, podChanged, traceOutputDir)
 		}
 
 		if podChanged || prevTraceEnabled == corev1.ConditionFalse {
 			reqLogger.Info("Enabled trace for pod " + podName + " in namespace " + podNamespace)
+			metrics.CountOperation(metrics.OperationTrace, true)
 		} else {
 			reqLogger.Info("Updated trace for pod " + podName + " in namespace " + podNamespace)
 		}
 		r.UpdateStatus(nil, openlibertyv1.OperationStatusConditionTypeEnabled, *instance, corev1.ConditionTrue, podName, podChanged, traceOutputDir)
 	}
 
-	return reconcile.Result{}, nil
+	// stop tracing when it expires
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// Returns whether tracing has expired, setting the Expired condition and .status.expiresAt, or else the time left until it expires
+func checkTraceExpiry(instance *openlibertyv1.OpenLibertyTrace, podChanged bool, prevTraceEnabled corev1.ConditionStatus, now time.Time) (bool, time.Duration) {
+	if oc := openlibertyv1.GetOperationCondtion(instance.Status.Conditions, openlibertyv1.OperationStatusConditionTypeExpired); oc != nil && oc.Status == corev1.ConditionTrue {
+		return true, 0
+	}
+	if instance.Spec.Disable != nil && *instance.Spec.Disable {
+		instance.Status.ExpiresAt = nil
+		return false, 0
+	}
+
+	// the expiry is counted from the time tracing was enabled on the pod, which is kept in the status across operator restarts
+	enabledSince := now
+	if enabled := instance.Status.GetCondition(openlibertyv1.OperationStatusConditionTypeEnabled); !podChanged && prevTraceEnabled == corev1.ConditionTrue && enabled.LastTransitionTime != nil {
+		enabledSince = enabled.LastTransitionTime.Time
+	}
+	instance.Status.ExpiresAt = instance.GetExpiry(enabledSince)
+	if instance.Status.ExpiresAt == nil {
+		return false, 0
+	}
+	if remaining := instance.Status.ExpiresAt.Sub(now); remaining > 0 {
+		return false, remaining
+	}
+	transitionTime := metav1.NewTime(now)
+	instance.Status.Conditions = openlibertyv1.SetOperationCondtion(instance.Status.Conditions, openlibertyv1.OperationStatusCondition{
+		Type:               openlibertyv1.OperationStatusConditionTypeExpired,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: &transitionTime,
+		Message:            "Tracing was stopped at " + instance.Status.ExpiresAt.UTC().Format(time.RFC3339),
+	})
+	return true, 0
 }
 
 // UpdateStatus updates the status
 func (r *ReconcileOpenLibertyTrace) UpdateStatus(issue error, conditionType openlibertyv1.OperationStatusConditionType, instance openlibertyv1.OpenLibertyTrace, newStatus corev1.ConditionStatus, podName string, podChanged bool, traceOutputDir string) (reconcile.Result, error) {
 	s := instance.GetStatus()
 
-	s.SetOperatedResource(openlibertyv1.OperatedResource{ResourceName: podName, ResourceType: "pod"})
+	if podName != "" {
+		s.SetOperatedResource(openlibertyv1.OperatedResource{ResourceName: podName, ResourceType: "pod"})
+	} else {
+		s.SetOperatedResource(openlibertyv1.OperatedResource{})
+	}
 
 	oldCondition := s.GetCondition(conditionType)
 	// Keep the old `LastTransitionTime` when pod and status have not changed
@@ -178,7 +264,11 @@ func (r *ReconcileOpenLibertyTrace) UpdateStatus(issue error, conditionType open
 	statusCondition.SetLastTransitionTime(transitionTime)
 	statusCondition.SetLastUpdateTime(nowTime)
 
-	if issue != nil {
+	if _, ok := issue.(*invalidTraceConfigError); ok {
+		statusCondition.SetReason("InvalidTraceConfiguration")
+		statusCondition.SetMessage(issue.Error())
+		r.Recorder.Event(&instance, "Warning", "InvalidTraceConfiguration", issue.Error())
+	} else if issue != nil {
 		statusCondition.SetReason("Error")
 		statusCondition.SetMessage(issue.Error())
 		r.Recorder.Event(&instance, "Warning", "ProcessingError", issue.Error())
@@ -210,6 +300,316 @@ func (r *ReconcileOpenLibertyTrace) UpdateStatus(issue error, conditionType open
 	return reconcile.Result{Requeue: false}, nil
 }
 
+// Archives the trace log directory of the pod and uploads the archive once tracing is disabled, when .spec.export is set.
+// Returns true while the export is in progress.
+func (r *ReconcileOpenLibertyTrace) reconcileTraceExport(reqLogger logr.Logger, instance *openlibertyv1.OpenLibertyTrace, podName string) bool {
+	target := &openlibertyv1.OperatedResource{ResourceName: podName, Conditions: instance.Status.Conditions, Export: instance.Status.Export}
+	exporting := r.reconcilePodTraceExport(reqLogger, instance, target)
+	instance.Status.Conditions = target.Conditions
+	instance.Status.Export = target.Export
+	return exporting
+}
+
+// Archives the trace log directory of the target pod and uploads the archive once its Exported condition is pending,
+// recording the outcome in the conditions and export of the target. Returns true while the export is in progress.
+func (r *ReconcileOpenLibertyTrace) reconcilePodTraceExport(reqLogger logr.Logger, instance *openlibertyv1.OpenLibertyTrace, target *openlibertyv1.OperatedResource) bool {
+	oc := openlibertyv1.GetOperationCondtion(target.Conditions, openlibertyv1.OperationStatusConditionTypeExported)
+	if instance.Spec.Export == nil || oc == nil || oc.Status != corev1.ConditionUnknown {
+		return false
+	}
+
+	podName := target.ResourceName
+	workerKey := getWorkerKey(string(instance.UID), podName, 0)
+	status, result := pollWorker(workerKey)
+	switch status {
+	case workerStatusRunning:
+		return true
+	case workerStatusDone:
+		clearWorkerResult(workerKey)
+		if result.exportErr != nil {
+			r.setTraceExportFailed(reqLogger, instance, target, result.exportErr)
+		} else {
+			target.Export = result.export
+			target.Conditions = openlibertyv1.SetOperationCondtion(target.Conditions, openlibertyv1.OperationStatusCondition{
+				Type:   openlibertyv1.OperationStatusConditionTypeExported,
+				Status: corev1.ConditionTrue,
+			})
+		}
+		return false
+	}
+
+	exportClient, err := getExportClient(r.Client, instance.Namespace, instance.Spec.Export)
+	if err != nil {
+		r.setTraceExportFailed(reqLogger, instance, target, err)
+		return false
+	}
+	podNamespace := instance.Namespace
+	traceOutputDir := getTraceOutputDir(podNamespace, podName)
+	archiveDir := serviceabilityDir + "/" + podNamespace + "/" + podName + "/traces"
+	archiveFile := archiveDir + "/trace_" + time.Now().UTC().Format("2006.01.02_15.04.05") + "_utc.tar.gz"
+	archiveCmd := "mkdir -p " + archiveDir + " && tar -czf " + archiveFile + " -C " + traceOutputDir + " ."
+	export := instance.Spec.Export.DeepCopy()
+	started := startWorker(workerKey, 0, func(ctx context.Context) workerResult {
+		if _, err := lutils.ExecuteCommandInContainerWithContext(ctx, r.RestConfig, podName, podNamespace, "app", []string{"/bin/sh", "-c", archiveCmd}); err != nil {
+			return workerResult{exportErr: err}
+		}
+		result := workerResult{file: archiveFile}
+		result.export, result.exportErr = exportFile(ctx, r.RestConfig, podName, podNamespace, archiveFile, export, exportClient)
+		return result
+	})
+	c := openlibertyv1.OperationStatusCondition{
+		Type:    openlibertyv1.OperationStatusConditionTypeExported,
+		Status:  corev1.ConditionUnknown,
+		Reason:  "InProgress",
+		Message: "Uploading an archive of " + traceOutputDir + " to bucket " + instance.Spec.Export.Bucket,
+	}
+	if !started {
+		c.Reason = "TooManyWorkers"
+		c.Message = "The operator export queue is full. Waiting for a worker to become available..."
+	}
+	target.Conditions = openlibertyv1.SetOperationCondtion(target.Conditions, c)
+	return true
+}
+
+func (r *ReconcileOpenLibertyTrace) setTraceExportFailed(reqLogger logr.Logger, instance *openlibertyv1.OpenLibertyTrace, target *openlibertyv1.OperatedResource, err error) {
+	reqLogger.Error(err, "Failed to export the trace logs of pod "+target.ResourceName)
+	r.Recorder.Event(instance, "Warning", "ProcessingError", err.Error())
+	target.Conditions = openlibertyv1.SetOperationCondtion(target.Conditions, openlibertyv1.OperationStatusCondition{
+		Type:    openlibertyv1.OperationStatusConditionTypeExported,
+		Status:  corev1.ConditionFalse,
+		Reason:  "Error",
+		Message: err.Error(),
+	})
+}
+
+// Marks the export of the trace logs of the pod as pending, so that they are exported now that tracing has stopped
+func setTraceExportPending(conditions []openlibertyv1.OperationStatusCondition) []openlibertyv1.OperationStatusCondition {
+	return openlibertyv1.SetOperationCondtion(conditions, openlibertyv1.OperationStatusCondition{
+		Type:   openlibertyv1.OperationStatusConditionTypeExported,
+		Status: corev1.ConditionUnknown,
+		Reason: "Pending",
+	})
+}
+
+func getTraceOutputDir(podNamespace string, podName string) string {
+	return serviceabilityDir + "/" + podNamespace + "/" + podName + "/logs"
+}
+
+// Writes the trace configuration drop-in of the instance to the pod, directing the trace logs to traceOutputDir
+func (r *ReconcileOpenLibertyTrace) applyTraceConfig(instance *openlibertyv1.OpenLibertyTrace, podName string, traceOutputDir string) error {
+	logging := getTraceLogging(instance, traceOutputDir)
+	traceConfig, err := logging.Marshal()
+	if err != nil {
+		return &invalidTraceConfigError{err: err}
+	}
+	if _, err := lutils.ExecuteCommandInContainer(r.RestConfig, podName, instance.Namespace, "app", []string{"mkdir", "-p", traceOutputDir}); err != nil {
+		return err
+	}
+	return lutils.WriteFileInContainer(context.TODO(), r.RestConfig, podName, instance.Namespace, "app", traceConfigFile, bytes.NewReader(traceConfig))
+}
+
+func getTraceLogging(instance *openlibertyv1.OpenLibertyTrace, traceOutputDir string) trace.Logging {
+	return trace.Logging{
+		TraceSpecification: instance.Spec.TraceSpecification,
+		LogDirectory:       traceOutputDir,
+		MaxFileSize:        instance.Spec.MaxFileSize,
+		MaxFiles:           instance.Spec.MaxFiles,
+		TraceFormat:        instance.Spec.TraceFormat,
+		ConsoleLogLevel:    instance.Spec.ConsoleLogLevel,
+		MessageFormat:      instance.Spec.MessageFormat,
+	}
+}
+
+// Returns an error if the trace configuration of the instance is rejected, so that it is never written to a pod
+func validateTraceConfig(instance *openlibertyv1.OpenLibertyTrace) error {
+	logging := getTraceLogging(instance, serviceabilityDir)
+	if err := logging.Validate(); err != nil {
+		return &invalidTraceConfigError{err: err}
+	}
+	return nil
+}
+
+// Reported with the InvalidTraceConfiguration reason
+type invalidTraceConfigError struct {
+	err error
+}
+
+func (e *invalidTraceConfigError) Error() string {
+	return "Invalid trace configuration: " + e.err.Error()
+}
+
+// Returns an error unless exactly one of .spec.podName or .spec.applicationRef is set
+func validateTraceTarget(instance *openlibertyv1.OpenLibertyTrace) error {
+	if (instance.Spec.PodName == "") == (instance.Spec.ApplicationRef == nil) {
+		return fmt.Errorf("exactly one of podName or applicationRef must be set")
+	}
+	return nil
+}
+
+// Applies the trace configuration to the running pods of .spec.applicationRef, including the pods that are scheduled after
+// tracing was enabled, or removes it from the traced pods once tracing is disabled or has expired. The outcome for each
+// pod is recorded in .status.operatedResources.
+func (r *ReconcileOpenLibertyTrace) reconcileApplicationTrace(reqLogger logr.Logger, instance *openlibertyv1.OpenLibertyTrace, podChanged bool, expired bool, requeueAfter time.Duration) (reconcile.Result, error) {
+	appName := instance.Spec.ApplicationRef.Name
+	pods, err := getApplicationPods(r.Client, instance.Namespace, appName)
+	if err != nil {
+		reqLogger.Error(err, "Failed to get the pods of application "+appName+" in namespace "+instance.Namespace)
+		return r.UpdateStatus(err, openlibertyv1.OperationStatusConditionTypeEnabled, *instance, corev1.ConditionFalse, "", podChanged, "")
+	}
+
+	disable := *instance.Spec.Disable || expired
+	prevPods := map[string]openlibertyv1.OperatedResource{}
+	for _, podStatus := range instance.Status.OperatedResources {
+		prevPods[podStatus.ResourceName] = podStatus
+	}
+	podStatuses := []openlibertyv1.OperatedResource{}
+	exporting := false
+	for _, pod := range pods {
+		podStatus, found := prevPods[pod.Name]
+		delete(prevPods, pod.Name)
+		if !found {
+			podStatus = openlibertyv1.OperatedResource{ResourceName: pod.Name, ResourceType: "pod"}
+		}
+		if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil || (disable && !found) {
+			// A pod is traced once it is running
+			if found {
+				podStatuses = append(podStatuses, podStatus)
+			}
+			continue
+		}
+		if disable {
+			r.disableTraceOnApplicationPod(reqLogger, instance, &podStatus, expired)
+			if r.reconcilePodTraceExport(reqLogger, instance, &podStatus) {
+				exporting = true
+			}
+		} else {
+			r.enableTraceOnApplicationPod(reqLogger, instance, &podStatus)
+		}
+		podStatuses = append(podStatuses, podStatus)
+	}
+	//Stop tracing on the pods that were deleted or no longer belong to the application
+	for _, podStatus := range prevPods {
+		if isTraceEnabled(podStatus.Conditions) {
+			r.disableTraceOnPrevPod(reqLogger, podStatus.ResourceName, instance.Namespace)
+		}
+	}
+	instance.Status.OperatedResources = podStatuses
+
+	if instance.Spec.Export != nil {
+		if oc, found := getTracePodsExportedCondition(podStatuses); found {
+			instance.Status.Conditions = openlibertyv1.SetOperationCondtion(instance.Status.Conditions, oc)
+		}
+	}
+	newStatus := corev1.ConditionTrue
+	if disable {
+		newStatus = corev1.ConditionFalse
+	}
+	r.UpdateStatus(nil, openlibertyv1.OperationStatusConditionTypeEnabled, *instance, newStatus, "", podChanged, "")
+	if exporting {
+		return reconcile.Result{RequeueAfter: 5 * time.Second}, nil
+	}
+	// stop tracing when it expires
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// Returns the pods of the application, sorted by name
+func getApplicationPods(c client.Client, namespace string, appName string) ([]corev1.Pod, error) {
+	app := &openlibertyv1.OpenLibertyApplication{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Name: appName, Namespace: namespace}, app); err != nil {
+		return nil, err
+	}
+	podList := &corev1.PodList{}
+	if err := c.List(context.TODO(), podList, client.InNamespace(namespace), client.MatchingLabels{"app.kubernetes.io/instance": app.Name}); err != nil {
+		return nil, err
+	}
+	pods := podList.Items
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].Name < pods[j].Name
+	})
+	return pods, nil
+}
+
+func (r *ReconcileOpenLibertyTrace) enableTraceOnApplicationPod(reqLogger logr.Logger, instance *openlibertyv1.OpenLibertyTrace, podStatus *openlibertyv1.OperatedResource) {
+	podName := podStatus.ResourceName
+	traceOutputDir := getTraceOutputDir(instance.Namespace, podName)
+	if err := r.applyTraceConfig(instance, podName, traceOutputDir); err != nil {
+		reqLogger.Error(err, "Encountered error while setting up trace for pod "+podName+" in namespace "+instance.Namespace)
+		r.Recorder.Event(instance, "Warning", "ProcessingError", "Failed to enable trace for pod "+podName+": "+err.Error())
+		metrics.CountOperation(metrics.OperationTrace, false)
+		setTracePodCondition(podStatus, corev1.ConditionFalse, err)
+		return
+	}
+	if isTraceEnabled(podStatus.Conditions) {
+		reqLogger.Info("Updated trace for pod " + podName + " in namespace " + instance.Namespace)
+	} else {
+		reqLogger.Info("Enabled trace for pod " + podName + " in namespace " + instance.Namespace)
+		metrics.CountOperation(metrics.OperationTrace, true)
+	}
+	podStatus.LogDirectory = traceOutputDir
+	setTracePodCondition(podStatus, corev1.ConditionTrue, nil)
+}
+
+func (r *ReconcileOpenLibertyTrace) disableTraceOnApplicationPod(reqLogger logr.Logger, instance *openlibertyv1.OpenLibertyTrace, podStatus *openlibertyv1.OperatedResource, expired bool) {
+	if !isTraceEnabled(podStatus.Conditions) {
+		return
+	}
+	podName := podStatus.ResourceName
+	_, err := lutils.ExecuteCommandInContainer(r.RestConfig, podName, instance.Namespace, "app", []string{"/bin/sh", "-c", "rm -f " + traceConfigFile})
+	if err != nil {
+		reqLogger.Error(err, "Encountered error while disabling trace for pod "+podName+" in namespace "+instance.Namespace)
+		r.Recorder.Event(instance, "Warning", "ProcessingError", "Failed to disable trace for pod "+podName+": "+err.Error())
+		setTracePodCondition(podStatus, corev1.ConditionTrue, err)
+		return
+	}
+	reqLogger.Info("Disabled trace for pod " + podName + " in namespace " + instance.Namespace)
+	observeTraceDuration(podStatus.Conditions, time.Now())
+	if expired {
+		r.Recorder.Event(instance, "Normal", "TraceExpired", "Stopped tracing of pod "+podName+" because it expired")
+	}
+	setTracePodCondition(podStatus, corev1.ConditionFalse, nil)
+	if instance.Spec.Export != nil {
+		// the trace logs are exported once tracing has stopped
+		podStatus.Conditions = setTraceExportPending(podStatus.Conditions)
+	}
+}
+
+func setTracePodCondition(podStatus *openlibertyv1.OperatedResource, status corev1.ConditionStatus, err error) {
+	c := openlibertyv1.OperationStatusCondition{
+		Type:   openlibertyv1.OperationStatusConditionTypeEnabled,
+		Status: status,
+	}
+	if err != nil {
+		c.Reason = "Error"
+		c.Message = err.Error()
+	}
+	if openlibertyv1.GetOperationCondtion(podStatus.Conditions, c.Type) == nil {
+		transitionTime := metav1.Now()
+		c.LastTransitionTime = &transitionTime
+	}
+	podStatus.Conditions = openlibertyv1.SetOperationCondtion(podStatus.Conditions, c)
+}
+
+// Observes how long the pod was traced, which is the time since its Enabled condition became true
+func observeTraceDuration(conditions []openlibertyv1.OperationStatusCondition, now time.Time) {
+	if oc := openlibertyv1.GetOperationCondtion(conditions, openlibertyv1.OperationStatusConditionTypeEnabled); oc != nil && oc.LastTransitionTime != nil {
+		metrics.ObserveOperationDuration(metrics.OperationTrace, now.Sub(oc.LastTransitionTime.Time))
+	}
+}
+
+func isTraceEnabled(conditions []openlibertyv1.OperationStatusCondition) bool {
+	oc := openlibertyv1.GetOperationCondtion(conditions, openlibertyv1.OperationStatusConditionTypeEnabled)
+	return oc != nil && oc.Status == corev1.ConditionTrue
+}
+
+// Returns the Exported condition of the trace logs of the pods of the application
+func getTracePodsExportedCondition(podStatuses []openlibertyv1.OperatedResource) (openlibertyv1.OperationStatusCondition, bool) {
+	podConditions := make([][]openlibertyv1.OperationStatusCondition, len(podStatuses))
+	for i := range podStatuses {
+		podConditions[i] = podStatuses[i].Conditions
+	}
+	return getPodsExportedCondition(podConditions, "trace logs")
+}
+
 func (r *ReconcileOpenLibertyTrace) disableTraceOnPrevPod(reqLogger logr.Logger, prevPodName string, podNamespace string) {
 	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: prevPodName, Namespace: podNamespace}, &corev1.Pod{})
 	if err != nil && errors.IsNotFound(err) {
@@ -227,12 +627,24 @@ func (r *ReconcileOpenLibertyTrace) disableTraceOnPrevPod(reqLogger logr.Logger,
 }
 
 func (r *ReconcileOpenLibertyTrace) finalizeOpenLibertyTrace(reqLogger logr.Logger, olt *openlibertyv1.OpenLibertyTrace, prevTraceEnabled corev1.ConditionStatus, prevPodName string, podNamespace string) error {
-	if prevTraceEnabled == corev1.ConditionTrue {
+	cancelWorkers(string(olt.UID))
+	clearWorkerResults(string(olt.UID))
+	if prevTraceEnabled == corev1.ConditionTrue && prevPodName != "" {
 		r.disableTraceOnPrevPod(reqLogger, prevPodName, podNamespace)
 	}
+	r.disableTraceOnOperatedPods(reqLogger, olt)
 	return nil
 }
 
+// Stops tracing on the pods of the application that trace was enabled on
+func (r *ReconcileOpenLibertyTrace) disableTraceOnOperatedPods(reqLogger logr.Logger, olt *openlibertyv1.OpenLibertyTrace) {
+	for _, podStatus := range olt.Status.OperatedResources {
+		if isTraceEnabled(podStatus.Conditions) {
+			r.disableTraceOnPrevPod(reqLogger, podStatus.ResourceName, olt.Namespace)
+		}
+	}
+}
+
 func (r *ReconcileOpenLibertyTrace) addFinalizer(reqLogger logr.Logger, olt *openlibertyv1.OpenLibertyTrace) error {
 	reqLogger.Info("Adding Finalizer for OpenLibertyTrace")
 	olt.SetFinalizers(append(olt.GetFinalizers(), traceFinalizer))
@@ -278,7 +690,57 @@ func (r *ReconcileOpenLibertyTrace) SetupWithManager(mgr ctrl.Manager) error {
 			return isClusterWide || watchNamespacesMap[e.Object.GetNamespace()]
 		},
 	}
-	return ctrl.NewControllerManagedBy(mgr).For(&openlibertyv1.OpenLibertyTrace{}, builder.WithPredicates(pred)).WithOptions(controller.Options{
-		MaxConcurrentReconciles: 1,
-	}).Complete(r)
+
+	isApplicationPod := func(obj client.Object) bool {
+		labels := obj.GetLabels()
+		return labels["app.kubernetes.io/instance"] != "" && labels["app.kubernetes.io/managed-by"] == OperatorName && (isClusterWide || watchNamespacesMap[obj.GetNamespace()])
+	}
+	podPred := predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			// Trace the pods of an application once they are running, and again after their containers restarted
+			oldPod, oldOk := e.ObjectOld.(*corev1.Pod)
+			newPod, newOk := e.ObjectNew.(*corev1.Pod)
+			return oldOk && newOk && isApplicationPod(newPod) &&
+				(oldPod.Status.Phase != newPod.Status.Phase || getPodRestartCount(oldPod) != getPodRestartCount(newPod))
+		},
+		CreateFunc: func(e event.CreateEvent) bool {
+			return isApplicationPod(e.Object)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return isApplicationPod(e.Object)
+		},
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+	return ctrl.NewControllerManagedBy(mgr).For(&openlibertyv1.OpenLibertyTrace{}, builder.WithPredicates(pred)).
+		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(r.getApplicationTraces), builder.WithPredicates(podPred)).
+		WithOptions(controller.Options{
+			MaxConcurrentReconciles: 1,
+		}).Complete(r)
+}
+
+// Returns the OpenLibertyTrace instances that trace the application of the pod
+func (r *ReconcileOpenLibertyTrace) getApplicationTraces(ctx context.Context, obj client.Object) []reconcile.Request {
+	appName := obj.GetLabels()["app.kubernetes.io/instance"]
+	traces := &openlibertyv1.OpenLibertyTraceList{}
+	if err := r.Client.List(ctx, traces, client.InNamespace(obj.GetNamespace())); err != nil {
+		r.Log.Error(err, "Failed to list the OpenLibertyTrace instances in namespace "+obj.GetNamespace())
+		return nil
+	}
+	requests := []reconcile.Request{}
+	for _, trace := range traces.Items {
+		if trace.Spec.ApplicationRef != nil && trace.Spec.ApplicationRef.Name == appName {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: trace.Name, Namespace: trace.Namespace}})
+		}
+	}
+	return requests
+}
+
+func getPodRestartCount(pod *corev1.Pod) int32 {
+	var restarts int32
+	for _, status := range pod.Status.ContainerStatuses {
+		restarts += status.RestartCount
+	}
+	return restarts
 }
@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	openlibertyv1 "github.com/OpenLiberty/open-liberty-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCheckTraceExpiry(t *testing.T) {
+	now := time.Date(2024, time.January, 1, 12, 0, 0, 0, time.UTC)
+	enabledSince := metav1.NewTime(now.Add(-10 * time.Minute))
+	duration := int32(3600)
+	shortDuration := int32(300)
+	expiresAt := metav1.NewTime(now.Add(30 * time.Minute))
+	disable := true
+
+	trace := func(spec openlibertyv1.OpenLibertyTraceSpec) *openlibertyv1.OpenLibertyTrace {
+		return &openlibertyv1.OpenLibertyTrace{
+			Spec: spec,
+			Status: openlibertyv1.OpenLibertyTraceStatus{
+				Conditions: []openlibertyv1.OperationStatusCondition{{Type: openlibertyv1.OperationStatusConditionTypeEnabled, Status: corev1.ConditionTrue, LastTransitionTime: &enabledSince}},
+			},
+		}
+	}
+
+	noExpiry := trace(openlibertyv1.OpenLibertyTraceSpec{})
+	noExpiryExpired, noExpiryRequeue := checkTraceExpiry(noExpiry, false, corev1.ConditionTrue, now)
+	running := trace(openlibertyv1.OpenLibertyTraceSpec{DurationSeconds: &duration})
+	runningExpired, runningRequeue := checkTraceExpiry(running, false, corev1.ConditionTrue, now)
+	earliest := trace(openlibertyv1.OpenLibertyTraceSpec{DurationSeconds: &duration, ExpiresAt: &expiresAt})
+	_, earliestRequeue := checkTraceExpiry(earliest, false, corev1.ConditionTrue, now)
+	// tracing enabled on a new pod starts counting again
+	newPod := trace(openlibertyv1.OpenLibertyTraceSpec{DurationSeconds: &shortDuration})
+	newPodExpired, newPodRequeue := checkTraceExpiry(newPod, true, corev1.ConditionTrue, now)
+	expired := trace(openlibertyv1.OpenLibertyTraceSpec{DurationSeconds: &shortDuration})
+	expiredExpired, _ := checkTraceExpiry(expired, false, corev1.ConditionTrue, now)
+	expiredCondition := openlibertyv1.GetOperationCondtion(expired.Status.Conditions, openlibertyv1.OperationStatusConditionTypeExpired)
+	// the Expired condition keeps tracing stopped after the operator restarts
+	stillExpired, _ := checkTraceExpiry(expired, false, corev1.ConditionFalse, now.Add(time.Hour))
+	disabled := trace(openlibertyv1.OpenLibertyTraceSpec{DurationSeconds: &duration, Disable: &disable})
+	disabledExpired, _ := checkTraceExpiry(disabled, false, corev1.ConditionTrue, now)
+
+	tests := []Test{
+		{"no expiry", false, noExpiryExpired},
+		{"no expiry requeue", time.Duration(0), noExpiryRequeue},
+		{"no expiry time", (*metav1.Time)(nil), noExpiry.Status.ExpiresAt},
+		{"running", false, runningExpired},
+		{"running requeue", 50 * time.Minute, runningRequeue},
+		{"running expiry time", now.Add(50 * time.Minute), running.Status.ExpiresAt.Time},
+		{"earliest expiry", 30 * time.Minute, earliestRequeue},
+		{"new pod", false, newPodExpired},
+		{"new pod requeue", 5 * time.Minute, newPodRequeue},
+		{"expired", true, expiredExpired},
+		{"expired condition", corev1.ConditionTrue, expiredCondition.Status},
+		{"expired message", "Tracing was stopped at 2024-01-01T11:55:00Z", expiredCondition.Message},
+		{"still expired", true, stillExpired},
+		{"disabled", false, disabledExpired},
+		{"disabled expiry time", (*metav1.Time)(nil), disabled.Status.ExpiresAt},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestValidateTraceTarget(t *testing.T) {
+	app := &corev1.LocalObjectReference{Name: "app"}
+	validate := func(spec openlibertyv1.OpenLibertyTraceSpec) bool {
+		return validateTraceTarget(&openlibertyv1.OpenLibertyTrace{Spec: spec}) == nil
+	}
+
+	tests := []Test{
+		{"pod name", true, validate(openlibertyv1.OpenLibertyTraceSpec{PodName: "app-0"})},
+		{"application", true, validate(openlibertyv1.OpenLibertyTraceSpec{ApplicationRef: app})},
+		{"no target", false, validate(openlibertyv1.OpenLibertyTraceSpec{})},
+		{"both targets", false, validate(openlibertyv1.OpenLibertyTraceSpec{PodName: "app-0", ApplicationRef: app})},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestGetTracePodsExportedCondition(t *testing.T) {
+	pod := func(name string, exported corev1.ConditionStatus) openlibertyv1.OperatedResource {
+		podStatus := openlibertyv1.OperatedResource{ResourceName: name, ResourceType: "pod"}
+		setTracePodCondition(&podStatus, corev1.ConditionFalse, nil)
+		if exported != "" {
+			podStatus.Conditions = append(podStatus.Conditions, openlibertyv1.OperationStatusCondition{Type: openlibertyv1.OperationStatusConditionTypeExported, Status: exported})
+		}
+		return podStatus
+	}
+	status := func(podStatuses ...openlibertyv1.OperatedResource) corev1.ConditionStatus {
+		oc, found := getTracePodsExportedCondition(podStatuses)
+		if !found {
+			return ""
+		}
+		return oc.Status
+	}
+	pending, _ := getTracePodsExportedCondition([]openlibertyv1.OperatedResource{pod("app-0", corev1.ConditionUnknown), pod("app-1", corev1.ConditionTrue), pod("app-2", "")})
+
+	tests := []Test{
+		{"no exports", corev1.ConditionStatus(""), status(pod("app-0", ""))},
+		{"exported", corev1.ConditionTrue, status(pod("app-0", corev1.ConditionTrue), pod("app-1", corev1.ConditionTrue), pod("app-2", ""))},
+		{"failed", corev1.ConditionFalse, status(pod("app-0", corev1.ConditionTrue), pod("app-1", corev1.ConditionFalse))},
+		{"in progress", corev1.ConditionUnknown, status(pod("app-0", corev1.ConditionUnknown), pod("app-1", corev1.ConditionFalse))},
+		{"in progress message", "Exporting the trace logs of 1 of 2 pods", pending.Message},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestValidateTraceConfig(t *testing.T) {
+	reason := func(spec openlibertyv1.OpenLibertyTraceSpec) string {
+		err := validateTraceConfig(&openlibertyv1.OpenLibertyTrace{Spec: spec})
+		if err == nil {
+			return ""
+		}
+		if _, ok := err.(*invalidTraceConfigError); !ok {
+			return err.Error()
+		}
+		return "InvalidTraceConfiguration"
+	}
+
+	tests := []Test{
+		{"valid", "", reason(openlibertyv1.OpenLibertyTraceSpec{PodName: "app-0", TraceSpecification: "*=info:com.ibm.ws.webcontainer*=all", MessageFormat: "json"})},
+		{"malformed trace specification", "InvalidTraceConfiguration", reason(openlibertyv1.OpenLibertyTraceSpec{PodName: "app-0", TraceSpecification: `*=all"/><include location="/tmp/x.xml`})},
+		{"invalid console log level", "InvalidTraceConfiguration", reason(openlibertyv1.OpenLibertyTraceSpec{PodName: "app-0", ConsoleLogLevel: "DEBUG"})},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
@@ -0,0 +1,125 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	openlibertyv1 "github.com/OpenLiberty/open-liberty-operator/api/v1"
+	"github.com/OpenLiberty/open-liberty-operator/utils"
+	"github.com/OpenLiberty/open-liberty-operator/utils/s3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Keys of the Secret referenced by .spec.export.credentialsSecretRef
+const (
+	exportAccessKeyIDKey     = "accessKeyId"
+	exportSecretAccessKeyKey = "secretAccessKey"
+	exportSessionTokenKey    = "sessionToken"
+)
+
+// Returns a client for the bucket of the export, with the credentials read from the Secret in the namespace
+func getExportClient(c client.Client, namespace string, export *openlibertyv1.OperationExport) (*s3.Client, error) {
+	secret := &corev1.Secret{}
+	err := c.Get(context.TODO(), types.NamespacedName{Name: export.CredentialsSecretRef.Name, Namespace: namespace}, secret)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the export credentials Secret %s: %v", export.CredentialsSecretRef.Name, err)
+	}
+	credentials := s3.Credentials{
+		AccessKeyID:     string(secret.Data[exportAccessKeyIDKey]),
+		SecretAccessKey: string(secret.Data[exportSecretAccessKeyKey]),
+		SessionToken:    string(secret.Data[exportSessionTokenKey]),
+	}
+	return s3.NewClient(export.Endpoint, export.Bucket, export.Region, credentials, nil)
+}
+
+// Returns the object key of a file in the serviceability folder, which is its path relative to the folder
+func getExportObjectKey(export *openlibertyv1.OperationExport, file string) string {
+	key := strings.TrimPrefix(file, serviceabilityDir+"/")
+	if export.Prefix != "" {
+		key = path.Join(export.Prefix, key)
+	}
+	return key
+}
+
+// Streams the file from the pod to the bucket of the export
+func exportFile(ctx context.Context, config *rest.Config, podName, podNamespace, file string, export *openlibertyv1.OperationExport, exportClient *s3.Client) (*openlibertyv1.OperationExportStatus, error) {
+	var sizeOut bytes.Buffer
+	if _, err := utils.StreamCommandInContainer(ctx, config, podName, podNamespace, "app", []string{"/bin/sh", "-c", "wc -c < " + file}, &sizeOut); err != nil {
+		return nil, err
+	}
+	size, err := strconv.ParseInt(strings.TrimSpace(sizeOut.String()), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the size of %s: %v", file, err)
+	}
+
+	reader, writer := io.Pipe()
+	// closing the reader stops the copy from the pod if the upload fails
+	defer reader.Close()
+	go func() {
+		_, err := utils.StreamCommandInContainer(ctx, config, podName, podNamespace, "app", []string{"cat", file}, writer)
+		writer.CloseWithError(err)
+	}()
+
+	object, err := exportClient.PutObject(ctx, getExportObjectKey(export, file), reader, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to export %s: %v", file, err)
+	}
+	exportTime := metav1.Now()
+	return &openlibertyv1.OperationExportStatus{
+		URL:        object.URL,
+		SHA256:     object.SHA256,
+		ExportTime: &exportTime,
+	}, nil
+}
+
+// Returns an Exported condition that is unknown while the files of any pod are being exported and true only if the
+// files of all pods were exported, or false if the files of no pod are to be exported. files describes the exported
+// files in the condition message.
+func getPodsExportedCondition(podConditions [][]openlibertyv1.OperationStatusCondition, files string) (openlibertyv1.OperationStatusCondition, bool) {
+	exports, pending, failed := 0, 0, 0
+	for _, conditions := range podConditions {
+		oc := openlibertyv1.GetOperationCondtion(conditions, openlibertyv1.OperationStatusConditionTypeExported)
+		if oc == nil {
+			continue
+		}
+		exports++
+		switch oc.Status {
+		case corev1.ConditionUnknown:
+			pending++
+		case corev1.ConditionFalse:
+			failed++
+		}
+	}
+	if exports == 0 {
+		return openlibertyv1.OperationStatusCondition{}, false
+	}
+	if pending > 0 {
+		return openlibertyv1.OperationStatusCondition{
+			Type:    openlibertyv1.OperationStatusConditionTypeExported,
+			Status:  corev1.ConditionUnknown,
+			Reason:  "InProgress",
+			Message: fmt.Sprintf("Exporting the %s of %d of %d pods", files, pending, exports),
+		}, true
+	}
+	if failed > 0 {
+		return openlibertyv1.OperationStatusCondition{
+			Type:    openlibertyv1.OperationStatusConditionTypeExported,
+			Status:  corev1.ConditionFalse,
+			Reason:  "Error",
+			Message: fmt.Sprintf("The %s of %d of %d pods failed to export", files, failed, exports),
+		}, true
+	}
+	return openlibertyv1.OperationStatusCondition{
+		Type:   openlibertyv1.OperationStatusConditionTypeExported,
+		Status: corev1.ConditionTrue,
+	}, true
+}
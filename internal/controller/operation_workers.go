@@ -0,0 +1,161 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	openlibertyv1 "github.com/OpenLiberty/open-liberty-operator/api/v1"
+)
+
+// Long running day-2 operation steps, such as server dumps and artifact exports, run in background workers so that they
+// do not block the reconciliation of other instances. The reconciler starts a worker and polls for its result.
+
+// Maximum number of workers run by the operator at the same time
+const maxOperationWorkers = 10
+
+type workerStatus string
+
+const (
+	workerStatusNotFound workerStatus = "notfound"
+	workerStatusRunning  workerStatus = "running"
+	workerStatusDone     workerStatus = "done"
+)
+
+type operationWorker struct {
+	cancelContext context.CancelFunc
+	workerKey     string
+}
+
+type workerResult struct {
+	// Location of the artifact created by the worker
+	file string
+	err  error
+	// Set when the artifact was summarized
+	summary *openlibertyv1.PerformanceDataSummary
+	// Set when the artifact was exported to object storage
+	export    *openlibertyv1.OperationExportStatus
+	exportErr error
+	// How long the worker ran
+	duration time.Duration
+}
+
+var workerMutex = &sync.Mutex{}
+var operationWorkers = []operationWorker{}
+
+// Results of the finished workers, keyed by worker key
+var completedWorkers = &sync.Map{}
+
+// Returns the key of the worker operating on a pod for one run of an instance. runID is 0 unless the operation is scheduled.
+func getWorkerKey(uid, podName string, runID int64) string {
+	return fmt.Sprintf("%s:%s:%d", uid, podName, runID)
+}
+
+// Starts a worker that calls run, cancelling its context after timeout if timeout is non-zero.
+// Returns false without starting the worker if the operator is already running maxOperationWorkers workers.
+func startWorker(workerKey string, timeout time.Duration, run func(ctx context.Context) workerResult) bool {
+	workerMutex.Lock()
+	defer workerMutex.Unlock()
+	if len(operationWorkers) >= maxOperationWorkers {
+		return false
+	}
+	var ctx context.Context
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), timeout)
+	} else {
+		ctx, cancel = context.WithCancel(context.Background())
+	}
+	completedWorkers.Delete(workerKey)
+	operationWorkers = append(operationWorkers, operationWorker{cancelContext: cancel, workerKey: workerKey})
+
+	go func() {
+		defer cancel()
+		start := time.Now()
+		result := run(ctx)
+		result.duration = time.Since(start)
+		result.err = getWorkerError(ctx, result.err, timeout)
+		result.exportErr = getWorkerError(ctx, result.exportErr, timeout)
+		// store the result and remove the worker together so that a poll never finds neither
+		workerMutex.Lock()
+		defer workerMutex.Unlock()
+		completedWorkers.Store(workerKey, result)
+		removeWorker(workerKey)
+	}()
+	return true
+}
+
+// Replaces the error of a step that failed because the worker timed out or was cancelled
+func getWorkerError(ctx context.Context, err error, timeout time.Duration) error {
+	if err == nil {
+		return nil
+	}
+	switch ctx.Err() {
+	case context.DeadlineExceeded:
+		return fmt.Errorf("the operation did not complete within %v", timeout)
+	case context.Canceled:
+		return fmt.Errorf("the operation was cancelled")
+	}
+	return err
+}
+
+// Returns whether the worker is still running, has finished with a result, or is unknown to this operator (e.g. after a restart)
+func pollWorker(workerKey string) (workerStatus, workerResult) {
+	workerMutex.Lock()
+	defer workerMutex.Unlock()
+	if result, found := completedWorkers.Load(workerKey); found {
+		return workerStatusDone, result.(workerResult)
+	}
+	if hasWorker(workerKey) {
+		return workerStatusRunning, workerResult{}
+	}
+	return workerStatusNotFound, workerResult{}
+}
+
+// Cancels the running workers of an instance
+func cancelWorkers(uid string) {
+	workerMutex.Lock()
+	defer workerMutex.Unlock()
+	for _, worker := range operationWorkers {
+		if strings.HasPrefix(worker.workerKey, uid+":") {
+			worker.cancelContext()
+		}
+	}
+}
+
+// Forgets the results of the finished workers of an instance
+func clearWorkerResults(uid string) {
+	completedWorkers.Range(func(key, value any) bool {
+		if strings.HasPrefix(key.(string), uid+":") {
+			completedWorkers.Delete(key)
+		}
+		return true
+	})
+}
+
+// Forgets the result of a finished worker
+func clearWorkerResult(workerKey string) {
+	completedWorkers.Delete(workerKey)
+}
+
+// Assumes workerMutex is held
+func hasWorker(workerKey string) bool {
+	for _, worker := range operationWorkers {
+		if worker.workerKey == workerKey {
+			return true
+		}
+	}
+	return false
+}
+
+// Assumes workerMutex is held
+func removeWorker(workerKey string) {
+	for i, worker := range operationWorkers {
+		if worker.workerKey == workerKey {
+			operationWorkers = append(operationWorkers[:i], operationWorkers[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	openlibertyv1 "github.com/OpenLiberty/open-liberty-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestPollWorker(t *testing.T) {
+	runningKey := getWorkerKey("uid-1", "pod-a", 0)
+	doneKey := getWorkerKey("uid-1", "pod-b", 0)
+	otherKey := getWorkerKey("uid-2", "pod-a", 0)
+
+	operationWorkers = append(operationWorkers, operationWorker{cancelContext: func() {}, workerKey: runningKey})
+	completedWorkers.Store(doneKey, workerResult{file: "/serviceability/ns/pod-b/serverDumps/dump.zip"})
+	completedWorkers.Store(otherKey, workerResult{err: fmt.Errorf("the dump was cancelled")})
+	defer func() {
+		removeWorker(runningKey)
+		clearWorkerResults("uid-2")
+	}()
+
+	runningStatus, _ := pollWorker(runningKey)
+	doneStatus, doneResult := pollWorker(doneKey)
+	notFoundStatus, _ := pollWorker(getWorkerKey("uid-1", "pod-c", 0))
+	clearWorkerResults("uid-1")
+	clearedStatus, _ := pollWorker(doneKey)
+	otherStatus, _ := pollWorker(otherKey)
+
+	tests := []Test{
+		{"running worker", workerStatusRunning, runningStatus},
+		{"finished worker", workerStatusDone, doneStatus},
+		{"finished worker dump file", "/serviceability/ns/pod-b/serverDumps/dump.zip", doneResult.file},
+		{"unknown worker", workerStatusNotFound, notFoundStatus},
+		{"cleared result", workerStatusNotFound, clearedStatus},
+		{"result of another instance", workerStatusDone, otherStatus},
+		{"scheduled run key", "uid-1:pod-a:1704067200", getWorkerKey("uid-1", "pod-a", 1704067200)},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestStartWorker(t *testing.T) {
+	timedOutKey := getWorkerKey("uid-3", "pod-a", 0)
+	cancelledKey := getWorkerKey("uid-4", "pod-a", 0)
+	defer func() {
+		clearWorkerResults("uid-3")
+		clearWorkerResults("uid-4")
+	}()
+	waitForCancel := func(ctx context.Context) workerResult {
+		<-ctx.Done()
+		return workerResult{file: "dump.zip", err: ctx.Err()}
+	}
+
+	if !startWorker(timedOutKey, 10*time.Millisecond, waitForCancel) || !startWorker(cancelledKey, 0, waitForCancel) {
+		t.Fatalf("expected the workers to start")
+	}
+	cancelWorkers("uid-4")
+	timedOut := waitForWorker(t, timedOutKey)
+	cancelled := waitForWorker(t, cancelledKey)
+
+	tests := []Test{
+		{"timed out worker", "the operation did not complete within 10ms", timedOut.err.Error()},
+		{"cancelled worker", "the operation was cancelled", cancelled.err.Error()},
+		{"worker without export", nil, cancelled.exportErr},
+		{"workers removed", 0, len(operationWorkers)},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func waitForWorker(t *testing.T, workerKey string) workerResult {
+	for i := 0; i < 100; i++ {
+		if status, result := pollWorker(workerKey); status == workerStatusDone {
+			return result
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("worker %s did not finish", workerKey)
+	return workerResult{}
+}
+
+func TestGetExportObjectKey(t *testing.T) {
+	file := "/serviceability/ns/pod-a/serverDumps/dump_2024.01.01_10.15.00_utc.zip"
+	tests := []Test{
+		{"no prefix", "ns/pod-a/serverDumps/dump_2024.01.01_10.15.00_utc.zip", getExportObjectKey(&openlibertyv1.OperationExport{}, file)},
+		{"prefix", "cluster-a/ns/pod-a/serverDumps/dump_2024.01.01_10.15.00_utc.zip", getExportObjectKey(&openlibertyv1.OperationExport{Prefix: "cluster-a/"}, file)},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestIsDumpPending(t *testing.T) {
+	running := []openlibertyv1.OperationStatusCondition{
+		{Type: openlibertyv1.OperationStatusConditionTypeStarted, Status: corev1.ConditionTrue},
+		{Type: openlibertyv1.OperationStatusConditionTypeRunning, Status: corev1.ConditionTrue},
+	}
+	waiting := []openlibertyv1.OperationStatusCondition{
+		{Type: openlibertyv1.OperationStatusConditionTypeStarted, Status: corev1.ConditionTrue},
+		{Type: openlibertyv1.OperationStatusConditionTypeRunning, Status: corev1.ConditionFalse, Reason: "TooManyWorkers"},
+	}
+	failed := openlibertyv1.DumpPodStatus{PodName: "pod-a", Conditions: append([]openlibertyv1.OperationStatusCondition{}, running...)}
+	setDumpFailed(&failed, "the dump was cancelled")
+
+	tests := []Test{
+		{"new dump", true, isDumpPending(nil)},
+		{"running dump", false, isDumpPending(running)},
+		{"dump waiting for a worker", true, isDumpPending(waiting)},
+		{"failed dump", false, isDumpPending(failed.Conditions)},
+		{"failed dump is not running", false, isDumpRunning(failed.Conditions)},
+		{"failed dump condition", corev1.ConditionTrue, openlibertyv1.GetOperationCondtion(failed.Conditions, openlibertyv1.OperationStatusConditionTypeFailed).Status},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestIsDumpFinished(t *testing.T) {
+	failed := &openlibertyv1.OpenLibertyDump{}
+	failed.Generation = 1
+	failed.Status.ObservedGeneration = 1
+	failedTarget := openlibertyv1.DumpPodStatus{PodName: "pod-a"}
+	setDumpFailed(&failedTarget, "Failed to find a pod or pod is not in running state")
+	failed.Status.Conditions = failedTarget.Conditions
+	updated := failed.DeepCopy()
+	updated.Generation = 2
+	completed := updated.DeepCopy()
+	completed.Status.Conditions = openlibertyv1.SetOperationCondtion(completed.Status.Conditions, openlibertyv1.OperationStatusCondition{
+		Type:   openlibertyv1.OperationStatusConditionTypeCompleted,
+		Status: corev1.ConditionTrue,
+	})
+
+	tests := []Test{
+		{"new dump", false, isDumpFinished(&openlibertyv1.OpenLibertyDump{})},
+		{"failed dump", true, isDumpFinished(failed)},
+		{"failed dump updated", false, isDumpFinished(updated)},
+		{"completed dump updated", true, isDumpFinished(completed)},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
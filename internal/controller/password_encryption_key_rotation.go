@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+
+	olv1 "github.com/OpenLiberty/open-liberty-operator/api/v1"
+	lutils "github.com/OpenLiberty/open-liberty-operator/utils"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Returns the hash of the password encryption key in the internal encryption key Secret of the instance, or an empty string if the instance does not
+// share a password encryption key. The key is hashed together with the namespace so that the hash in the status can not be looked up for common keys.
+func (r *ReconcileOpenLiberty) getPasswordEncryptionKeyHash(instance *olv1.OpenLibertyApplication, passwordEncryptionMetadata *lutils.PasswordEncryptionMetadata) (string, error) {
+	encryptionSecret, sharingEnabled, _, err := r.getValidInternalEncryptionKey(instance, passwordEncryptionMetadata)
+	if !sharingEnabled || err != nil || encryptionSecret == nil {
+		return "", err
+	}
+	matchedKey := PasswordEncryptionKey
+	if _, found := encryptionSecret.Data[AESEncryptionKey]; found {
+		matchedKey = AESEncryptionKey
+	}
+	return hashPasswordEncryptionKey(instance.GetNamespace(), encryptionSecret.Data[matchedKey]), nil
+}
+
+func hashPasswordEncryptionKey(namespace string, encryptionKey []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(namespace))
+	hash.Write([]byte{0})
+	hash.Write(encryptionKey)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Detects a rotation of the password encryption key shared with the instance and starts re-encoding the operator managed passwords with the new key
+func (r *ReconcileOpenLiberty) reconcilePasswordEncryptionKeyRotation(instance *olv1.OpenLibertyApplication, passwordEncryptionMetadata *lutils.PasswordEncryptionMetadata) error {
+	keyHash, err := r.getPasswordEncryptionKeyHash(instance, passwordEncryptionMetadata)
+	if err != nil {
+		return err
+	}
+	instance.Status.PasswordEncryption = getPasswordEncryptionKeyRotationStatus(instance.Status.PasswordEncryption, keyHash)
+	return nil
+}
+
+// Returns the rotation status for the password encryption key with hash keyHash. A new hash starts a rotation that re-encodes the operator managed passwords,
+// and the first key seen by the instance is recorded as already rotated because the managed passwords are encoded with it from the start.
+func getPasswordEncryptionKeyRotationStatus(status *olv1.PasswordEncryptionStatus, keyHash string) *olv1.PasswordEncryptionStatus {
+	if keyHash == "" {
+		return nil
+	}
+	if status == nil {
+		return &olv1.PasswordEncryptionStatus{KeyHash: keyHash, RotationPhase: olv1.PasswordEncryptionKeyRotationPhaseCompleted}
+	}
+	if status.KeyHash != keyHash {
+		status.PreviousKeyHash = status.KeyHash
+		status.KeyHash = keyHash
+		status.RotationPhase = olv1.PasswordEncryptionKeyRotationPhaseReEncoding
+	}
+	return status
+}
+
+// Moves the rotation of the password encryption key from ReEncoding to RollingPods once the LTPA password Secret of the namespace is encoded with the new key.
+// The LTPA password is re-encoded by the instance that reconciles the LTPA config first, so the other instances wait until they read the re-encoded password.
+func (r *ReconcileOpenLiberty) reconcilePasswordEncryptionKeyReEncoding(instance *olv1.OpenLibertyApplication, ltpaConfigMetadata *lutils.LTPAMetadata, passwordEncryptionMetadata *lutils.PasswordEncryptionMetadata) error {
+	if status := instance.Status.PasswordEncryption; status == nil || status.RotationPhase != olv1.PasswordEncryptionKeyRotationPhaseReEncoding {
+		return nil
+	}
+	// without managed LTPA keys, the operator manages no password that is encoded with the key
+	if ltpaConfigMetadata != nil && r.isLTPAKeySharingEnabled(instance) && r.isUsingPasswordEncryptionKeySharing(instance, passwordEncryptionMetadata) {
+		encryptionSecret, _, _, err := r.getValidInternalEncryptionKey(instance, passwordEncryptionMetadata)
+		if err != nil || encryptionSecret == nil {
+			return err
+		}
+		ltpaConfigSecret := &corev1.Secret{}
+		ltpaConfigSecretName := OperatorShortName + "-managed-ltpa-keyed-password" + ltpaConfigMetadata.Name
+		if err := r.GetClient().Get(context.TODO(), types.NamespacedName{Name: ltpaConfigSecretName, Namespace: instance.GetNamespace()}, ltpaConfigSecret); err != nil {
+			if kerrors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		if !isEncodedWithEncryptionKey(ltpaConfigSecret, encryptionSecret) {
+			return nil
+		}
+	}
+	setPasswordEncryptionKeyRotationPhase(instance, olv1.PasswordEncryptionKeyRotationPhaseReEncoding, olv1.PasswordEncryptionKeyRotationPhaseRollingPods)
+	return nil
+}
+
+// Returns true if the passwords in the LTPA password Secret are encoded with the encryption key in encryptionSecret, which the Secret records by the last rotation of the key
+func isEncodedWithEncryptionKey(ltpaConfigSecret *corev1.Secret, encryptionSecret *corev1.Secret) bool {
+	lastRotation, found := encryptionSecret.Data["lastRotation"]
+	encryptionKeyLastRotation, encoded := ltpaConfigSecret.Data["encryptionKeyLastRotation"]
+	return found && encoded && bytes.Equal(lastRotation, encryptionKeyLastRotation)
+}
+
+// Moves the rotation of the password encryption key to its next phase. The passwords are re-encoded before the pods are rolled out, so the pods
+// only roll once with the new key and the re-encoded passwords together, and the rotation completes when the workload has finished rolling out.
+func setPasswordEncryptionKeyRotationPhase(instance *olv1.OpenLibertyApplication, from olv1.PasswordEncryptionKeyRotationPhase, to olv1.PasswordEncryptionKeyRotationPhase) {
+	if status := instance.Status.PasswordEncryption; status != nil && status.RotationPhase == from {
+		status.RotationPhase = to
+	}
+}
+
+// Adds the last rotation annotation of the password encryption key to the pod template. While the passwords are re-encoded with a new key, the pod template
+// keeps the annotation of the previous key, so that the pods are only rolled out with the new key once the LTPA password Secret is re-encoded with it.
+func addPasswordEncryptionKeyRotationAnnotation(pts *corev1.PodTemplateSpec, instance *olv1.OpenLibertyApplication, lastRotationAnnotation map[string]string) {
+	if status := instance.Status.PasswordEncryption; status != nil && status.RotationPhase == olv1.PasswordEncryptionKeyRotationPhaseReEncoding {
+		return
+	}
+	lutils.AddPodTemplateSpecAnnotation(pts, lastRotationAnnotation)
+}
+
+// Returns true if all the replicas of the Deployment or StatefulSet run the latest pod template
+func isWorkloadRolledOut(obj client.Object) bool {
+	switch workload := obj.(type) {
+	case *appsv1.Deployment:
+		replicas := int32(1)
+		if workload.Spec.Replicas != nil {
+			replicas = *workload.Spec.Replicas
+		}
+		return workload.Status.ObservedGeneration >= workload.Generation && workload.Status.UpdatedReplicas == replicas &&
+			workload.Status.Replicas == workload.Status.UpdatedReplicas && workload.Status.AvailableReplicas >= replicas
+	case *appsv1.StatefulSet:
+		replicas := int32(1)
+		if workload.Spec.Replicas != nil {
+			replicas = *workload.Spec.Replicas
+		}
+		return workload.Status.ObservedGeneration >= workload.Generation && workload.Status.UpdatedReplicas == replicas &&
+			workload.Status.ReadyReplicas == replicas && workload.Status.CurrentRevision == workload.Status.UpdateRevision
+	}
+	return true
+}
@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"testing"
+
+	openlibertyv1 "github.com/OpenLiberty/open-liberty-operator/api/v1"
+	lutils "github.com/OpenLiberty/open-liberty-operator/utils"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestHashPasswordEncryptionKey(t *testing.T) {
+	hash := hashPasswordEncryptionKey("ns", []byte("key"))
+
+	tests := []Test{
+		{"same key and namespace", hash, hashPasswordEncryptionKey("ns", []byte("key"))},
+		{"new key", false, hash == hashPasswordEncryptionKey("ns", []byte("new-key"))},
+		{"other namespace", false, hash == hashPasswordEncryptionKey("other-ns", []byte("key"))},
+		{"hash length", 64, len(hash)},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestGetPasswordEncryptionKeyRotationStatus(t *testing.T) {
+	rotated := getPasswordEncryptionKeyRotationStatus(&openlibertyv1.PasswordEncryptionStatus{KeyHash: "a", RotationPhase: openlibertyv1.PasswordEncryptionKeyRotationPhaseCompleted}, "b")
+	unchanged := getPasswordEncryptionKeyRotationStatus(&openlibertyv1.PasswordEncryptionStatus{KeyHash: "a", PreviousKeyHash: "z", RotationPhase: openlibertyv1.PasswordEncryptionKeyRotationPhaseRollingPods}, "a")
+
+	tests := []Test{
+		{"sharing disabled", (*openlibertyv1.PasswordEncryptionStatus)(nil), getPasswordEncryptionKeyRotationStatus(&openlibertyv1.PasswordEncryptionStatus{KeyHash: "a"}, "")},
+		{"first key", &openlibertyv1.PasswordEncryptionStatus{KeyHash: "a", RotationPhase: openlibertyv1.PasswordEncryptionKeyRotationPhaseCompleted}, getPasswordEncryptionKeyRotationStatus(nil, "a")},
+		{"rotated key", &openlibertyv1.PasswordEncryptionStatus{KeyHash: "b", PreviousKeyHash: "a", RotationPhase: openlibertyv1.PasswordEncryptionKeyRotationPhaseReEncoding}, rotated},
+		{"unchanged key", &openlibertyv1.PasswordEncryptionStatus{KeyHash: "a", PreviousKeyHash: "z", RotationPhase: openlibertyv1.PasswordEncryptionKeyRotationPhaseRollingPods}, unchanged},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestSetPasswordEncryptionKeyRotationPhase(t *testing.T) {
+	instance := &openlibertyv1.OpenLibertyApplication{}
+	instance.Status.PasswordEncryption = &openlibertyv1.PasswordEncryptionStatus{KeyHash: "b", PreviousKeyHash: "a", RotationPhase: openlibertyv1.PasswordEncryptionKeyRotationPhaseReEncoding}
+
+	setPasswordEncryptionKeyRotationPhase(instance, openlibertyv1.PasswordEncryptionKeyRotationPhaseRollingPods, openlibertyv1.PasswordEncryptionKeyRotationPhaseCompleted)
+	notReEncoded := instance.Status.PasswordEncryption.RotationPhase
+	setPasswordEncryptionKeyRotationPhase(instance, openlibertyv1.PasswordEncryptionKeyRotationPhaseReEncoding, openlibertyv1.PasswordEncryptionKeyRotationPhaseRollingPods)
+	reEncoded := instance.Status.PasswordEncryption.RotationPhase
+
+	disabled := &openlibertyv1.OpenLibertyApplication{}
+	setPasswordEncryptionKeyRotationPhase(disabled, openlibertyv1.PasswordEncryptionKeyRotationPhaseReEncoding, openlibertyv1.PasswordEncryptionKeyRotationPhaseRollingPods)
+
+	tests := []Test{
+		{"pods not rolled before re-encoding", openlibertyv1.PasswordEncryptionKeyRotationPhaseReEncoding, notReEncoded},
+		{"pods rolled after re-encoding", openlibertyv1.PasswordEncryptionKeyRotationPhaseRollingPods, reEncoded},
+		{"sharing disabled", (*openlibertyv1.PasswordEncryptionStatus)(nil), disabled.Status.PasswordEncryption},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestAddPasswordEncryptionKeyRotationAnnotation(t *testing.T) {
+	annotationKey := lutils.GetLastRotationLabelKey(PASSWORD_ENCRYPTION_RESOURCE_SHARING_FILE_NAME)
+	newKeyAnnotation := map[string]string{annotationKey: "200"}
+	// the pod template of the instance in phase, after the annotation of the new key was added to it
+	podTemplate := func(phase openlibertyv1.PasswordEncryptionKeyRotationPhase) *corev1.PodTemplateSpec {
+		instance := &openlibertyv1.OpenLibertyApplication{}
+		instance.Status.PasswordEncryption = &openlibertyv1.PasswordEncryptionStatus{KeyHash: "b", PreviousKeyHash: "a", RotationPhase: phase}
+		pts := &corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{annotationKey: "100", "libertyOperator": "Open Liberty"}}}
+		addPasswordEncryptionKeyRotationAnnotation(pts, instance, newKeyAnnotation)
+		return pts
+	}
+	newPodTemplate := &corev1.PodTemplateSpec{}
+	addPasswordEncryptionKeyRotationAnnotation(newPodTemplate, &openlibertyv1.OpenLibertyApplication{}, newKeyAnnotation)
+
+	tests := []Test{
+		{"pod template unchanged while re-encoding", &corev1.PodTemplateSpec{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{annotationKey: "100", "libertyOperator": "Open Liberty"}}},
+			podTemplate(openlibertyv1.PasswordEncryptionKeyRotationPhaseReEncoding)},
+		{"new key rolled out after re-encoding", "200", podTemplate(openlibertyv1.PasswordEncryptionKeyRotationPhaseRollingPods).Annotations[annotationKey]},
+		{"new key after the rotation completed", "200", podTemplate(openlibertyv1.PasswordEncryptionKeyRotationPhaseCompleted).Annotations[annotationKey]},
+		{"first key", "200", newPodTemplate.Annotations[annotationKey]},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestIsWorkloadRolledOut(t *testing.T) {
+	replicas := int32(2)
+	deploy := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Generation: 2}, Spec: appsv1.DeploymentSpec{Replicas: &replicas}}
+	deploy.Status = appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 2, AvailableReplicas: 2}
+	rollingDeploy := deploy.DeepCopy()
+	rollingDeploy.Status = appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 3, UpdatedReplicas: 2, AvailableReplicas: 2}
+	outdatedDeploy := deploy.DeepCopy()
+	outdatedDeploy.Status.ObservedGeneration = 1
+
+	statefulSet := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Generation: 2}, Spec: appsv1.StatefulSetSpec{Replicas: &replicas}}
+	statefulSet.Status = appsv1.StatefulSetStatus{ObservedGeneration: 2, Replicas: 2, UpdatedReplicas: 2, ReadyReplicas: 2, CurrentRevision: "b", UpdateRevision: "b"}
+	rollingStatefulSet := statefulSet.DeepCopy()
+	rollingStatefulSet.Status.UpdatedReplicas = 1
+	rollingStatefulSet.Status.CurrentRevision = "a"
+
+	tests := []Test{
+		{"deployment rolled out", true, isWorkloadRolledOut(deploy)},
+		{"deployment with old pods", false, isWorkloadRolledOut(rollingDeploy)},
+		{"deployment not observed", false, isWorkloadRolledOut(outdatedDeploy)},
+		{"statefulset rolled out", true, isWorkloadRolledOut(statefulSet)},
+		{"statefulset with old pods", false, isWorkloadRolledOut(rollingStatefulSet)},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestIsEncodedWithEncryptionKey(t *testing.T) {
+	encryptionSecret := &corev1.Secret{Data: map[string][]byte{"lastRotation": []byte("200")}}
+	createLTPAConfigSecret := func(data map[string][]byte) *corev1.Secret {
+		return &corev1.Secret{Data: data}
+	}
+
+	tests := []Test{
+		{"encoded with the new key", true, isEncodedWithEncryptionKey(createLTPAConfigSecret(map[string][]byte{"encryptionKeyLastRotation": []byte("200")}), encryptionSecret)},
+		{"encoded with the previous key", false, isEncodedWithEncryptionKey(createLTPAConfigSecret(map[string][]byte{"encryptionKeyLastRotation": []byte("100")}), encryptionSecret)},
+		{"encoded without a key", false, isEncodedWithEncryptionKey(createLTPAConfigSecret(map[string][]byte{"password": []byte("{aes}encoded")}), encryptionSecret)},
+		{"key without last rotation", false, isEncodedWithEncryptionKey(createLTPAConfigSecret(map[string][]byte{"encryptionKeyLastRotation": []byte("200")}), &corev1.Secret{})},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
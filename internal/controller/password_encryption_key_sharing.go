@@ -9,6 +9,7 @@ import (
 
 	olv1 "github.com/OpenLiberty/open-liberty-operator/api/v1"
 	lutils "github.com/OpenLiberty/open-liberty-operator/utils"
+	"github.com/OpenLiberty/open-liberty-operator/utils/metrics"
 	tree "github.com/OpenLiberty/open-liberty-operator/utils/tree"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -401,7 +402,8 @@ func (r *ReconcileOpenLiberty) mirrorEncryptionKeySecretState(instance *olv1.Ope
 
 	// Case 2: user encryption secret exists, no internal secret: Create internalEncryptionSecret
 	// Case 3: user encryption secret exists, internal secret exists: Update internalEncryptionSecret
-	return r.CreateOrUpdate(internalEncryptionSecret, nil, func() error {
+	rotated := false
+	err := r.CreateOrUpdate(internalEncryptionSecret, nil, func() error {
 		if internalEncryptionSecret.Data == nil {
 			internalEncryptionSecret.Data = make(map[string][]byte)
 		}
@@ -413,9 +415,18 @@ func (r *ReconcileOpenLiberty) mirrorEncryptionKeySecretState(instance *olv1.Ope
 		if string(internalPasswordEncryptionKey) != string(userPasswordEncryptionKey) {
 			internalEncryptionSecret.Data[syncedKey] = userPasswordEncryptionKey
 			internalEncryptionSecret.Data["lastRotation"] = []byte(fmt.Sprint(time.Now().Unix()))
+			rotated = true
 		}
 		return nil
 	})
+	if err == nil && rotated {
+		if syncedKey == AESEncryptionKey {
+			metrics.CountKeyRotation(AES_ENCRYPTION_RESOURCE_SHARING_FILE_NAME)
+		} else {
+			metrics.CountKeyRotation(PASSWORD_ENCRYPTION_RESOURCE_SHARING_FILE_NAME)
+		}
+	}
+	return err
 }
 
 func (r *ReconcileOpenLiberty) getSecret(instance *olv1.OpenLibertyApplication, secretName string) (*corev1.Secret, bool, error) {
@@ -0,0 +1,180 @@
+package v1
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	olv1 "github.com/OpenLiberty/open-liberty-operator/api/v1"
+	lutils "github.com/OpenLiberty/open-liberty-operator/utils"
+	oputils "github.com/application-stacks/runtime-component-operator/utils"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+var openlibertyapplicationlog = logf.Log.WithName("openlibertyapplication-resource")
+
+// The IDs of the SSO providers are used in the names of the SSO environment variables and the keys of the SSO secret
+var ssoProviderIDRegexp = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+
+// SetupOpenLibertyApplicationWebhookWithManager registers the webhooks for OpenLibertyApplication in the manager.
+// The conversion webhook is registered as well, because v1 is the hub version that v1beta2 is converted to and from.
+func SetupOpenLibertyApplicationWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &olv1.OpenLibertyApplication{}).
+		WithValidator(&OpenLibertyApplicationCustomValidator{}).
+		WithDefaulter(&OpenLibertyApplicationCustomDefaulter{}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/mutate-apps-openliberty-io-v1-openlibertyapplication,mutating=true,failurePolicy=fail,sideEffects=None,groups=apps.openliberty.io,resources=openlibertyapplications,verbs=create;update,versions=v1,name=mopenlibertyapplication-v1.kb.io,admissionReviewVersions=v1
+
+// OpenLibertyApplicationCustomDefaulter sets the default values of an OpenLibertyApplication when it is created or updated.
+type OpenLibertyApplicationCustomDefaulter struct{}
+
+var _ admission.Defaulter[*olv1.OpenLibertyApplication] = &OpenLibertyApplicationCustomDefaulter{}
+
+// Default sets the default values that do not depend on other fields of the OpenLibertyApplication. The values that are derived from
+// other fields, such as the service port that depends on .spec.manageTLS, are left to Initialize() so that they follow later changes.
+func (d *OpenLibertyApplicationCustomDefaulter) Default(_ context.Context, olapp *olv1.OpenLibertyApplication) error {
+	openlibertyapplicationlog.V(1).Info("Defaulting OpenLibertyApplication", "name", olapp.GetName(), "namespace", olapp.GetNamespace())
+	if olapp.Spec.PullPolicy == nil {
+		pp := corev1.PullIfNotPresent
+		olapp.Spec.PullPolicy = &pp
+	}
+	if olapp.Spec.Service == nil {
+		olapp.Spec.Service = &olv1.OpenLibertyApplicationService{}
+	}
+	if olapp.Spec.Service.Type == nil {
+		st := corev1.ServiceTypeClusterIP
+		olapp.Spec.Service.Type = &st
+	}
+	return nil
+}
+
+// +kubebuilder:webhook:path=/validate-apps-openliberty-io-v1-openlibertyapplication,mutating=false,failurePolicy=fail,sideEffects=None,groups=apps.openliberty.io,resources=openlibertyapplications,verbs=create;update,versions=v1,name=vopenlibertyapplication-v1.kb.io,admissionReviewVersions=v1
+
+// OpenLibertyApplicationCustomValidator rejects an OpenLibertyApplication with an invalid spec when it is created or updated.
+type OpenLibertyApplicationCustomValidator struct{}
+
+var _ admission.Validator[*olv1.OpenLibertyApplication] = &OpenLibertyApplicationCustomValidator{}
+
+// ValidateCreate validates the OpenLibertyApplication being created
+func (v *OpenLibertyApplicationCustomValidator) ValidateCreate(_ context.Context, olapp *olv1.OpenLibertyApplication) (admission.Warnings, error) {
+	openlibertyapplicationlog.V(1).Info("Validating OpenLibertyApplication on create", "name", olapp.GetName(), "namespace", olapp.GetNamespace())
+	return validateOpenLibertyApplication(olapp, nil)
+}
+
+// ValidateUpdate validates the updated OpenLibertyApplication. The SSO provider IDs that are unchanged from the old
+// OpenLibertyApplication only return warnings, so that applications created before the IDs were checked can still be updated.
+func (v *OpenLibertyApplicationCustomValidator) ValidateUpdate(_ context.Context, oldOlapp, olapp *olv1.OpenLibertyApplication) (admission.Warnings, error) {
+	openlibertyapplicationlog.V(1).Info("Validating OpenLibertyApplication on update", "name", olapp.GetName(), "namespace", olapp.GetNamespace())
+	return validateOpenLibertyApplication(olapp, oldOlapp)
+}
+
+// ValidateDelete allows any OpenLibertyApplication to be deleted
+func (v *OpenLibertyApplicationCustomValidator) ValidateDelete(_ context.Context, _ *olv1.OpenLibertyApplication) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// Returns an Invalid error listing all the problems found in the spec of the OpenLibertyApplication, or nil if it is valid.
+// oldOlapp is the OpenLibertyApplication being updated, or nil when it is created.
+func validateOpenLibertyApplication(olapp, oldOlapp *olv1.OpenLibertyApplication) (admission.Warnings, error) {
+	specPath := field.NewPath("spec")
+	var allErrs field.ErrorList
+
+	// The checks shared with the controller, which reports them in the Reconciled condition
+	if _, err := oputils.Validate(olapp); err != nil {
+		allErrs = append(allErrs, invalidWithoutValue(specPath, err))
+	}
+	if _, err := lutils.Validate(olapp); err != nil {
+		allErrs = append(allErrs, invalidWithoutValue(specPath.Child("serviceability"), err))
+	}
+
+	// A Knative service replaces the Deployment or StatefulSet of the application
+	if olapp.GetCreateKnativeService() != nil && *olapp.GetCreateKnativeService() && olapp.Spec.StatefulSet != nil {
+		allErrs = append(allErrs, field.Forbidden(specPath.Child("statefulSet"), "cannot be set when spec.createKnativeService is true"))
+	}
+
+	var oldSSO *olv1.OpenLibertyApplicationSSO
+	if oldOlapp != nil {
+		oldSSO = oldOlapp.Spec.SSO
+	}
+	ssoErrs, warnings := validateSSOProviderIDs(olapp.Spec.SSO, oldSSO, specPath.Child("sso"))
+	allErrs = append(allErrs, ssoErrs...)
+
+	if scc := olapp.GetSemeruCloudCompiler(); scc != nil && scc.GetHealth() != nil && scc.GetHealth().GetPort() != nil {
+		if port := *scc.GetHealth().GetPort(); port < 1 || port > 65535 {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("semeruCloudCompiler", "health", "port"), port, "must be between 1 and 65535, inclusive"))
+		}
+	}
+
+	if ltpa := olapp.Spec.LTPA; ltpa != nil && ltpa.KeysSecretRef != nil {
+		for _, msg := range validation.IsDNS1123Subdomain(*ltpa.KeysSecretRef) {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("ltpa", "keysSecretRef"), *ltpa.KeysSecretRef, msg))
+		}
+	}
+
+	if len(allErrs) == 0 {
+		return warnings, nil
+	}
+	return warnings, apierrors.NewInvalid(olv1.GroupVersion.WithKind("OpenLibertyApplication").GroupKind(), olapp.GetName(), allErrs)
+}
+
+// Returns the errors of the OIDC and OAuth2 provider IDs. The IDs are upper-cased in the names of the SSO environment
+// variables, so two IDs that only differ in case are duplicates. The problems of an ID that oldSSO already had at the
+// same index are returned as warnings instead.
+func validateSSOProviderIDs(sso, oldSSO *olv1.OpenLibertyApplicationSSO, ssoPath *field.Path) (field.ErrorList, admission.Warnings) {
+	if sso == nil {
+		return nil, nil
+	}
+	oldIDs := map[string]string{}
+	if oldSSO != nil {
+		for i, oidcClient := range oldSSO.OIDC {
+			oldIDs[ssoPath.Child("oidc").Index(i).Child("id").String()] = oidcClient.ID
+		}
+		for i, oauth2Client := range oldSSO.Oauth2 {
+			oldIDs[ssoPath.Child("oauth2").Index(i).Child("id").String()] = oauth2Client.ID
+		}
+	}
+	var allErrs field.ErrorList
+	var warnings admission.Warnings
+	addError := func(idPath *field.Path, id string, err *field.Error) {
+		if oldID, found := oldIDs[idPath.String()]; found && oldID == id {
+			warnings = append(warnings, err.Error())
+			return
+		}
+		allErrs = append(allErrs, err)
+	}
+	ids := map[string]bool{}
+	validateID := func(idPath *field.Path, id, defaultID string) {
+		rawID := id
+		if id == "" {
+			id = defaultID
+		} else if !ssoProviderIDRegexp.MatchString(id) {
+			addError(idPath, rawID, field.Invalid(idPath, id, "must consist of alphanumeric characters or '_'"))
+			return
+		}
+		if ids[strings.ToUpper(id)] {
+			addError(idPath, rawID, field.Duplicate(idPath, id))
+			return
+		}
+		ids[strings.ToUpper(id)] = true
+	}
+	for i, oidcClient := range sso.OIDC {
+		validateID(ssoPath.Child("oidc").Index(i).Child("id"), oidcClient.ID, "oidc")
+	}
+	for i, oauth2Client := range sso.Oauth2 {
+		validateID(ssoPath.Child("oauth2").Index(i).Child("id"), oauth2Client.ID, "oauth2")
+	}
+	return allErrs, warnings
+}
+
+// Returns an Invalid field error with the message of err that does not repeat the value of the field
+func invalidWithoutValue(fieldPath *field.Path, err error) *field.Error {
+	return field.Invalid(fieldPath, field.OmitValueType{}, err.Error())
+}
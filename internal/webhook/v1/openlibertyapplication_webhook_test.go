@@ -0,0 +1,191 @@
+package v1
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	olv1 "github.com/OpenLiberty/open-liberty-operator/api/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+type Test struct {
+	test     string
+	expected interface{}
+	actual   interface{}
+}
+
+func verifyTests(tests []Test) error {
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.actual, tt.expected) {
+			return fmt.Errorf("%s test expected: (%v) actual: (%v)", tt.test, tt.expected, tt.actual)
+		}
+	}
+	return nil
+}
+
+func newOpenLibertyApplication() *olv1.OpenLibertyApplication {
+	return &olv1.OpenLibertyApplication{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "ns", Labels: map[string]string{}},
+		Spec:       olv1.OpenLibertyApplicationSpec{ApplicationImage: "icr.io/appcafe/open-liberty"},
+	}
+}
+
+// Returns the fields rejected by the validating webhook, or the error if it is not an Invalid error
+func invalidFields(olapp *olv1.OpenLibertyApplication) []string {
+	validator := &OpenLibertyApplicationCustomValidator{}
+	_, createErr := validator.ValidateCreate(context.TODO(), olapp)
+	_, updateErr := validator.ValidateUpdate(context.TODO(), newOpenLibertyApplication(), olapp)
+	if fmt.Sprint(createErr) != fmt.Sprint(updateErr) {
+		return []string{fmt.Sprintf("create: %v, update: %v", createErr, updateErr)}
+	}
+	fields := []string{}
+	if createErr == nil {
+		return fields
+	}
+	if !apierrors.IsInvalid(createErr) {
+		return []string{createErr.Error()}
+	}
+	for _, cause := range createErr.(apierrors.APIStatus).Status().Details.Causes {
+		fields = append(fields, cause.Field)
+	}
+	return fields
+}
+
+func TestValidateOpenLibertyApplication(t *testing.T) {
+	trueValue := true
+	valid := newOpenLibertyApplication()
+
+	knativeStatefulSet := newOpenLibertyApplication()
+	knativeStatefulSet.Spec.CreateKnativeService = &trueValue
+	knativeStatefulSet.Spec.StatefulSet = &olv1.OpenLibertyApplicationStatefulSet{}
+
+	knativeDeployment := newOpenLibertyApplication()
+	knativeDeployment.Spec.CreateKnativeService = &trueValue
+	knativeDeployment.Spec.Deployment = &olv1.OpenLibertyApplicationDeployment{}
+
+	ssoIDs := newOpenLibertyApplication()
+	ssoIDs.Spec.SSO = &olv1.OpenLibertyApplicationSSO{
+		OIDC:   []olv1.OidcClient{{}, {ID: "my-provider"}, {ID: "OAuth2"}},
+		Oauth2: []olv1.OAuth2Client{{}, {ID: "oidc"}, {ID: "github_2"}},
+	}
+
+	serviceabilityWithoutStorage := newOpenLibertyApplication()
+	serviceabilityWithoutStorage.Spec.Serviceability = &olv1.OpenLibertyApplicationServiceability{}
+
+	serviceabilityInvalidSize := newOpenLibertyApplication()
+	serviceabilityInvalidSize.Spec.Serviceability = &olv1.OpenLibertyApplicationServiceability{Size: "1 gigabyte"}
+
+	semeruPort := func(port int32) *olv1.OpenLibertyApplication {
+		olapp := newOpenLibertyApplication()
+		olapp.Spec.SemeruCloudCompiler = &olv1.OpenLibertyApplicationSemeruCloudCompiler{
+			Enable: true,
+			Health: &olv1.OpenLibertyApplicationSemeruCloudCompilerHealth{Port: &port},
+		}
+		return olapp
+	}
+
+	ltpaKeysSecret := func(name string) *olv1.OpenLibertyApplication {
+		olapp := newOpenLibertyApplication()
+		olapp.Spec.LTPA = &olv1.OpenLibertyApplicationLTPA{KeysSecretRef: &name}
+		return olapp
+	}
+
+	multipleErrors := semeruPort(0)
+	multipleErrors.Spec.CreateKnativeService = &trueValue
+	multipleErrors.Spec.StatefulSet = &olv1.OpenLibertyApplicationStatefulSet{}
+
+	tests := []Test{
+		{"valid", []string{}, invalidFields(valid)},
+		{"Knative service with StatefulSet", []string{"spec.statefulSet"}, invalidFields(knativeStatefulSet)},
+		{"Knative service with Deployment", []string{}, invalidFields(knativeDeployment)},
+		{"SSO provider IDs", []string{"spec.sso.oidc[1].id", "spec.sso.oauth2[0].id", "spec.sso.oauth2[1].id"}, invalidFields(ssoIDs)},
+		{"serviceability without storage", []string{"spec.serviceability"}, invalidFields(serviceabilityWithoutStorage)},
+		{"serviceability with invalid size", []string{"spec.serviceability"}, invalidFields(serviceabilityInvalidSize)},
+		{"Semeru health port 38600", []string{}, invalidFields(semeruPort(38600))},
+		{"Semeru health port 0", []string{"spec.semeruCloudCompiler.health.port"}, invalidFields(semeruPort(0))},
+		{"Semeru health port 65536", []string{"spec.semeruCloudCompiler.health.port"}, invalidFields(semeruPort(65536))},
+		{"LTPA keys Secret", []string{}, invalidFields(ltpaKeysSecret("my-ltpa-keys"))},
+		{"invalid LTPA keys Secret", []string{"spec.ltpa.keysSecretRef"}, invalidFields(ltpaKeysSecret("My_LTPA_Keys"))},
+		{"multiple errors", []string{"spec.statefulSet", "spec.semeruCloudCompiler.health.port"}, invalidFields(multipleErrors)},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	_, err := (&OpenLibertyApplicationCustomValidator{}).ValidateDelete(context.TODO(), knativeStatefulSet)
+	if err != nil {
+		t.Fatalf("delete test expected no error, actual: %v", err)
+	}
+}
+
+func TestValidateOpenLibertyApplicationUpdate(t *testing.T) {
+	validator := &OpenLibertyApplicationCustomValidator{}
+	old := newOpenLibertyApplication()
+	old.Spec.SSO = &olv1.OpenLibertyApplicationSSO{
+		OIDC:   []olv1.OidcClient{{ID: "my-provider"}, {}},
+		Oauth2: []olv1.OAuth2Client{{ID: "oidc"}},
+	}
+
+	// the IDs accepted before they were checked only return warnings
+	newImage := old.DeepCopy()
+	newImage.Spec.ApplicationImage = "icr.io/appcafe/open-liberty:latest"
+	newImageWarnings, newImageErr := validator.ValidateUpdate(context.TODO(), old, newImage)
+
+	changedID := old.DeepCopy()
+	changedID.Spec.SSO.OIDC[0].ID = "my-other-provider"
+	_, changedIDErr := validator.ValidateUpdate(context.TODO(), old, changedID)
+
+	addedID := old.DeepCopy()
+	addedID.Spec.SSO.OIDC = append(addedID.Spec.SSO.OIDC, olv1.OidcClient{ID: "another-provider"})
+	addedIDWarnings, addedIDErr := validator.ValidateUpdate(context.TODO(), old, addedID)
+
+	tests := []Test{
+		{"unchanged IDs", nil, newImageErr},
+		{"unchanged ID warnings", admission.Warnings{
+			`spec.sso.oidc[0].id: Invalid value: "my-provider": must consist of alphanumeric characters or '_'`,
+			`spec.sso.oauth2[0].id: Duplicate value: "oidc"`,
+		}, newImageWarnings},
+		{"changed ID", true, apierrors.IsInvalid(changedIDErr)},
+		{"added ID", true, apierrors.IsInvalid(addedIDErr)},
+		{"added ID causes", []metav1.StatusCause{{Type: metav1.CauseTypeFieldValueInvalid, Message: `Invalid value: "another-provider": must consist of alphanumeric characters or '_'`, Field: "spec.sso.oidc[2].id"}},
+			addedIDErr.(apierrors.APIStatus).Status().Details.Causes},
+		{"added ID warnings", 2, len(addedIDWarnings)},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestDefaultOpenLibertyApplication(t *testing.T) {
+	olapp := newOpenLibertyApplication()
+	olapp.Labels["app.kubernetes.io/part-of"] = "store"
+	if err := (&OpenLibertyApplicationCustomDefaulter{}).Default(context.TODO(), olapp); err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	manageTLS := false
+	notManagedTLS := newOpenLibertyApplication()
+	notManagedTLS.Spec.ManageTLS = &manageTLS
+	if err := (&OpenLibertyApplicationCustomDefaulter{}).Default(context.TODO(), notManagedTLS); err != nil {
+		t.Fatalf("%v", err)
+	}
+	notManagedTLS.Initialize()
+
+	pullPolicy := corev1.PullIfNotPresent
+	serviceType := corev1.ServiceTypeClusterIP
+	tests := []Test{
+		{"pull policy", &pullPolicy, olapp.Spec.PullPolicy},
+		{"service type", &serviceType, olapp.Spec.Service.Type},
+		{"application name not persisted", "", olapp.Spec.ApplicationName},
+		{"service port not persisted", int32(0), olapp.Spec.Service.Port},
+		{"service port follows manageTLS", int32(9080), notManagedTLS.Spec.Service.Port},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
@@ -0,0 +1,13 @@
+package v1
+
+import (
+	olv1 "github.com/OpenLiberty/open-liberty-operator/api/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// SetupOpenLibertyDumpWebhookWithManager registers the conversion webhook for OpenLibertyDump in the manager.
+// v1 is the hub version that the v1beta2 version of OpenLibertyDump is converted to and from.
+func SetupOpenLibertyDumpWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr, &olv1.OpenLibertyDump{}).
+		Complete()
+}
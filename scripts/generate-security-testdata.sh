@@ -0,0 +1,40 @@
+#!/bin/bash -e
+
+# Writes the output of the Liberty securityUtility command to utils/security/testdata, so that the tests of utils/security
+# check the passwords and LTPA keys created by the operator against the ones created by Liberty
+
+# -----------------------------------------------------
+# Prereqs to running this script
+# -----------------------------------------------------
+# 1. Have "podman" or "docker" installed & on the path
+
+BASE_DIR="$(cd $(dirname $0) && pwd)"
+TESTDATA_DIR="$BASE_DIR/../utils/security/testdata"
+
+CONTAINER_CLI="${CONTAINER_CLI:-docker}"
+# A Liberty release that encodes with AES-256 and supports --base64Key
+IMAGE="${IMAGE:-icr.io/appcafe/open-liberty:full-java17-openj9-ubi}"
+# A Liberty release that still encodes with AES-128
+LEGACY_IMAGE="${LEGACY_IMAGE:-icr.io/appcafe/open-liberty:22.0.0.12-full-java11-openj9-ubi}"
+
+# Keep in sync with the constants in utils/security/password_test.go and utils/security/ltpa_test.go
+PASSWORD="Pa55w0rd-with-a-longer-value-than-one-block"
+KEY="my-key"
+BASE64_KEY="BwcHBwcHBwcHBwcHBwcHBwcHBwcHBwcHBwcHBwcHBwc="
+LTPA_PASSWORD="ltpa-password"
+
+function security_utility() {
+    local image=$1
+    shift
+    $CONTAINER_CLI run --rm --user "$(id -u)" -v "$TESTDATA_DIR:/testdata:z" --entrypoint /opt/ol/wlp/bin/securityUtility "$image" "$@"
+}
+
+mkdir -p "$TESTDATA_DIR"
+rm -f "$TESTDATA_DIR/ltpa.keys"
+
+security_utility "$IMAGE" encode --encoding=aes "$PASSWORD" > "$TESTDATA_DIR/aes-default.txt"
+security_utility "$IMAGE" encode --encoding=aes --key="$KEY" "$PASSWORD" > "$TESTDATA_DIR/aes-key.txt"
+security_utility "$IMAGE" encode --encoding=aes --base64Key="$BASE64_KEY" "$PASSWORD" > "$TESTDATA_DIR/aes-base64key.txt"
+security_utility "$LEGACY_IMAGE" encode --encoding=aes "$PASSWORD" > "$TESTDATA_DIR/aes128-default.txt"
+security_utility "$LEGACY_IMAGE" encode --encoding=aes --key="$KEY" "$PASSWORD" > "$TESTDATA_DIR/aes128-key.txt"
+security_utility "$IMAGE" createLTPAKeys --file=/testdata/ltpa.keys --password="$LTPA_PASSWORD"
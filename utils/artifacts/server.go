@@ -0,0 +1,382 @@
+package artifacts
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	openlibertyv1 "github.com/OpenLiberty/open-liberty-operator/api/v1"
+	"github.com/OpenLiberty/open-liberty-operator/utils"
+	"github.com/go-logr/logr"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	certutil "k8s.io/client-go/util/cert"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+// The artifacts server streams the files created by day-2 operations out of the application pods, so that users who
+// cannot exec into the pods can still download the diagnostics they requested. Requests are authenticated with a
+// TokenReview of their bearer token, and authorized with a SubjectAccessReview of the "get" verb on the artifact
+// subresource of the operation, e.g. openlibertydumps/artifact.
+
+const (
+	// Subresource checked by the SubjectAccessReview of a download
+	ArtifactSubresource = "artifact"
+
+	ResourceDumps           = "openlibertydumps"
+	ResourcePerformanceData = "openlibertyperformancedata"
+	ResourceTraces          = "openlibertytraces"
+
+	serviceabilityDir = "/serviceability"
+)
+
+// Artifact is a file or directory in the serviceability folder of a pod
+type Artifact struct {
+	PodName string
+	Path    string
+	// Set when Path is a directory that is streamed as a gzipped tar archive
+	Archive bool
+}
+
+// Returns the name of the downloaded file
+func (a *Artifact) fileName() string {
+	if a.Archive {
+		return a.PodName + "_" + path.Base(a.Path) + ".tar.gz"
+	}
+	return path.Base(a.Path)
+}
+
+// Returns the command that writes the artifact to standard output
+func (a *Artifact) command() []string {
+	if a.Archive {
+		return []string{"tar", "-czf", "-", "-C", a.Path, "."}
+	}
+	return []string{"cat", a.Path}
+}
+
+// Error of a download request, reported to the client with the HTTP status code
+type requestError struct {
+	code    int
+	message string
+}
+
+func (e *requestError) Error() string {
+	return e.message
+}
+
+func newRequestError(code int, format string, a ...any) error {
+	return &requestError{code: code, message: fmt.Sprintf(format, a...)}
+}
+
+type Server struct {
+	// Creates the token and subject access reviews
+	client client.Client
+	// Reads the operation instances and pods, which may be outside of the namespaces cached by the manager
+	reader client.Reader
+	// Writes the standard output of the command run in the app container of the pod to w
+	stream func(ctx context.Context, podName, podNamespace string, command []string, w io.Writer) error
+	logger logr.Logger
+}
+
+func NewServer(c client.Client, reader client.Reader, config *rest.Config, logger logr.Logger) *Server {
+	return &Server{
+		client: c,
+		reader: reader,
+		stream: func(ctx context.Context, podName, podNamespace string, command []string, w io.Writer) error {
+			stderr, err := utils.StreamCommandInContainer(ctx, config, podName, podNamespace, "app", command, w)
+			if err != nil && stderr != "" {
+				return fmt.Errorf("%v: %s", err, strings.TrimSpace(stderr))
+			}
+			return err
+		},
+		logger: logger,
+	}
+}
+
+// Handler returns the handler of GET /artifacts/namespaces/{namespace}/{resource}/{name}. Dumps, performance data and
+// traces of several pods can be selected with the pod query parameter, and dumps with the file query parameter.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /artifacts/namespaces/{namespace}/{resource}/{name}", s.serveArtifact)
+	return mux
+}
+
+// ServeArtifacts listens on bindAddress and serves the artifacts of day-2 operations over TLS. The caller closes the
+// returned listener to stop the server.
+func ServeArtifacts(mgr manager.Manager, bindAddress string, tlsConfig *tls.Config, logger logr.Logger) (net.Listener, error) {
+	listener, err := tls.Listen("tcp", bindAddress, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	logger.Info(fmt.Sprintf("Serving artifacts at address: %s", listener.Addr()))
+	server := &http.Server{
+		Handler:           NewServer(mgr.GetClient(), mgr.GetAPIReader(), mgr.GetConfig(), logger).Handler(),
+		ReadHeaderTimeout: 30 * time.Second,
+	}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, net.ErrClosed) {
+			logger.Error(err, "Artifacts server stopped")
+		}
+	}()
+	return listener, nil
+}
+
+// NewSelfSignedTLSConfig returns a TLS configuration with a certificate generated for the operator, for use when no
+// certificate is provided
+func NewSelfSignedTLSConfig() (*tls.Config, error) {
+	certPEM, keyPEM, err := certutil.GenerateSelfSignedCertKey("open-liberty-operator", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}, nil
+}
+
+func (s *Server) serveArtifact(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	namespace, resource, name := r.PathValue("namespace"), r.PathValue("resource"), r.PathValue("name")
+	logger := s.logger.WithValues("namespace", namespace, "resource", resource, "name", name)
+
+	user, err := s.authenticate(ctx, r)
+	if err == nil {
+		err = s.authorize(ctx, user, namespace, resource, name)
+	}
+	var artifact *Artifact
+	if err == nil {
+		artifact, err = s.getArtifact(ctx, namespace, resource, name, r.URL.Query().Get("pod"), r.URL.Query().Get("file"))
+	}
+	if err == nil {
+		err = s.checkPod(ctx, namespace, artifact.PodName)
+	}
+	if err != nil {
+		code := http.StatusInternalServerError
+		var reqErr *requestError
+		if errors.As(err, &reqErr) {
+			code = reqErr.code
+		} else {
+			logger.Error(err, "Failed to process artifact request")
+		}
+		if code == http.StatusUnauthorized {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="open-liberty-operator"`)
+		}
+		http.Error(w, err.Error(), code)
+		return
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(artifact.fileName()))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": artifact.fileName()}))
+	logger.Info("Streaming artifact", "user", user.Username, "pod", artifact.PodName, "path", artifact.Path)
+	// the status code has been sent once the first bytes are written, so a failure can only end the response early
+	if err := s.stream(ctx, artifact.PodName, namespace, artifact.command(), w); err != nil {
+		logger.Error(err, "Failed to stream artifact", "pod", artifact.PodName, "path", artifact.Path)
+	}
+}
+
+// Returns the user of the bearer token of the request
+func (s *Server) authenticate(ctx context.Context, r *http.Request) (*authenticationv1.UserInfo, error) {
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !found || strings.TrimSpace(token) == "" {
+		return nil, newRequestError(http.StatusUnauthorized, "a bearer token is required")
+	}
+	review := &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: strings.TrimSpace(token)},
+	}
+	if err := s.client.Create(ctx, review); err != nil {
+		return nil, fmt.Errorf("failed to create TokenReview: %v", err)
+	}
+	if !review.Status.Authenticated {
+		return nil, newRequestError(http.StatusUnauthorized, "the bearer token is not valid")
+	}
+	return &review.Status.User, nil
+}
+
+// Checks that the user is allowed to get the artifact subresource of the instance
+func (s *Server) authorize(ctx context.Context, user *authenticationv1.UserInfo, namespace, resource, name string) error {
+	extra := map[string]authorizationv1.ExtraValue{}
+	for key, value := range user.Extra {
+		extra[key] = authorizationv1.ExtraValue(value)
+	}
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace:   namespace,
+				Verb:        "get",
+				Group:       openlibertyv1.GroupVersion.Group,
+				Resource:    resource,
+				Subresource: ArtifactSubresource,
+				Name:        name,
+			},
+			User:   user.Username,
+			UID:    user.UID,
+			Groups: user.Groups,
+			Extra:  extra,
+		},
+	}
+	if err := s.client.Create(ctx, review); err != nil {
+		return fmt.Errorf("failed to create SubjectAccessReview: %v", err)
+	}
+	if !review.Status.Allowed {
+		return newRequestError(http.StatusForbidden, "user %q cannot get resource \"%s/%s\" in API group %q in the namespace %q",
+			user.Username, resource, ArtifactSubresource, openlibertyv1.GroupVersion.Group, namespace)
+	}
+	return nil
+}
+
+// Returns the finished artifact of the instance. For dumps, pod and file optionally select one of the dump files
+// listed in the status, the most recent one being returned by default. For performance data and traces, pod optionally
+// selects one of the pods of the application.
+func (s *Server) getArtifact(ctx context.Context, namespace, resource, name, pod, file string) (*Artifact, error) {
+	key := types.NamespacedName{Name: name, Namespace: namespace}
+	var artifact *Artifact
+	switch resource {
+	case ResourceDumps:
+		instance := &openlibertyv1.OpenLibertyDump{}
+		if err := s.getInstance(ctx, key, resource, instance); err != nil {
+			return nil, err
+		}
+		for _, a := range GetDumpArtifacts(instance) {
+			if (pod == "" || a.PodName == pod) && (file == "" || a.Path == file) {
+				artifact = &a
+				break
+			}
+		}
+		if artifact == nil {
+			return nil, newRequestError(http.StatusNotFound, "no matching dump file was found in the status of %s %s", resource, name)
+		}
+	case ResourcePerformanceData:
+		instance := &openlibertyv1.OpenLibertyPerformanceData{}
+		if err := s.getInstance(ctx, key, resource, instance); err != nil {
+			return nil, err
+		}
+		for _, a := range GetPerformanceDataArtifacts(instance) {
+			if pod == "" || a.PodName == pod {
+				artifact = &a
+				break
+			}
+		}
+		if artifact == nil {
+			return nil, newRequestError(http.StatusNotFound, "the performance data of %s %s has not completed", resource, name)
+		}
+	case ResourceTraces:
+		instance := &openlibertyv1.OpenLibertyTrace{}
+		if err := s.getInstance(ctx, key, resource, instance); err != nil {
+			return nil, err
+		}
+		for _, a := range GetTraceArtifacts(instance) {
+			if pod == "" || a.PodName == pod {
+				artifact = &a
+				break
+			}
+		}
+		if artifact == nil {
+			return nil, newRequestError(http.StatusNotFound, "%s %s has no trace logs", resource, name)
+		}
+		if oc := openlibertyv1.GetOperationCondtion(instance.Status.Conditions, openlibertyv1.OperationStatusConditionTypeEnabled); oc != nil && oc.Status == corev1.ConditionTrue {
+			return nil, newRequestError(http.StatusConflict, "tracing of %s %s is still enabled, disable it to download the trace logs", resource, name)
+		}
+	default:
+		return nil, newRequestError(http.StatusNotFound, "unknown resource %q", resource)
+	}
+	// the status is only written by the operator, but make sure a download can never read outside of the serviceability folder
+	if artifact.PodName == "" || path.Clean(artifact.Path) != artifact.Path || !strings.HasPrefix(artifact.Path, serviceabilityDir+"/") {
+		return nil, fmt.Errorf("invalid artifact %q of pod %q", artifact.Path, artifact.PodName)
+	}
+	return artifact, nil
+}
+
+func (s *Server) getInstance(ctx context.Context, key types.NamespacedName, resource string, obj client.Object) error {
+	if err := s.reader.Get(ctx, key, obj); err != nil {
+		if kerrors.IsNotFound(err) {
+			return newRequestError(http.StatusNotFound, "%s %s not found", resource, key.Name)
+		}
+		return err
+	}
+	return nil
+}
+
+// Checks that the pod holding the artifact is running, so that the artifact can be read from it
+func (s *Server) checkPod(ctx context.Context, namespace, podName string) error {
+	pod := &corev1.Pod{}
+	if err := s.reader.Get(ctx, types.NamespacedName{Name: podName, Namespace: namespace}, pod); err != nil {
+		if kerrors.IsNotFound(err) {
+			return newRequestError(http.StatusNotFound, "pod %s not found", podName)
+		}
+		return err
+	}
+	if pod.Status.Phase != corev1.PodRunning {
+		return newRequestError(http.StatusConflict, "pod %s is not running", podName)
+	}
+	return nil
+}
+
+// GetDumpArtifacts returns the dump files listed in the status of the instance, most recent first
+func GetDumpArtifacts(instance *openlibertyv1.OpenLibertyDump) []Artifact {
+	artifacts := []Artifact{}
+	if instance.Status.DumpFile != "" {
+		artifacts = append(artifacts, Artifact{PodName: instance.Status.PodName, Path: instance.Status.DumpFile})
+	}
+	for _, podStatus := range instance.Status.Pods {
+		if podStatus.DumpFile != "" {
+			artifacts = append(artifacts, Artifact{PodName: podStatus.PodName, Path: podStatus.DumpFile})
+		}
+	}
+	for _, entry := range instance.Status.History {
+		if entry.DumpFile != "" {
+			artifacts = append(artifacts, Artifact{PodName: entry.PodName, Path: entry.DumpFile})
+		}
+		for _, podStatus := range entry.Pods {
+			if podStatus.DumpFile != "" {
+				artifacts = append(artifacts, Artifact{PodName: podStatus.PodName, Path: podStatus.DumpFile})
+			}
+		}
+	}
+	return artifacts
+}
+
+// GetPerformanceDataArtifacts returns the performance data files listed in the status of the instance
+func GetPerformanceDataArtifacts(instance *openlibertyv1.OpenLibertyPerformanceData) []Artifact {
+	artifacts := []Artifact{}
+	if instance.Status.PerformanceDataFile != "" {
+		artifacts = append(artifacts, Artifact{PodName: instance.Spec.PodName, Path: instance.Status.PerformanceDataFile})
+	}
+	for _, podStatus := range instance.Status.Pods {
+		if podStatus.PerformanceDataFile != "" {
+			artifacts = append(artifacts, Artifact{PodName: podStatus.PodName, Path: podStatus.PerformanceDataFile})
+		}
+	}
+	return artifacts
+}
+
+// GetTraceArtifacts returns the trace log directories listed in the status of the instance
+func GetTraceArtifacts(instance *openlibertyv1.OpenLibertyTrace) []Artifact {
+	artifacts := []Artifact{}
+	if instance.Status.LogDirectory != "" {
+		artifacts = append(artifacts, Artifact{PodName: instance.Status.OperatedResource.ResourceName, Path: instance.Status.LogDirectory, Archive: true})
+	}
+	for _, podStatus := range instance.Status.OperatedResources {
+		if podStatus.LogDirectory != "" {
+			artifacts = append(artifacts, Artifact{PodName: podStatus.ResourceName, Path: podStatus.LogDirectory, Archive: true})
+		}
+	}
+	return artifacts
+}
@@ -0,0 +1,319 @@
+package artifacts
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	openlibertyv1 "github.com/OpenLiberty/open-liberty-operator/api/v1"
+	"github.com/go-logr/logr"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+type Test struct {
+	test     string
+	expected interface{}
+	actual   interface{}
+}
+
+func verifyTests(tests []Test) error {
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.actual, tt.expected) {
+			return fmt.Errorf("%s test expected: (%v) actual: (%v)", tt.test, tt.expected, tt.actual)
+		}
+	}
+	return nil
+}
+
+const namespace = "ns"
+
+// Returns a server whose token reviews accept the token "valid" for user "dev", which may only get the artifacts of
+// the instances named "allowed". Commands are echoed instead of being run in the pods.
+func newTestServer(objs ...client.Object) *Server {
+	scheme := runtime.NewScheme()
+	clientgoscheme.AddToScheme(scheme)
+	openlibertyv1.AddToScheme(scheme)
+	c := fakeclient.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			switch review := obj.(type) {
+			case *authenticationv1.TokenReview:
+				if review.Spec.Token == "valid" {
+					review.Status.Authenticated = true
+					review.Status.User = authenticationv1.UserInfo{Username: "dev", Groups: []string{"developers"}}
+				}
+			case *authorizationv1.SubjectAccessReview:
+				attrs := review.Spec.ResourceAttributes
+				review.Status.Allowed = review.Spec.User == "dev" && attrs.Verb == "get" && attrs.Group == "apps.openliberty.io" &&
+					attrs.Subresource == ArtifactSubresource && attrs.Namespace == namespace && attrs.Name == "allowed"
+			default:
+				return c.Create(ctx, obj, opts...)
+			}
+			return nil
+		},
+	}).Build()
+	return &Server{
+		client: c,
+		reader: c,
+		stream: func(ctx context.Context, podName, podNamespace string, command []string, w io.Writer) error {
+			_, err := fmt.Fprintf(w, "%s/%s: %s", podNamespace, podName, strings.Join(command, " "))
+			return err
+		},
+		logger: logr.Discard(),
+	}
+}
+
+func runningPod(name string) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func get(server *Server, target, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServeArtifactAccess(t *testing.T) {
+	dump := &openlibertyv1.OpenLibertyDump{
+		ObjectMeta: metav1.ObjectMeta{Name: "allowed", Namespace: namespace},
+		Spec:       openlibertyv1.OpenLibertyDumpSpec{PodName: "app-0"},
+		Status:     openlibertyv1.OpenLibertyDumpStatus{DumpFile: "/serviceability/ns/app-0/2024-01-01_00:00:00.zip", PodName: "app-0"},
+	}
+	denied := dump.DeepCopy()
+	denied.Name = "denied"
+	server := newTestServer(dump, denied, runningPod("app-0"))
+
+	ok := get(server, "/artifacts/namespaces/ns/openlibertydumps/allowed", "valid")
+	noToken := get(server, "/artifacts/namespaces/ns/openlibertydumps/allowed", "")
+	invalidToken := get(server, "/artifacts/namespaces/ns/openlibertydumps/allowed", "invalid")
+	forbidden := get(server, "/artifacts/namespaces/ns/openlibertydumps/denied", "valid")
+	otherNamespace := get(server, "/artifacts/namespaces/other/openlibertydumps/allowed", "valid")
+	missing := get(server, "/artifacts/namespaces/ns/openlibertytraces/missing", "valid")
+
+	tests := []Test{
+		{"authorized status", http.StatusOK, ok.Code},
+		{"authorized body", "ns/app-0: cat /serviceability/ns/app-0/2024-01-01_00:00:00.zip", ok.Body.String()},
+		{"authorized content type", "application/zip", ok.Header().Get("Content-Type")},
+		{"authorized content disposition", `attachment; filename="2024-01-01_00:00:00.zip"`, ok.Header().Get("Content-Disposition")},
+		{"missing token", http.StatusUnauthorized, noToken.Code},
+		{"missing token challenge", `Bearer realm="open-liberty-operator"`, noToken.Header().Get("WWW-Authenticate")},
+		{"invalid token", http.StatusUnauthorized, invalidToken.Code},
+		{"forbidden instance", http.StatusForbidden, forbidden.Code},
+		{"forbidden namespace", http.StatusForbidden, otherNamespace.Code},
+		// authorization is checked before the instance is read so that its existence is not disclosed
+		{"forbidden missing instance", http.StatusForbidden, missing.Code},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestServeArtifactResources(t *testing.T) {
+	perfData := &openlibertyv1.OpenLibertyPerformanceData{
+		ObjectMeta: metav1.ObjectMeta{Name: "allowed", Namespace: namespace},
+		Spec:       openlibertyv1.OpenLibertyPerformanceDataSpec{PodName: "app-0"},
+		Status:     openlibertyv1.OpenLibertyPerformanceDataStatus{PerformanceDataFile: "/serviceability/ns/app-0/performanceData/linperf_RESULTS.tar.gz"},
+	}
+	trace := &openlibertyv1.OpenLibertyTrace{
+		ObjectMeta: metav1.ObjectMeta{Name: "allowed", Namespace: namespace},
+		Status: openlibertyv1.OpenLibertyTraceStatus{
+			OperatedResource: openlibertyv1.OperatedResource{ResourceName: "app-1"},
+			LogDirectory:     "/serviceability/ns/app-1/logs",
+			Conditions:       []openlibertyv1.OperationStatusCondition{{Type: openlibertyv1.OperationStatusConditionTypeEnabled, Status: corev1.ConditionFalse}},
+		},
+	}
+	server := newTestServer(perfData, trace, runningPod("app-0"), runningPod("app-1"))
+	perfDataResp := get(server, "/artifacts/namespaces/ns/openlibertyperformancedata/allowed", "valid")
+	traceResp := get(server, "/artifacts/namespaces/ns/openlibertytraces/allowed", "valid")
+
+	trace.Status.Conditions[0].Status = corev1.ConditionTrue
+	trace.ResourceVersion = ""
+	tracing := get(newTestServer(trace, runningPod("app-1")), "/artifacts/namespaces/ns/openlibertytraces/allowed", "valid")
+
+	stoppedPod := runningPod("app-0")
+	stoppedPod.Status.Phase = corev1.PodFailed
+	perfData.ResourceVersion = ""
+	stopped := get(newTestServer(perfData, stoppedPod), "/artifacts/namespaces/ns/openlibertyperformancedata/allowed", "valid")
+	deleted := get(newTestServer(perfData), "/artifacts/namespaces/ns/openlibertyperformancedata/allowed", "valid")
+
+	tests := []Test{
+		{"performance data status", http.StatusOK, perfDataResp.Code},
+		{"performance data body", "ns/app-0: cat /serviceability/ns/app-0/performanceData/linperf_RESULTS.tar.gz", perfDataResp.Body.String()},
+		{"trace status", http.StatusOK, traceResp.Code},
+		{"trace body", "ns/app-1: tar -czf - -C /serviceability/ns/app-1/logs .", traceResp.Body.String()},
+		{"trace content disposition", "attachment; filename=app-1_logs.tar.gz", traceResp.Header().Get("Content-Disposition")},
+		{"trace still enabled", http.StatusConflict, tracing.Code},
+		{"pod not running", http.StatusConflict, stopped.Code},
+		{"pod deleted", http.StatusNotFound, deleted.Code},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestServeDumpArtifacts(t *testing.T) {
+	dump := &openlibertyv1.OpenLibertyDump{
+		ObjectMeta: metav1.ObjectMeta{Name: "allowed", Namespace: namespace},
+		Spec:       openlibertyv1.OpenLibertyDumpSpec{ApplicationRef: &corev1.LocalObjectReference{Name: "app"}},
+		Status: openlibertyv1.OpenLibertyDumpStatus{
+			Pods: []openlibertyv1.DumpPodStatus{
+				{PodName: "app-0", DumpFile: "/serviceability/ns/app-0/2.zip"},
+				{PodName: "app-1"},
+			},
+			History: []openlibertyv1.DumpHistoryEntry{
+				{Pods: []openlibertyv1.DumpPodStatus{
+					{PodName: "app-0", DumpFile: "/serviceability/ns/app-0/1.zip"},
+					{PodName: "app-1", DumpFile: "/serviceability/ns/app-1/1.zip"},
+				}},
+			},
+		},
+	}
+	server := newTestServer(dump, runningPod("app-0"), runningPod("app-1"))
+
+	tests := []Test{
+		{"dump artifacts", []Artifact{
+			{PodName: "app-0", Path: "/serviceability/ns/app-0/2.zip"},
+			{PodName: "app-0", Path: "/serviceability/ns/app-0/1.zip"},
+			{PodName: "app-1", Path: "/serviceability/ns/app-1/1.zip"},
+		}, GetDumpArtifacts(dump)},
+		{"most recent dump", "ns/app-0: cat /serviceability/ns/app-0/2.zip",
+			get(server, "/artifacts/namespaces/ns/openlibertydumps/allowed", "valid").Body.String()},
+		{"most recent dump of pod", "ns/app-1: cat /serviceability/ns/app-1/1.zip",
+			get(server, "/artifacts/namespaces/ns/openlibertydumps/allowed?pod=app-1", "valid").Body.String()},
+		{"dump file", "ns/app-0: cat /serviceability/ns/app-0/1.zip",
+			get(server, "/artifacts/namespaces/ns/openlibertydumps/allowed?file=/serviceability/ns/app-0/1.zip", "valid").Body.String()},
+		{"unknown dump file", http.StatusNotFound,
+			get(server, "/artifacts/namespaces/ns/openlibertydumps/allowed?file=/serviceability/ns/app-1/2.zip", "valid").Code},
+		{"unknown resource", http.StatusNotFound,
+			get(server, "/artifacts/namespaces/ns/openlibertyapplications/allowed", "valid").Code},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestServeEditedPodDumpArtifacts(t *testing.T) {
+	dump := &openlibertyv1.OpenLibertyDump{
+		ObjectMeta: metav1.ObjectMeta{Name: "allowed", Namespace: namespace},
+		Spec:       openlibertyv1.OpenLibertyDumpSpec{PodName: "app-1", Schedule: "@hourly"},
+		Status: openlibertyv1.OpenLibertyDumpStatus{
+			DumpFile: "/serviceability/ns/app-0/2.zip",
+			PodName:  "app-0",
+			History: []openlibertyv1.DumpHistoryEntry{
+				{DumpFile: "/serviceability/ns/app-0/2.zip", PodName: "app-0"},
+				{DumpFile: "/serviceability/ns/app-2/1.zip", PodName: "app-2"},
+			},
+		},
+	}
+	server := newTestServer(dump, runningPod("app-0"), runningPod("app-1"), runningPod("app-2"))
+
+	tests := []Test{
+		{"dump artifacts", []Artifact{
+			{PodName: "app-0", Path: "/serviceability/ns/app-0/2.zip"},
+			{PodName: "app-0", Path: "/serviceability/ns/app-0/2.zip"},
+			{PodName: "app-2", Path: "/serviceability/ns/app-2/1.zip"},
+		}, GetDumpArtifacts(dump)},
+		{"most recent dump", "ns/app-0: cat /serviceability/ns/app-0/2.zip",
+			get(server, "/artifacts/namespaces/ns/openlibertydumps/allowed", "valid").Body.String()},
+		{"dump of previous pod", "ns/app-2: cat /serviceability/ns/app-2/1.zip",
+			get(server, "/artifacts/namespaces/ns/openlibertydumps/allowed?file=/serviceability/ns/app-2/1.zip", "valid").Body.String()},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestServeApplicationPerformanceDataArtifacts(t *testing.T) {
+	perfData := &openlibertyv1.OpenLibertyPerformanceData{
+		ObjectMeta: metav1.ObjectMeta{Name: "allowed", Namespace: namespace},
+		Spec:       openlibertyv1.OpenLibertyPerformanceDataSpec{ApplicationRef: &corev1.LocalObjectReference{Name: "app"}},
+		Status: openlibertyv1.OpenLibertyPerformanceDataStatus{
+			Pods: []openlibertyv1.PerformanceDataPodStatus{
+				{PodName: "app-0", PerformanceDataFile: "/serviceability/ns/app-0/performanceData/linperf_RESULTS.tar.gz"},
+				{PodName: "app-1"},
+				{PodName: "app-2", PerformanceDataFile: "/serviceability/ns/app-2/performanceData/linperf_RESULTS.tar.gz"},
+			},
+		},
+	}
+	server := newTestServer(perfData, runningPod("app-0"), runningPod("app-2"))
+
+	tests := []Test{
+		{"performance data artifacts", []Artifact{
+			{PodName: "app-0", Path: "/serviceability/ns/app-0/performanceData/linperf_RESULTS.tar.gz"},
+			{PodName: "app-2", Path: "/serviceability/ns/app-2/performanceData/linperf_RESULTS.tar.gz"},
+		}, GetPerformanceDataArtifacts(perfData)},
+		{"first pod", "ns/app-0: cat /serviceability/ns/app-0/performanceData/linperf_RESULTS.tar.gz",
+			get(server, "/artifacts/namespaces/ns/openlibertyperformancedata/allowed", "valid").Body.String()},
+		{"pod", "ns/app-2: cat /serviceability/ns/app-2/performanceData/linperf_RESULTS.tar.gz",
+			get(server, "/artifacts/namespaces/ns/openlibertyperformancedata/allowed?pod=app-2", "valid").Body.String()},
+		{"pod without performance data", http.StatusNotFound,
+			get(server, "/artifacts/namespaces/ns/openlibertyperformancedata/allowed?pod=app-1", "valid").Code},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestServeApplicationTraceArtifacts(t *testing.T) {
+	trace := &openlibertyv1.OpenLibertyTrace{
+		ObjectMeta: metav1.ObjectMeta{Name: "allowed", Namespace: namespace},
+		Spec:       openlibertyv1.OpenLibertyTraceSpec{ApplicationRef: &corev1.LocalObjectReference{Name: "app"}},
+		Status: openlibertyv1.OpenLibertyTraceStatus{
+			OperatedResources: []openlibertyv1.OperatedResource{
+				{ResourceName: "app-0", ResourceType: "pod", LogDirectory: "/serviceability/ns/app-0/logs"},
+				{ResourceName: "app-1", ResourceType: "pod"},
+				{ResourceName: "app-2", ResourceType: "pod", LogDirectory: "/serviceability/ns/app-2/logs"},
+			},
+			Conditions: []openlibertyv1.OperationStatusCondition{{Type: openlibertyv1.OperationStatusConditionTypeEnabled, Status: corev1.ConditionFalse}},
+		},
+	}
+	server := newTestServer(trace, runningPod("app-0"), runningPod("app-2"))
+
+	tests := []Test{
+		{"trace artifacts", []Artifact{
+			{PodName: "app-0", Path: "/serviceability/ns/app-0/logs", Archive: true},
+			{PodName: "app-2", Path: "/serviceability/ns/app-2/logs", Archive: true},
+		}, GetTraceArtifacts(trace)},
+		{"first traced pod", "ns/app-0: tar -czf - -C /serviceability/ns/app-0/logs .",
+			get(server, "/artifacts/namespaces/ns/openlibertytraces/allowed", "valid").Body.String()},
+		{"traced pod", "ns/app-2: tar -czf - -C /serviceability/ns/app-2/logs .",
+			get(server, "/artifacts/namespaces/ns/openlibertytraces/allowed?pod=app-2", "valid").Body.String()},
+		{"pod not traced", http.StatusNotFound,
+			get(server, "/artifacts/namespaces/ns/openlibertytraces/allowed?pod=app-1", "valid").Code},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestGetArtifactPath(t *testing.T) {
+	dump := &openlibertyv1.OpenLibertyDump{
+		ObjectMeta: metav1.ObjectMeta{Name: "allowed", Namespace: namespace},
+		Spec:       openlibertyv1.OpenLibertyDumpSpec{PodName: "app-0"},
+		Status:     openlibertyv1.OpenLibertyDumpStatus{DumpFile: "/serviceability/../etc/passwd", PodName: "app-0"},
+	}
+	server := newTestServer(dump, runningPod("app-0"))
+	_, err := server.getArtifact(context.Background(), namespace, ResourceDumps, "allowed", "", "")
+	if err == nil {
+		t.Fatalf("expected an error for a dump file outside of the serviceability folder")
+	}
+}
@@ -0,0 +1,192 @@
+package collector
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	olv1 "github.com/OpenLiberty/open-liberty-operator/api/v1"
+	"github.com/OpenLiberty/open-liberty-operator/utils"
+)
+
+// DefaultCollector is the collector used when .spec.collector of an OpenLibertyPerformanceData is not set
+const DefaultCollector = "linperf"
+
+// Collector gathers performance data from the Liberty container of a pod. A collector is selected by the
+// .spec.collector field of an OpenLibertyPerformanceData and run by the pod injector.
+type Collector interface {
+	// Name returns the value of .spec.collector that selects the collector
+	Name() string
+	// AssetsFolder returns the folder, relative to the operator working directory, that is copied to $WLP_OUTPUT_DIR/helper
+	// in the container before the collector runs, or "" if the collector has no assets
+	AssetsFolder() string
+	// RequiredCommands returns the commands that must be installed in the container
+	RequiredCommands() []string
+	// MissingCommandsMessage returns the message reported when a required command is not installed in the container
+	MissingCommandsMessage() string
+	// Attrs returns the attributes of the instance that are passed to DirName and Command
+	Attrs(instance *olv1.OpenLibertyPerformanceData) map[string]string
+	// DirName returns the name of the folder in the output folder that a run started at now writes its data to
+	DirName(attrs map[string]string, now time.Time) string
+	// Command returns the shell command that writes the data to the dirName folder of outputDir. The command is run by
+	// /bin/sh -c in single quotes, so it must not contain single quotes.
+	Command(attrs map[string]string, outputDir, dirName string) string
+	// OutputFile returns the path of the data file from the output of the command
+	OutputFile(stdout, outputDir, dirName string) string
+}
+
+// Summarizer is implemented by the collectors whose data file can be parsed into a summary for a first-pass triage
+type Summarizer interface {
+	// Summarize parses the data file read from r
+	Summarize(r io.Reader) (*olv1.PerformanceDataSummary, error)
+}
+
+var (
+	registryMutex = &sync.RWMutex{}
+	registry      = map[string]Collector{}
+)
+
+// Register makes the collector available to OpenLibertyPerformanceData instances. It panics if a collector with the
+// same name is already registered.
+func Register(c Collector) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+	if _, found := registry[c.Name()]; found {
+		panic(fmt.Sprintf("collector %s is already registered", c.Name()))
+	}
+	registry[c.Name()] = c
+}
+
+// Get returns the collector with the name, or the default collector if name is ""
+func Get(name string) (Collector, error) {
+	if name == "" {
+		name = DefaultCollector
+	}
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	if c, found := registry[name]; found {
+		return c, nil
+	}
+	return nil, fmt.Errorf("unknown performance data collector %q, valid collectors are %s", name, strings.Join(names(), ", "))
+}
+
+// Names returns the names of the registered collectors, sorted
+func Names() []string {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+	return names()
+}
+
+func names() []string {
+	list := []string{}
+	for name := range registry {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// EncodeAttrs encodes the attributes of the collector for the pod injector, along with the UID and name of the instance
+// that identify the run
+func EncodeAttrs(c Collector, instance *olv1.OpenLibertyPerformanceData) string {
+	attrs := c.Attrs(instance)
+	attrs["uid"] = string(instance.GetUID())
+	attrs["name"] = instance.GetName()
+	keys := []string{}
+	for key := range attrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	encodedAttrs := []string{}
+	for _, key := range keys {
+		encodedAttrs = append(encodedAttrs, fmt.Sprintf("%s/%s", key, attrs[key]))
+	}
+	return strings.Join(encodedAttrs, "|")
+}
+
+// DecodeAttrs decodes the attributes encoded by EncodeAttrs
+func DecodeAttrs(encodedAttrs string) map[string]string {
+	return utils.DecodeLinperfAttr(encodedAttrs)
+}
+
+// Returns the start time of a run as used in folder names, such as 20240110.93005
+func getTimestamp(now time.Time) string {
+	startDate := fmt.Sprintf("%d%d%d", now.Year(), now.Month(), now.Day())
+	startTime := fmt.Sprintf("%d%d%d", now.Hour(), now.Minute(), now.Second())
+	return startDate + "." + startTime
+}
+
+const serviceabilityRootDir = "/serviceability"
+
+// Exit codes of the command returned by GetRunCmd
+const (
+	exitCodeNoServiceability = 129
+	exitCodeMissingCommands  = 130
+	exitCodeStopped          = 131
+)
+
+// GetOutputDir returns the folder of the serviceability storage that collectors write the data of the pod to
+func GetOutputDir(podName, podNamespace string) string {
+	return fmt.Sprintf("%s/%s/%s/performanceData/", serviceabilityRootDir, podNamespace, podName)
+}
+
+// Returns the paths of the log, exit code and process ID files of a run. The files are kept in the serviceability
+// folder so that the operator can reattach to the run after it restarts.
+func getRunFiles(podName, podNamespace, dirName string) (string, string, string) {
+	runDir := GetOutputDir(podName, podNamespace) + ".collector/" + dirName
+	return runDir + ".log", runDir + ".exit", runDir + ".pid"
+}
+
+// GetRunCmd returns the command that runs the collector in the background, writing its data to the dirName folder, and
+// waits for it to complete
+func GetRunCmd(c Collector, encodedAttrs, podName, podNamespace, dirName string) string {
+	cmdArgs := []string{}
+	for _, cli := range c.RequiredCommands() {
+		cmdArgs = append(cmdArgs, fmt.Sprintf("command -v %s >/dev/null 2>&1", cli))
+	}
+	checkCLICmd := "true"
+	if len(cmdArgs) > 0 {
+		checkCLICmd = strings.Join(cmdArgs, " && ") // add spaces for readability
+	}
+
+	outputDir := GetOutputDir(podName, podNamespace)
+	collectorCmd := c.Command(DecodeAttrs(encodedAttrs), outputDir, dirName)
+
+	// the collector is detached from the exec session, so that it keeps running if the operator restarts
+	logFile, exitFile, pidFile := getRunFiles(podName, podNamespace, dirName)
+	runCmd := fmt.Sprintf("mkdir -p %s %s && rm -f %s && (nohup /bin/sh -c '%s > %s 2>&1; echo $? > %s' > /dev/null 2>&1 & echo $! > %s)",
+		outputDir, path.Dir(logFile), exitFile, collectorCmd, logFile, exitFile, pidFile)
+	return fmt.Sprintf("if ! (%s); then exit %d; elif [ $(df | grep %s -c) -eq 0 ]; then exit %d; else %s && %s; fi",
+		checkCLICmd, exitCodeMissingCommands, serviceabilityRootDir, exitCodeNoServiceability, runCmd, GetWaitCmd(podName, podNamespace, dirName))
+}
+
+// GetWaitCmd returns the command that waits for a run to complete, printing its output and exiting with its exit code.
+// The command exits with code 131 if the collector stopped without completing, such as when the container restarted.
+func GetWaitCmd(podName, podNamespace, dirName string) string {
+	logFile, exitFile, pidFile := getRunFiles(podName, podNamespace, dirName)
+	return fmt.Sprintf("while [ ! -f %s ]; do if ! kill -0 $(cat %s 2>/dev/null) 2>/dev/null; then [ -f %s ] && break; exit %d; fi; sleep 5; done; cat %s; exit $(cat %s)",
+		exitFile, pidFile, exitFile, exitCodeStopped, logFile, exitFile)
+}
+
+// GetCleanupCmd returns the command that removes the log, exit code and process ID files of a run
+func GetCleanupCmd(podName, podNamespace, dirName string) []string {
+	logFile, exitFile, pidFile := getRunFiles(podName, podNamespace, dirName)
+	return []string{"rm", "-f", logFile, exitFile, pidFile}
+}
+
+// GetError returns the error reported to the user when the command returned by GetRunCmd or GetWaitCmd failed
+func GetError(c Collector, err error) error {
+	switch {
+	case strings.HasSuffix(err.Error(), fmt.Sprintf("exit code %d", exitCodeNoServiceability)):
+		return fmt.Errorf("The Liberty custom resource which created this pod must enable .spec.serviceability in order to gather performance data")
+	case strings.HasSuffix(err.Error(), fmt.Sprintf("exit code %d", exitCodeMissingCommands)):
+		return fmt.Errorf("%s", c.MissingCommandsMessage())
+	case strings.HasSuffix(err.Error(), fmt.Sprintf("exit code %d", exitCodeStopped)):
+		return fmt.Errorf("The performance data collector stopped before it completed, which can happen when the Liberty container restarts")
+	}
+	return fmt.Errorf("Failed to create secondary StreamWithContext: %v", err)
+}
@@ -0,0 +1,114 @@
+package collector
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	olv1 "github.com/OpenLiberty/open-liberty-operator/api/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type Test struct {
+	test     string
+	expected interface{}
+	actual   interface{}
+}
+
+func verifyTests(tests []Test) error {
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.actual, tt.expected) {
+			return fmt.Errorf("%s test expected: (%v) actual: (%v)", tt.test, tt.expected, tt.actual)
+		}
+	}
+	return nil
+}
+
+func getName(name string) string {
+	c, err := Get(name)
+	if err != nil {
+		return err.Error()
+	}
+	return c.Name()
+}
+
+func TestRegistry(t *testing.T) {
+	linperf, _ := Get("linperf")
+	registerTwice := func() (panicked bool) {
+		defer func() {
+			panicked = recover() != nil
+		}()
+		Register(linperf)
+		return false
+	}
+
+	tests := []Test{
+		{"names", []string{"jfr", "linperf", "netstat-snapshot"}, Names()},
+		{"default collector", "linperf", getName("")},
+		{"netstat-snapshot", "netstat-snapshot", getName("netstat-snapshot")},
+		{"unknown collector", `unknown performance data collector "perf", valid collectors are jfr, linperf, netstat-snapshot`, getName("perf")},
+		{"register twice", true, registerTwice()},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestEncodeAttrs(t *testing.T) {
+	timespan := 60
+	instance := &olv1.OpenLibertyPerformanceData{
+		ObjectMeta: metav1.ObjectMeta{Name: "perf", UID: "1234"},
+		Spec:       olv1.OpenLibertyPerformanceDataSpec{Timespan: &timespan},
+	}
+	linperf, _ := Get("linperf")
+	encodedAttrs := EncodeAttrs(linperf, instance)
+
+	tests := []Test{
+		{"encoded attributes", "interval/30|name/perf|timespan/60|uid/1234", encodedAttrs},
+		{"decoded attributes", map[string]string{"interval": "30", "name": "perf", "timespan": "60", "uid": "1234"}, DecodeAttrs(encodedAttrs)},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestCommands(t *testing.T) {
+	now := time.Date(2024, time.January, 10, 9, 30, 5, 0, time.UTC)
+	attrs := map[string]string{"interval": "30", "name": "perf", "timespan": "60", "uid": "1234"}
+	outputDir := GetOutputDir("app-0", "ns")
+	linperf, _ := Get("linperf")
+	netstat, _ := Get("netstat-snapshot")
+	jfr, _ := Get("jfr")
+	jfrCmd := jfr.Command(attrs, outputDir, jfr.DirName(attrs, now))
+	runCmd := GetRunCmd(netstat, "interval/30|name/perf|timespan/60|uid/1234", "app-0", "ns", netstat.DirName(attrs, now))
+
+	tests := []Test{
+		{"output folder", "/serviceability/ns/app-0/performanceData/", outputDir},
+		{"linperf folder", "linperf_RESULTS_perf.2024110.9305", linperf.DirName(attrs, now)},
+		{"linperf command", `$WLP_OUTPUT_DIR/helper/linperf.sh --output-dir=/serviceability/ns/app-0/performanceData/ --dir-name=linperf_RESULTS_perf.2024110.9305 -s 60 -j 30 --ignore-root --clean-up-javacores "1"`,
+			linperf.Command(attrs, outputDir, linperf.DirName(attrs, now))},
+		{"netstat folder", "netstat_RESULTS_perf.2024110.9305", netstat.DirName(attrs, now)},
+		{"netstat output file", "/serviceability/ns/app-0/performanceData/netstat_RESULTS_perf.2024110.9305.tar.gz", netstat.OutputFile("", outputDir, netstat.DirName(attrs, now))},
+		{"netstat command without single quotes", false, strings.Contains(netstat.Command(attrs, outputDir, netstat.DirName(attrs, now)), "'")},
+		{"jfr folder", "jfr_RESULTS_perf.2024110.9305", jfr.DirName(attrs, now)},
+		{"jfr attributes", map[string]string{"timespan": "240"}, jfr.Attrs(&olv1.OpenLibertyPerformanceData{})},
+		{"jfr output file", "/serviceability/ns/app-0/performanceData/jfr_RESULTS_perf.2024110.9305.jfr", jfr.OutputFile("", outputDir, jfr.DirName(attrs, now))},
+		{"jfr command without single quotes", false, strings.Contains(jfrCmd, "'")},
+		{"jfr recording", true, strings.Contains(jfrCmd, "$JCMD $PID JFR.start name=jfr_RESULTS_perf.2024110.9305 duration=60s filename=/serviceability/ns/app-0/performanceData/jfr_RESULTS_perf.2024110.9305.jfr || exit 130;")},
+		{"run command checks the required commands", true, strings.HasPrefix(runCmd, "if ! (command -v netstat >/dev/null 2>&1 && command -v tar >/dev/null 2>&1); then exit 130;")},
+		{"run command waits", true, strings.HasSuffix(runCmd, GetWaitCmd("app-0", "ns", "netstat_RESULTS_perf.2024110.9305")+"; fi")},
+		{"cleanup command", []string{"rm", "-f",
+			"/serviceability/ns/app-0/performanceData/.collector/netstat_RESULTS_perf.2024110.9305.log",
+			"/serviceability/ns/app-0/performanceData/.collector/netstat_RESULTS_perf.2024110.9305.exit",
+			"/serviceability/ns/app-0/performanceData/.collector/netstat_RESULTS_perf.2024110.9305.pid",
+		}, GetCleanupCmd("app-0", "ns", "netstat_RESULTS_perf.2024110.9305")},
+		{"missing commands error", netstat.MissingCommandsMessage(), GetError(netstat, fmt.Errorf("command terminated with non-zero exit code: error executing command [/bin/sh -c], exit code 130")).Error()},
+		{"stopped error", "The performance data collector stopped before it completed, which can happen when the Liberty container restarts",
+			GetError(netstat, fmt.Errorf("command terminated with non-zero exit code: exit code 131")).Error()},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
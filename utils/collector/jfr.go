@@ -0,0 +1,62 @@
+package collector
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	olv1 "github.com/OpenLiberty/open-liberty-operator/api/v1"
+)
+
+// The maximum time, in seconds, that the collector waits for the JVM to write the recording once the timespan has elapsed
+const jfrWriteTimeout = 60
+
+// Records the Liberty JVM with Java Flight Recorder for the timespan, writing the recording to the serviceability folder
+type jfrCollector struct{}
+
+func init() {
+	Register(&jfrCollector{})
+}
+
+func (c *jfrCollector) Name() string {
+	return "jfr"
+}
+
+func (c *jfrCollector) AssetsFolder() string {
+	return ""
+}
+
+func (c *jfrCollector) RequiredCommands() []string {
+	return []string{"grep", "readlink"}
+}
+
+func (c *jfrCollector) MissingCommandsMessage() string {
+	return "Failed to start a Java Flight Recorder recording in the Liberty JVM; The Liberty container must include the jcmd command of a Java runtime that supports Java Flight Recorder, either on the PATH or in $JAVA_HOME/bin. For Eclipse OpenJ9, use a release that supports Java Flight Recorder and enable it with the -XX:+FlightRecorder JVM option"
+}
+
+func (c *jfrCollector) Attrs(instance *olv1.OpenLibertyPerformanceData) map[string]string {
+	return map[string]string{
+		"timespan": strconv.Itoa(instance.GetTimespan()),
+	}
+}
+
+func (c *jfrCollector) DirName(attrs map[string]string, now time.Time) string {
+	return fmt.Sprintf("jfr_RESULTS_%s.%s", attrs["name"], getTimestamp(now))
+}
+
+// The recording is started with a duration, after which the JVM writes it to the recording file. If the JVM has not
+// written the file once the timespan has elapsed, the recording is stopped, which also writes it.
+func (c *jfrCollector) Command(attrs map[string]string, outputDir, dirName string) string {
+	recordingFile := c.OutputFile("", outputDir, dirName)
+	findJVM := "PID=\"\"; for p in /proc/[0-9]*; do case \"$(readlink $p/exe 2>/dev/null)\" in */java) if grep -q ws-server.jar $p/cmdline 2>/dev/null; then PID=${p#/proc/}; break; fi;; esac; done"
+	findJcmd := "JCMD=$(command -v jcmd || echo $JAVA_HOME/bin/jcmd)"
+	return fmt.Sprintf("%[1]s; %[2]s; if [ -z \"$PID\" ] || [ ! -x \"$JCMD\" ]; then echo \"Could not find the Liberty JVM or the jcmd command\"; exit %[3]d; fi; "+
+		"$JCMD $PID JFR.start name=%[4]s duration=%[5]ss filename=%[6]s || exit %[3]d; "+
+		"sleep %[5]s; i=0; while [ ! -s %[6]s ] && [ $i -lt %[7]d ]; do sleep 1; i=$((i+1)); done; "+
+		"[ -s %[6]s ] || $JCMD $PID JFR.stop name=%[4]s filename=%[6]s; [ -s %[6]s ] && echo \"Recording written to %[6]s\"",
+		findJVM, findJcmd, exitCodeMissingCommands, dirName, attrs["timespan"], recordingFile, jfrWriteTimeout)
+}
+
+func (c *jfrCollector) OutputFile(stdout, outputDir, dirName string) string {
+	return outputDir + dirName + ".jfr"
+}
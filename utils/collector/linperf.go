@@ -0,0 +1,94 @@
+package collector
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	olv1 "github.com/OpenLiberty/open-liberty-operator/api/v1"
+)
+
+// Collects OS-level and javacore data with the linperf.sh script
+type linperfCollector struct{}
+
+func init() {
+	Register(&linperfCollector{})
+}
+
+func (c *linperfCollector) Name() string {
+	return "linperf"
+}
+
+func (c *linperfCollector) AssetsFolder() string {
+	return "internal/controller/assets/helper"
+}
+
+func (c *linperfCollector) RequiredCommands() []string {
+	return []string{"netstat", "ps", "dmesg", "tput", "ifconfig", "vmstat", "top", "uptime", "hostname"}
+}
+
+func (c *linperfCollector) MissingCommandsMessage() string {
+	return "The Liberty container is missing packages required for collecting performance data; To install the packages, include the command 'RUN command -v yum && pkgcmd=yum || pkgcmd=microdnf && ($pkgcmd update -y && $pkgcmd install -y procps-ng net-tools ncurses hostname)' in the Liberty container image definition"
+}
+
+func (c *linperfCollector) Attrs(instance *olv1.OpenLibertyPerformanceData) map[string]string {
+	return map[string]string{
+		"timespan": strconv.Itoa(instance.GetTimespan()),
+		"interval": strconv.Itoa(instance.GetInterval()),
+	}
+}
+
+func (c *linperfCollector) DirName(attrs map[string]string, now time.Time) string {
+	return fmt.Sprintf("linperf_RESULTS_%s.%s", attrs["name"], getTimestamp(now))
+}
+
+func (c *linperfCollector) Command(attrs map[string]string, outputDir, dirName string) string {
+	linperfCmdArgs := []string{"$WLP_OUTPUT_DIR/helper/linperf.sh"}
+	linperfCmdArgs = append(linperfCmdArgs, "--output-dir="+outputDir)
+	linperfCmdArgs = append(linperfCmdArgs, "--dir-name="+dirName)
+	linperfCmdArgs = append(linperfCmdArgs, "-s "+attrs["timespan"])
+	linperfCmdArgs = append(linperfCmdArgs, "-j "+attrs["interval"])
+	linperfCmdArgs = append(linperfCmdArgs, "--ignore-root")
+	linperfCmdArgs = append(linperfCmdArgs, "--clean-up-javacores")
+	linperfCmdArgs = append(linperfCmdArgs, "\"1\"")
+	return strings.Join(linperfCmdArgs, " ")
+}
+
+func (c *linperfCollector) OutputFile(stdout, outputDir, dirName string) string {
+	return getLinperfDataFileName(stdout)
+}
+
+// Matches a string within line in array lines that excludes the prefix and includes the suffix
+func getSubstring(lines []string, prefix, suffix string) string {
+	for _, line := range lines {
+		if strings.Contains(line, prefix) && strings.Contains(line, suffix) {
+			startIndex := strings.Index(line, prefix) + len(prefix)
+			endIndex := strings.Index(line, suffix)
+			if startIndex != -1 && endIndex != -1 && startIndex < len(line) && endIndex+len(suffix) <= len(line) {
+				return line[startIndex : endIndex+len(suffix)]
+			}
+		}
+	}
+	return ""
+}
+
+// Gets the linperf data file name from the stdout output of the linperf.sh script
+func getLinperfDataFileName(linperfOutput string) string {
+	linperfOutputLines := strings.Split(linperfOutput, "\n")
+	// 1. capture line '2025-07-23 13:34:54 Compressing the following files into linperf_RESULTS_sample.20250723.133044.tar.gz.'
+	prefix := "Compressing the following files into "
+	suffix := ".tar.gz"
+	fileName := getSubstring(linperfOutputLines, prefix, suffix)
+	if fileName == "" {
+		return "Could not parse tgz name from linperf.sh output"
+	}
+	// 2. capture line '* /serviceability/olo-test/example-75dfd65979-mwvnz/performanceData/linperf_RESULTS_sample.20250723.133044'
+	prefix = "* /serviceability/"
+	suffix = "/performanceData"
+	filePath := getSubstring(linperfOutputLines, prefix, suffix)
+	if filePath == "" {
+		return "Could not parse tgz path from linperf.sh output"
+	}
+	return fmt.Sprintf("/serviceability/%s/%s", filePath, fileName)
+}
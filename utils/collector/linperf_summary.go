@@ -0,0 +1,214 @@
+package collector
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	olv1 "github.com/OpenLiberty/open-liberty-operator/api/v1"
+)
+
+// The number of threads listed in the summary
+const summaryTopThreads = 5
+
+var (
+	loadAverageRegexp = regexp.MustCompile(`load averages?: (.*)$`)
+	threadStateRegexp = regexp.MustCompile(`\bstate:([A-Z]+)`)
+	gcCountRegexp     = regexp.MustCompile(`\b(global|scavenge)count=([0-9]+)`)
+)
+
+// The CPU usage of a thread across the top dash H snapshots
+type threadCPU struct {
+	name  string
+	total float64
+	max   float64
+}
+
+// Collects the summary while the files of the linperf archive are read
+type linperfSummary struct {
+	loadAverage  string
+	snapshots    int
+	threads      map[string]*threadCPU
+	javacores    int
+	lastJavacore string
+	threadStates map[string]int
+	gcCounts     map[string]int
+}
+
+// Summarize parses the uptime.out, topdashH.<pid>.out and javacore.<pid>.tar files of the linperf archive
+func (c *linperfCollector) Summarize(r io.Reader) (*olv1.PerformanceDataSummary, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the performance data file: %v", err)
+	}
+	defer gz.Close()
+	s := &linperfSummary{threads: map[string]*threadCPU{}}
+	archive := tar.NewReader(gz)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the performance data file: %v", err)
+		}
+		name := path.Base(header.Name)
+		switch {
+		case name == "uptime.out":
+			err = s.parseUptime(archive)
+		case strings.HasPrefix(name, "topdashH.") && strings.HasSuffix(name, ".out"):
+			err = s.parseTopDashH(archive)
+		case strings.HasPrefix(name, "javacore.") && strings.HasSuffix(name, ".tar"):
+			err = s.parseJavacores(tar.NewReader(archive))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %v", name, err)
+		}
+	}
+	return s.summary(), nil
+}
+
+func (s *linperfSummary) parseUptime(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if match := loadAverageRegexp.FindStringSubmatch(scanner.Text()); match != nil {
+			s.loadAverage = strings.TrimSpace(match[1])
+		}
+	}
+	return scanner.Err()
+}
+
+// Adds the CPU usage of the threads in each snapshot of top -bH, whose thread table starts with a PID header line and
+// ends with a blank line
+func (s *linperfSummary) parseTopDashH(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	var columns map[string]int
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			columns = nil
+			continue
+		}
+		if fields[0] == "PID" {
+			columns = map[string]int{}
+			for i, field := range fields {
+				columns[field] = i
+			}
+			s.snapshots++
+			continue
+		}
+		cpuColumn, hasCPU := columns["%CPU"]
+		commandColumn, hasCommand := columns["COMMAND"]
+		if !hasCPU || !hasCommand || len(fields) <= commandColumn {
+			continue
+		}
+		cpu, err := strconv.ParseFloat(fields[cpuColumn], 64)
+		if err != nil {
+			continue
+		}
+		thread, found := s.threads[fields[0]]
+		if !found {
+			thread = &threadCPU{name: strings.Join(fields[commandColumn:], " ")}
+			s.threads[fields[0]] = thread
+		}
+		thread.total += cpu
+		if cpu > thread.max {
+			thread.max = cpu
+		}
+	}
+	return scanner.Err()
+}
+
+// Counts the javacores of the archive, keeping the thread states and GC counts of the most recent one. Javacore names
+// start with the date and time they were taken, so the most recent one has the greatest name.
+func (s *linperfSummary) parseJavacores(archive *tar.Reader) error {
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		name := path.Base(header.Name)
+		if !strings.HasPrefix(name, "javacore.") {
+			continue
+		}
+		s.javacores++
+		if name < s.lastJavacore {
+			continue
+		}
+		threadStates, gcCounts, err := parseJavacore(archive)
+		if err != nil {
+			return err
+		}
+		s.lastJavacore, s.threadStates, s.gcCounts = name, threadStates, gcCounts
+	}
+}
+
+// Returns the number of threads in each state and the highest GC counts in the GC history of the javacore
+func parseJavacore(r io.Reader) (map[string]int, map[string]int, error) {
+	threadStates := map[string]int{}
+	gcCounts := map[string]int{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "3XMTHREADINFO "):
+			if match := threadStateRegexp.FindStringSubmatch(line); match != nil {
+				threadStates[match[1]]++
+			}
+		case strings.HasPrefix(line, "3STHSTTYPE "):
+			for _, match := range gcCountRegexp.FindAllStringSubmatch(line, -1) {
+				if count, err := strconv.Atoi(match[2]); err == nil && count > gcCounts[match[1]] {
+					gcCounts[match[1]] = count
+				}
+			}
+		}
+	}
+	return threadStates, gcCounts, scanner.Err()
+}
+
+func (s *linperfSummary) summary() *olv1.PerformanceDataSummary {
+	summary := &olv1.PerformanceDataSummary{
+		LoadAverage: s.loadAverage,
+		Javacores:   s.javacores,
+	}
+	if len(s.threadStates) > 0 {
+		summary.ThreadStates = s.threadStates
+	}
+	if len(s.gcCounts) > 0 {
+		summary.GCCounts = s.gcCounts
+	}
+	ids := []string{}
+	for id := range s.threads {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if s.threads[ids[i]].total != s.threads[ids[j]].total {
+			return s.threads[ids[i]].total > s.threads[ids[j]].total
+		}
+		return ids[i] < ids[j]
+	})
+	for _, id := range ids {
+		thread := s.threads[id]
+		if len(summary.TopThreads) == summaryTopThreads || thread.total == 0 {
+			break
+		}
+		summary.TopThreads = append(summary.TopThreads, olv1.PerformanceDataThread{
+			ID:         id,
+			Name:       thread.name,
+			AverageCPU: strconv.FormatFloat(thread.total/float64(s.snapshots), 'f', 1, 64),
+			MaxCPU:     strconv.FormatFloat(thread.max, 'f', 1, 64),
+		})
+	}
+	return summary
+}
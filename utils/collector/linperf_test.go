@@ -0,0 +1,119 @@
+package collector
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	olv1 "github.com/OpenLiberty/open-liberty-operator/api/v1"
+)
+
+// Returns a tar archive of the files, in the order of names
+func getTestTar(names []string, files map[string]string) []byte {
+	var buf bytes.Buffer
+	archive := tar.NewWriter(&buf)
+	for _, name := range names {
+		archive.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(files[name]))})
+		archive.Write([]byte(files[name]))
+	}
+	archive.Close()
+	return buf.Bytes()
+}
+
+func Test_getLinperfDataFileName(t *testing.T) {
+	outputFile := getLinperfDataFileName(`2025-07-23 13:34:54 Compressing the following files into linperf_RESULTS_sample.20250723.133044.tar.gz.
+2025-07-23 13:34:54 \tTemporary directory linperf_RESULTS_sample.20250723.133044 removed.
+2025-07-23 13:34:54 \tlinperf script complete.
+To share with IBM support, upload all the following files:
+* /serviceability/olo-test/example-75dfd65979-mwvnz/performanceData/linperf_RESULTS_sample.20250723.133044
+* /var/log/messages (Linux OS files)
+For WebSphere Application Server:
+* Logs (systemout.log, native_stderr.log, etc)
+* server.xml for the server(s) that you are providing data for
+For Liberty:
+* Logs (messages.log, console.log, etc)
+* server.env, server.xml, and jvm.options`)
+	tests := []Test{
+		{"capture output file", "/serviceability/olo-test/example-75dfd65979-mwvnz/performanceData/linperf_RESULTS_sample.20250723.133044.tar.gz", outputFile},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func Test_getSubstring(t *testing.T) {
+	substr1 := `hello world`
+	tests := []Test{
+		{"check substring 1", " wo", getSubstring([]string{substr1}, "ello", "wo")},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	javacores := getTestTar([]string{"javacore.20240110.093030.123.0002.txt", "javacore.20240110.093000.123.0001.txt"}, map[string]string{
+		"javacore.20240110.093000.123.0001.txt": `3XMTHREADINFO      "main" J9VMThread:0x1, omrthread_t:0x2, java/lang/Thread:0x3, state:R, prio=5
+`,
+		"javacore.20240110.093030.123.0002.txt": `1STGCHTYPE     GC History
+3STHSTTYPE     09:30:29:000000000 GMT j9mm.560 -   LocalGC start: globalcount=3 scavengecount=117 weakrefs=0 soft=0 phantom=0 finalizers=0
+3STHSTTYPE     09:30:20:000000000 GMT j9mm.91 -   GlobalGC start: globalcount=2
+3XMTHREADINFO      "main" J9VMThread:0x1, omrthread_t:0x2, java/lang/Thread:0x3, state:CW, prio=5
+3XMTHREADINFO1            (native thread ID:0x7B, native priority:0x5, native policy:UNKNOWN, vmstate:CW, vm thread flags:0x00000481)
+3XMTHREADINFO      "Default Executor-thread-1" J9VMThread:0x4, omrthread_t:0x5, java/lang/Thread:0x6, state:R, prio=5
+3XMTHREADINFO      "Default Executor-thread-2" J9VMThread:0x7, omrthread_t:0x8, java/lang/Thread:0x9, state:CW, prio=5
+`,
+	})
+	topDashH := `2024-01-10 09:30:00	Collecting against PID 123.
+
+top - 09:30:00 up 1 day,  2:03,  0 users,  load average: 0.52, 0.58, 0.59
+Threads:  80 total,   1 running,  79 sleeping,   0 stopped,   0 zombie
+
+    PID USER      PR  NI    VIRT    RES    SHR S  %CPU  %MEM     TIME+ COMMAND
+    130 1001      20   0 3824000 412000  38000 R  50.0   2.6   0:10.00 Default Executo
+    131 1001      20   0 3824000 412000  38000 S  10.0   2.6   0:01.00 GC Slave
+    123 1001      20   0 3824000 412000  38000 S   0.0   2.6   0:05.00 java
+
+top - 09:30:05 up 1 day,  2:03,  0 users,  load average: 0.60, 0.59, 0.59
+
+    PID USER      PR  NI    VIRT    RES    SHR S  %CPU  %MEM     TIME+ COMMAND
+    130 1001      20   0 3824000 412000  38000 R  30.0   2.6   0:11.50 Default Executo
+    131 1001      20   0 3824000 412000  38000 S  40.0   2.6   0:03.00 GC Slave
+`
+	names := []string{"uptime.out", "topdashH.123.out", "javacore.123.tar"}
+	archive := getTestTar(names, map[string]string{
+		"uptime.out":       " 09:30:00 up 1 day,  2:03,  0 users,  load average: 0.52, 0.58, 0.59\n",
+		"topdashH.123.out": topDashH,
+		"javacore.123.tar": string(javacores),
+	})
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	gz.Write(archive)
+	gz.Close()
+
+	linperf, _ := Get("linperf")
+	summary, err := linperf.(Summarizer).Summarize(&buf)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	_, err = linperf.(Summarizer).Summarize(bytes.NewReader(archive))
+	netstat, _ := Get("netstat-snapshot")
+	_, netstatSummarized := netstat.(Summarizer)
+
+	tests := []Test{
+		{"load average", "0.52, 0.58, 0.59", summary.LoadAverage},
+		{"top threads", []olv1.PerformanceDataThread{
+			{ID: "130", Name: "Default Executo", AverageCPU: "40.0", MaxCPU: "50.0"},
+			{ID: "131", Name: "GC Slave", AverageCPU: "25.0", MaxCPU: "40.0"},
+		}, summary.TopThreads},
+		{"javacores", 2, summary.Javacores},
+		{"thread states of the last javacore", map[string]int{"CW": 2, "R": 1}, summary.ThreadStates},
+		{"gc counts", map[string]int{"global": 3, "scavenge": 117}, summary.GCCounts},
+		{"not compressed", true, err != nil},
+		{"netstat-snapshot is not summarized", false, netstatSummarized},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
@@ -0,0 +1,53 @@
+package collector
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	olv1 "github.com/OpenLiberty/open-liberty-operator/api/v1"
+)
+
+// Collects snapshots of the network connections of the container with netstat at every interval of the timespan
+type netstatCollector struct{}
+
+func init() {
+	Register(&netstatCollector{})
+}
+
+func (c *netstatCollector) Name() string {
+	return "netstat-snapshot"
+}
+
+func (c *netstatCollector) AssetsFolder() string {
+	return ""
+}
+
+func (c *netstatCollector) RequiredCommands() []string {
+	return []string{"netstat", "tar"}
+}
+
+func (c *netstatCollector) MissingCommandsMessage() string {
+	return "The Liberty container is missing packages required for collecting network snapshots; To install the packages, include the command 'RUN command -v yum && pkgcmd=yum || pkgcmd=microdnf && ($pkgcmd update -y && $pkgcmd install -y net-tools tar)' in the Liberty container image definition"
+}
+
+func (c *netstatCollector) Attrs(instance *olv1.OpenLibertyPerformanceData) map[string]string {
+	return map[string]string{
+		"timespan": strconv.Itoa(instance.GetTimespan()),
+		"interval": strconv.Itoa(instance.GetInterval()),
+	}
+}
+
+func (c *netstatCollector) DirName(attrs map[string]string, now time.Time) string {
+	return fmt.Sprintf("netstat_RESULTS_%s.%s", attrs["name"], getTimestamp(now))
+}
+
+func (c *netstatCollector) Command(attrs map[string]string, outputDir, dirName string) string {
+	snapshotFile := outputDir + dirName + "/netstat.txt"
+	return fmt.Sprintf("mkdir -p %s%s && end=$(($(date +%%s) + %s)) && while [ $(date +%%s) -lt $end ]; do date >> %s; netstat -an >> %s; sleep %s; done && tar -czf %s -C %s %s && rm -rf %s%s",
+		outputDir, dirName, attrs["timespan"], snapshotFile, snapshotFile, attrs["interval"], c.OutputFile("", outputDir, dirName), outputDir, dirName, outputDir, dirName)
+}
+
+func (c *netstatCollector) OutputFile(stdout, outputDir, dirName string) string {
+	return outputDir + dirName + ".tar.gz"
+}
@@ -0,0 +1,135 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// The metrics of the operator are registered with the controller-runtime registry, so they are served by the metrics
+// endpoint of the manager along with the controller-runtime metrics
+
+const namespace = "openliberty_operator"
+
+// The day-2 operations
+const (
+	OperationDump            = "dump"
+	OperationTrace           = "trace"
+	OperationPerformanceData = "performance_data"
+)
+
+// The outcomes of a day-2 operation
+const (
+	OutcomeSucceeded = "succeeded"
+	OutcomeFailed    = "failed"
+)
+
+var (
+	reconcileDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "reconcile_duration_seconds",
+		Help:      "Duration of the reconciles of the operator controllers.",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 2, 12),
+	}, []string{"controller"})
+	reconcileErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "reconcile_errors_total",
+		Help:      "Number of reconciles of the operator controllers that reported an error in the status of the instance.",
+	}, []string{"controller"})
+	operations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "day2_operations_total",
+		Help:      "Number of day-2 operations run on pods, by outcome.",
+	}, []string{"operation", "outcome"})
+	operationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "day2_operation_duration_seconds",
+		Help:      "Duration of the day-2 operations that succeeded on pods. The duration of a trace is the time the pod was traced.",
+		Buckets:   prometheus.ExponentialBuckets(1, 2, 14),
+	}, []string{"operation"})
+	podInjectorWorkers = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "pod_injector_workers",
+		Help:      "Number of performance data collectors running in the pod injector.",
+	})
+	podInjectorMaxWorkers = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "pod_injector_max_workers",
+		Help:      "Maximum number of performance data collectors run by the pod injector at the same time.",
+	})
+	podInjectorQueueDepth = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "pod_injector_queue_depth",
+		Help:      "Number of pods waiting for a pod injector worker to collect their performance data.",
+	})
+	keyRotations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "key_rotations_total",
+		Help:      "Number of times the operator generated or rotated a shared key.",
+	}, []string{"key"})
+	imageMetadataPullFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "image_metadata_pull_failures_total",
+		Help:      "Number of failures to pull the metadata of an application image to find its Liberty version.",
+	})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(
+		reconcileDuration,
+		reconcileErrors,
+		operations,
+		operationDuration,
+		podInjectorWorkers,
+		podInjectorMaxWorkers,
+		podInjectorQueueDepth,
+		keyRotations,
+		imageMetadataPullFailures,
+	)
+}
+
+// ObserveReconcileDuration observes the time since start as the duration of a reconcile of the controller. It is meant
+// to be deferred at the start of the reconcile.
+func ObserveReconcileDuration(controller string, start time.Time) {
+	reconcileDuration.WithLabelValues(controller).Observe(time.Since(start).Seconds())
+}
+
+// CountReconcileError counts a reconcile of the controller that reported an error
+func CountReconcileError(controller string) {
+	reconcileErrors.WithLabelValues(controller).Inc()
+}
+
+// CountOperation counts the outcome of a day-2 operation on a pod
+func CountOperation(operation string, succeeded bool) {
+	outcome := OutcomeFailed
+	if succeeded {
+		outcome = OutcomeSucceeded
+	}
+	operations.WithLabelValues(operation, outcome).Inc()
+}
+
+// ObserveOperationDuration observes the duration of a day-2 operation on a pod, ignoring durations that are not known
+func ObserveOperationDuration(operation string, duration time.Duration) {
+	if duration <= 0 {
+		return
+	}
+	operationDuration.WithLabelValues(operation).Observe(duration.Seconds())
+}
+
+// SetPodInjectorWorkers sets the number of running, maximum and waiting workers of the pod injector
+func SetPodInjectorWorkers(workers, maxWorkers, queued int) {
+	podInjectorWorkers.Set(float64(workers))
+	podInjectorMaxWorkers.Set(float64(maxWorkers))
+	podInjectorQueueDepth.Set(float64(queued))
+}
+
+// CountKeyRotation counts a rotation of the key, which is named by its resource sharing file name, such as ltpa
+func CountKeyRotation(key string) {
+	keyRotations.WithLabelValues(key).Inc()
+}
+
+// CountImageMetadataPullFailure counts a failure to pull the metadata of an application image
+func CountImageMetadataPullFailure() {
+	imageMetadataPullFailures.Inc()
+}
@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+type Test struct {
+	test     string
+	expected interface{}
+	actual   interface{}
+}
+
+func verifyTests(tests []Test) error {
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.actual, tt.expected) {
+			return fmt.Errorf("%s test expected: (%v) actual: (%v)", tt.test, tt.expected, tt.actual)
+		}
+	}
+	return nil
+}
+
+// Returns the value of the counter or gauge, or the sample count of the histogram, whose labels match the given labels
+func getMetricValue(t *testing.T, name string, labels map[string]string) float64 {
+	families, err := ctrlmetrics.Registry.Gather()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, m := range family.GetMetric() {
+			matched := 0
+			for _, label := range m.GetLabel() {
+				if labels[label.GetName()] == label.GetValue() {
+					matched++
+				}
+			}
+			if matched != len(labels) {
+				continue
+			}
+			switch {
+			case m.GetCounter() != nil:
+				return m.GetCounter().GetValue()
+			case m.GetGauge() != nil:
+				return m.GetGauge().GetValue()
+			case m.GetHistogram() != nil:
+				return float64(m.GetHistogram().GetSampleCount())
+			}
+		}
+	}
+	return 0
+}
+
+func TestOperationMetrics(t *testing.T) {
+	succeeded := map[string]string{"operation": OperationDump, "outcome": OutcomeSucceeded}
+	failed := map[string]string{"operation": OperationDump, "outcome": OutcomeFailed}
+	dump := map[string]string{"operation": OperationDump}
+	CountOperation(OperationDump, true)
+	CountOperation(OperationDump, true)
+	CountOperation(OperationDump, false)
+	ObserveOperationDuration(OperationDump, 3*time.Second)
+	// unknown durations are not observed
+	ObserveOperationDuration(OperationDump, 0)
+
+	tests := []Test{
+		{"succeeded", 2.0, getMetricValue(t, "openliberty_operator_day2_operations_total", succeeded)},
+		{"failed", 1.0, getMetricValue(t, "openliberty_operator_day2_operations_total", failed)},
+		{"durations", 1.0, getMetricValue(t, "openliberty_operator_day2_operation_duration_seconds", dump)},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestPodInjectorMetrics(t *testing.T) {
+	SetPodInjectorWorkers(3, 10, 2)
+
+	tests := []Test{
+		{"workers", 3.0, getMetricValue(t, "openliberty_operator_pod_injector_workers", nil)},
+		{"max workers", 10.0, getMetricValue(t, "openliberty_operator_pod_injector_max_workers", nil)},
+		{"queue depth", 2.0, getMetricValue(t, "openliberty_operator_pod_injector_queue_depth", nil)},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestReconcileMetrics(t *testing.T) {
+	controller := map[string]string{"controller": "test"}
+	ObserveReconcileDuration("test", time.Now().Add(-time.Second))
+	CountReconcileError("test")
+	CountKeyRotation("ltpa")
+	CountImageMetadataPullFailure()
+
+	tests := []Test{
+		{"reconcile duration", 1.0, getMetricValue(t, "openliberty_operator_reconcile_duration_seconds", controller)},
+		{"reconcile errors", 1.0, getMetricValue(t, "openliberty_operator_reconcile_errors_total", controller)},
+		{"key rotations", 1.0, getMetricValue(t, "openliberty_operator_key_rotations_total", map[string]string{"key": "ltpa"})},
+		{"image metadata pull failures", 1.0, getMetricValue(t, "openliberty_operator_image_metadata_pull_failures_total", nil)},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
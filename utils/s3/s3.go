@@ -0,0 +1,392 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Region used to sign requests when none is configured. MinIO and most S3-compatible services accept it.
+const DefaultRegion = "us-east-1"
+
+// Objects larger than this are uploaded in parts of at least this size. Each part is held in memory while it is uploaded.
+const minPartSize = 16 * 1024 * 1024
+
+// Maximum number of parts of a multipart upload
+const maxParts = 10000
+
+// Credentials are the access keys used to sign requests
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	// Optional. Temporary security token
+	SessionToken string
+}
+
+// Client uploads objects to a bucket of an S3-compatible object storage service, such as Amazon S3 or MinIO.
+// Requests use path-style addressing and are signed with AWS Signature Version 4.
+type Client struct {
+	endpoint    *url.URL
+	bucket      string
+	region      string
+	credentials Credentials
+	httpClient  *http.Client
+	now         func() time.Time
+}
+
+// Object describes an uploaded object
+type Object struct {
+	URL    string
+	SHA256 string
+	Size   int64
+}
+
+// NewClient returns a Client for the bucket served at endpoint, e.g. https://s3.eu-west-1.amazonaws.com or http://minio.minio:9000
+func NewClient(endpoint, bucket, region string, credentials Credentials, httpClient *http.Client) (*Client, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint %q: %v", endpoint, err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid endpoint %q: expected an http or https URL", endpoint)
+	}
+	if bucket == "" {
+		return nil, fmt.Errorf("bucket name is empty")
+	}
+	if credentials.AccessKeyID == "" || credentials.SecretAccessKey == "" {
+		return nil, fmt.Errorf("access key ID and secret access key are required")
+	}
+	if region == "" {
+		region = DefaultRegion
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	u.RawPath = ""
+	return &Client{
+		endpoint:    u,
+		bucket:      bucket,
+		region:      region,
+		credentials: credentials,
+		httpClient:  httpClient,
+		now:         time.Now,
+	}, nil
+}
+
+// ObjectURL returns the URL of the object with the given key
+func (c *Client) ObjectURL(key string) string {
+	u := *c.endpoint
+	u.Path = c.endpoint.Path + "/" + c.bucket + "/" + strings.TrimPrefix(key, "/")
+	u.RawPath = uriEncode(u.Path, false)
+	return u.String()
+}
+
+// PutObject uploads size bytes read from body as the object with the given key, returning its URL and SHA-256 checksum.
+// Objects larger than minPartSize are uploaded with a multipart upload. The SHA-256 checksum of every request body is
+// sent in the x-amz-checksum-sha256 header, so the storage service rejects any part whose content does not match the
+// returned checksum.
+func (c *Client) PutObject(ctx context.Context, key string, body io.Reader, size int64) (*Object, error) {
+	objectURL := c.ObjectURL(key)
+	hash := sha256.New()
+	body = io.TeeReader(body, hash)
+	var err error
+	if size <= minPartSize {
+		err = c.putObject(ctx, objectURL, body, size)
+	} else {
+		err = c.putObjectInParts(ctx, objectURL, body, size)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Object{
+		URL:    objectURL,
+		SHA256: hex.EncodeToString(hash.Sum(nil)),
+		Size:   size,
+	}, nil
+}
+
+// Uploads the object with a single request
+func (c *Client) putObject(ctx context.Context, objectURL string, body io.Reader, size int64) error {
+	data, err := readPart(body, size)
+	if err != nil {
+		return err
+	}
+	req, err := c.newPayloadRequest(ctx, http.MethodPut, objectURL, "", http.Header{"Content-Type": {"application/octet-stream"}}, data)
+	if err != nil {
+		return err
+	}
+	_, err = c.do(req, data)
+	return err
+}
+
+// Uploads the object with a multipart upload, aborting the upload if any part fails
+func (c *Client) putObjectInParts(ctx context.Context, objectURL string, body io.Reader, size int64) error {
+	partSize := int64(minPartSize)
+	if size > partSize*maxParts {
+		partSize = (size + maxParts - 1) / maxParts
+	}
+
+	header := http.Header{"Content-Type": {"application/octet-stream"}, "X-Amz-Checksum-Algorithm": {"SHA256"}}
+	req, err := c.newPayloadRequest(ctx, http.MethodPost, objectURL, "uploads", header, nil)
+	if err != nil {
+		return err
+	}
+	respBody, err := c.do(req, nil)
+	if err != nil {
+		return err
+	}
+	var initiated struct {
+		UploadID string `xml:"UploadId"`
+	}
+	if err := xml.Unmarshal(respBody, &initiated); err != nil || initiated.UploadID == "" {
+		return fmt.Errorf("%s %s: response has no upload ID", req.Method, req.URL.Redacted())
+	}
+
+	if err := c.uploadParts(ctx, objectURL, initiated.UploadID, body, size, partSize); err != nil {
+		// the context may already be cancelled, so the upload is aborted with a new one
+		abortCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if req, abortErr := c.newPayloadRequest(abortCtx, http.MethodDelete, objectURL, "uploadId="+url.QueryEscape(initiated.UploadID), nil, nil); abortErr == nil {
+			c.do(req, nil)
+		}
+		return err
+	}
+	return nil
+}
+
+type completedPart struct {
+	PartNumber     int    `xml:"PartNumber"`
+	ETag           string `xml:"ETag"`
+	ChecksumSHA256 string `xml:"ChecksumSHA256"`
+}
+
+// Uploads the parts of the multipart upload and completes it
+func (c *Client) uploadParts(ctx context.Context, objectURL, uploadID string, body io.Reader, size, partSize int64) error {
+	var complete struct {
+		XMLName xml.Name        `xml:"CompleteMultipartUpload"`
+		Parts   []completedPart `xml:"Part"`
+	}
+	for partNumber := 1; size > 0; partNumber++ {
+		n := min(partSize, size)
+		data, err := readPart(body, n)
+		if err != nil {
+			return err
+		}
+		query := fmt.Sprintf("partNumber=%d&uploadId=%s", partNumber, url.QueryEscape(uploadID))
+		req, err := c.newPayloadRequest(ctx, http.MethodPut, objectURL, query, nil, data)
+		if err != nil {
+			return err
+		}
+		resp, err := c.doResponse(req, data)
+		if err != nil {
+			return err
+		}
+		resp.Body.Close()
+		complete.Parts = append(complete.Parts, completedPart{
+			PartNumber:     partNumber,
+			ETag:           resp.Header.Get("ETag"),
+			ChecksumSHA256: req.Header.Get("X-Amz-Checksum-Sha256"),
+		})
+		size -= n
+	}
+
+	data, err := xml.Marshal(complete)
+	if err != nil {
+		return err
+	}
+	req, err := c.newPayloadRequest(ctx, http.MethodPost, objectURL, "uploadId="+url.QueryEscape(uploadID), http.Header{"Content-Type": {"application/xml"}}, data)
+	if err != nil {
+		return err
+	}
+	respBody, err := c.do(req, data)
+	if err != nil {
+		return err
+	}
+	// a request to complete a multipart upload can fail after the response status is sent
+	var s3Error struct {
+		XMLName xml.Name `xml:"Error"`
+		Code    string   `xml:"Code"`
+		Message string   `xml:"Message"`
+	}
+	if xml.Unmarshal(respBody, &s3Error) == nil && s3Error.Code != "" {
+		return fmt.Errorf("%s %s: %s: %s", req.Method, req.URL.Redacted(), s3Error.Code, s3Error.Message)
+	}
+	return nil
+}
+
+// Reads the next n bytes of the body
+func readPart(body io.Reader, n int64) ([]byte, error) {
+	data := make([]byte, n)
+	if _, err := io.ReadFull(body, data); err != nil {
+		return nil, fmt.Errorf("failed to read %d bytes: %v", n, err)
+	}
+	return data, nil
+}
+
+// Returns a signed request for the object with the given raw query, headers and payload. A non-empty payload is sent with its SHA-256
+// checksum in the x-amz-checksum-sha256 header, which the storage service verifies along with the signed payload hash.
+func (c *Client) newPayloadRequest(ctx context.Context, method, objectURL, rawQuery string, header http.Header, payload []byte) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, objectURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	req.URL.RawQuery = rawQuery
+	sum := sha256.Sum256(payload)
+	if len(payload) > 0 {
+		req.Header.Set("X-Amz-Checksum-Sha256", base64.StdEncoding.EncodeToString(sum[:]))
+	}
+	signV4(req, c.credentials, c.region, hex.EncodeToString(sum[:]), c.now())
+	return req, nil
+}
+
+// Sends the request with the payload and returns the body of a successful response
+func (c *Client) do(req *http.Request, payload []byte) ([]byte, error) {
+	resp, err := c.doResponse(req, payload)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+// Sends the request with the payload and returns a successful response
+func (c *Client) doResponse(req *http.Request, payload []byte) (*http.Response, error) {
+	// S3 does not accept chunked uploads, so the length of the body must be known
+	req.ContentLength = int64(len(payload))
+	req.Body = http.NoBody
+	if len(payload) > 0 {
+		req.Body = io.NopCloser(bytes.NewReader(payload))
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		defer resp.Body.Close()
+		return nil, responseError(resp)
+	}
+	return resp, nil
+}
+
+// Returns an error describing a failed request, using the code and message of the S3 error response if there is one
+func responseError(resp *http.Response) error {
+	var s3Error struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	if xml.Unmarshal(body, &s3Error) == nil && s3Error.Code != "" {
+		return fmt.Errorf("%s %s: %s: %s", resp.Request.Method, resp.Request.URL.Redacted(), s3Error.Code, s3Error.Message)
+	}
+	return fmt.Errorf("%s %s: %s", resp.Request.Method, resp.Request.URL.Redacted(), resp.Status)
+}
+
+// Signs the request with AWS Signature Version 4, covering the host and all headers already set on the request
+func signV4(req *http.Request, credentials Credentials, region, payloadHash string, t time.Time) {
+	amzDate := t.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if credentials.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", credentials.SessionToken)
+	}
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	headers := map[string]string{"host": host}
+	for name, values := range req.Header {
+		trimmed := make([]string, len(values))
+		for i := range values {
+			trimmed[i] = strings.Join(strings.Fields(values[i]), " ")
+		}
+		headers[strings.ToLower(name)] = strings.Join(trimmed, ",")
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		uriEncode(req.URL.Path, false),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hexSHA256(canonicalRequest)
+
+	key := hmacSHA256([]byte("AWS4"+credentials.SecretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", credentials.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	params := []string{}
+	for _, key := range keys {
+		values := append([]string{}, query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			params = append(params, uriEncode(key, true)+"="+uriEncode(value, true))
+		}
+	}
+	return strings.Join(params, "&")
+}
+
+// Percent-encodes every byte except the unreserved characters A-Z, a-z, 0-9, '-', '.', '_' and '~', and '/' unless encodeSlash is set
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '-' || c == '.' || c == '_' || c == '~' || (c == '/' && !encodeSlash) {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func hexSHA256(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
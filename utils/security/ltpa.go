@@ -0,0 +1,295 @@
+package security
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/cipher"
+	"crypto/des"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// Properties of the LTPA keys file created by the Liberty securityUtility createLTPAKeys command
+const (
+	ltpaSharedKeyProperty    = "com.ibm.websphere.ltpa.3DESKey"
+	ltpaPrivateKeyProperty   = "com.ibm.websphere.ltpa.PrivateKey"
+	ltpaPublicKeyProperty    = "com.ibm.websphere.ltpa.PublicKey"
+	ltpaRealmProperty        = "com.ibm.websphere.ltpa.Realm"
+	ltpaVersionProperty      = "com.ibm.websphere.ltpa.version"
+	ltpaCreationHostProperty = "com.ibm.websphere.CreationHost"
+	ltpaCreationDateProperty = "com.ibm.websphere.CreationDate"
+)
+
+const (
+	ltpaKeysFileHeader = "IBM WebSphere Application Server key file"
+	ltpaDefaultRealm   = "defaultRealm"
+	ltpaVersion        = "1.0"
+	ltpaCreationHost   = "localhost"
+	ltpaSharedKeySize  = 24
+	ltpaRSAKeyBits     = 1024
+	// Format of java.util.Date.toString(), used for the creation date
+	javaDateFormat = "Mon Jan 02 15:04:05 MST 2006"
+)
+
+// Order of the properties in the LTPA keys file
+var ltpaKeysFileProperties = []string{ltpaCreationDateProperty, ltpaVersionProperty, ltpaSharedKeyProperty, ltpaCreationHostProperty, ltpaPrivateKeyProperty, ltpaRealmProperty, ltpaPublicKeyProperty}
+
+// LTPAKeys are the keys of an LTPA keys file, decrypted with the keys password
+type LTPAKeys struct {
+	SharedKey  []byte
+	PrivateKey *rsa.PrivateKey
+	Realm      string
+}
+
+// CreateLTPAKeys returns the content of a new LTPA keys file protected by the password, in the same format as the Liberty securityUtility createLTPAKeys command
+func CreateLTPAKeys(password string) ([]byte, error) {
+	return createLTPAKeys(rand.Reader, password, time.Now())
+}
+
+func createLTPAKeys(random io.Reader, password string, created time.Time) ([]byte, error) {
+	sharedKey := make([]byte, ltpaSharedKeySize)
+	if _, err := io.ReadFull(random, sharedKey); err != nil {
+		return nil, err
+	}
+	// Liberty expects both primes to have the same encoded length
+	var privateKey *rsa.PrivateKey
+	for privateKey == nil || len(javaBytes(privateKey.Primes[0])) != len(javaBytes(privateKey.Primes[1])) {
+		var err error
+		if privateKey, err = rsa.GenerateKey(random, ltpaRSAKeyBits); err != nil {
+			return nil, err
+		}
+	}
+	encryptedSharedKey, err := encryptLTPAKey(password, sharedKey)
+	if err != nil {
+		return nil, err
+	}
+	encryptedPrivateKey, err := encryptLTPAKey(password, encodeLTPAPrivateKey(privateKey))
+	if err != nil {
+		return nil, err
+	}
+	return writeProperties(ltpaKeysFileHeader, created, map[string]string{
+		ltpaCreationDateProperty: created.Format(javaDateFormat),
+		ltpaVersionProperty:      ltpaVersion,
+		ltpaSharedKeyProperty:    base64.StdEncoding.EncodeToString(encryptedSharedKey),
+		ltpaCreationHostProperty: ltpaCreationHost,
+		ltpaPrivateKeyProperty:   base64.StdEncoding.EncodeToString(encryptedPrivateKey),
+		ltpaRealmProperty:        ltpaDefaultRealm,
+		ltpaPublicKeyProperty:    base64.StdEncoding.EncodeToString(encodeLTPAPublicKey(&privateKey.PublicKey)),
+	}), nil
+}
+
+// ParseLTPAKeys decrypts the keys of an LTPA keys file with the keys password
+func ParseLTPAKeys(data []byte, password string) (*LTPAKeys, error) {
+	properties, err := readProperties(data)
+	if err != nil {
+		return nil, err
+	}
+	for _, property := range []string{ltpaSharedKeyProperty, ltpaPrivateKeyProperty, ltpaPublicKeyProperty} {
+		if properties[property] == "" {
+			return nil, fmt.Errorf("the LTPA keys file does not have the %s property", property)
+		}
+	}
+	sharedKey, err := decryptLTPAKeyProperty(password, properties[ltpaSharedKeyProperty])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt the LTPA shared key, the password may be incorrect: %w", err)
+	}
+	if len(sharedKey) != ltpaSharedKeySize {
+		return nil, fmt.Errorf("the LTPA shared key must be %d bytes long, found %d bytes", ltpaSharedKeySize, len(sharedKey))
+	}
+	encodedPrivateKey, err := decryptLTPAKeyProperty(password, properties[ltpaPrivateKeyProperty])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt the LTPA private key, the password may be incorrect: %w", err)
+	}
+	privateKey, err := decodeLTPAPrivateKey(encodedPrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	encodedPublicKey, err := base64.StdEncoding.DecodeString(properties[ltpaPublicKeyProperty])
+	if err != nil {
+		return nil, fmt.Errorf("the LTPA public key is not base64 encoded: %w", err)
+	}
+	if !bytes.Equal(encodedPublicKey, encodeLTPAPublicKey(&privateKey.PublicKey)) {
+		return nil, errors.New("the LTPA public key does not match the private key")
+	}
+	return &LTPAKeys{SharedKey: sharedKey, PrivateKey: privateKey, Realm: properties[ltpaRealmProperty]}, nil
+}
+
+// The keys are encrypted with 3DES in ECB mode. The 3DES key is the SHA-1 digest of the password padded with zeros to 24 bytes.
+func ltpaKeyCipher(password string) (cipher.Block, error) {
+	digest := sha1.Sum([]byte(password))
+	key := make([]byte, 24)
+	copy(key, digest[:])
+	return des.NewTripleDESCipher(key)
+}
+
+func encryptLTPAKey(password string, key []byte) ([]byte, error) {
+	block, err := ltpaKeyCipher(password)
+	if err != nil {
+		return nil, err
+	}
+	encrypted := pkcs5Pad(key, block.BlockSize())
+	for i := 0; i < len(encrypted); i += block.BlockSize() {
+		block.Encrypt(encrypted[i:], encrypted[i:])
+	}
+	return encrypted, nil
+}
+
+func decryptLTPAKeyProperty(password string, value string) ([]byte, error) {
+	encrypted, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	block, err := ltpaKeyCipher(password)
+	if err != nil {
+		return nil, err
+	}
+	if len(encrypted) == 0 || len(encrypted)%block.BlockSize() != 0 {
+		return nil, errors.New("invalid length")
+	}
+	decrypted := make([]byte, len(encrypted))
+	for i := 0; i < len(encrypted); i += block.BlockSize() {
+		block.Decrypt(decrypted[i:], encrypted[i:])
+	}
+	return pkcs5Unpad(decrypted, block.BlockSize())
+}
+
+// The public key is the modulus followed by the public exponent
+func encodeLTPAPublicKey(publicKey *rsa.PublicKey) []byte {
+	return append(javaBytes(publicKey.N), javaBytes(big.NewInt(int64(publicKey.E)))...)
+}
+
+// The private key is the length of the private exponent, the private exponent, the public exponent and the two primes
+func encodeLTPAPrivateKey(privateKey *rsa.PrivateKey) []byte {
+	d := javaBytes(privateKey.D)
+	encoded := binary.BigEndian.AppendUint32(nil, uint32(len(d)))
+	encoded = append(encoded, d...)
+	encoded = append(encoded, javaBytes(big.NewInt(int64(privateKey.E)))...)
+	encoded = append(encoded, javaBytes(privateKey.Primes[0])...)
+	return append(encoded, javaBytes(privateKey.Primes[1])...)
+}
+
+// The public exponent is always 65537, which is encoded in 3 bytes, and the two primes have the same length
+func decodeLTPAPrivateKey(encoded []byte) (*rsa.PrivateKey, error) {
+	const publicExponentLength = 3
+	malformed := errors.New("the LTPA private key is malformed")
+	if len(encoded) < 4 {
+		return nil, malformed
+	}
+	dLength := int(binary.BigEndian.Uint32(encoded))
+	primesLength := len(encoded) - 4 - dLength - publicExponentLength
+	if dLength <= 0 || primesLength <= 0 || primesLength%2 != 0 {
+		return nil, malformed
+	}
+	offset := 4
+	d := new(big.Int).SetBytes(encoded[offset : offset+dLength])
+	offset += dLength
+	e := new(big.Int).SetBytes(encoded[offset : offset+publicExponentLength])
+	offset += publicExponentLength
+	p := new(big.Int).SetBytes(encoded[offset : offset+primesLength/2])
+	q := new(big.Int).SetBytes(encoded[offset+primesLength/2:])
+	privateKey := &rsa.PrivateKey{
+		PublicKey: rsa.PublicKey{N: new(big.Int).Mul(p, q), E: int(e.Int64())},
+		D:         d,
+		Primes:    []*big.Int{p, q},
+	}
+	if err := privateKey.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %w", malformed, err)
+	}
+	privateKey.Precompute()
+	return privateKey, nil
+}
+
+// Returns the bytes of java.math.BigInteger.toByteArray() for a positive number, which has a leading zero byte when the high bit is set
+func javaBytes(n *big.Int) []byte {
+	b := n.Bytes()
+	if len(b) == 0 || b[0]&0x80 != 0 {
+		return append([]byte{0}, b...)
+	}
+	return b
+}
+
+// Writes the properties in the format of java.util.Properties.store()
+func writeProperties(comment string, date time.Time, properties map[string]string) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "#%s\n#%s\n", comment, date.Format(javaDateFormat))
+	for _, key := range ltpaKeysFileProperties {
+		if value, found := properties[key]; found {
+			fmt.Fprintf(&buf, "%s=%s\n", escapeProperty(key), escapeProperty(value))
+		}
+	}
+	return buf.Bytes()
+}
+
+func escapeProperty(s string) string {
+	var sb strings.Builder
+	for _, c := range s {
+		switch c {
+		case '\\', '=', ':', '#', '!':
+			sb.WriteRune('\\')
+			sb.WriteRune(c)
+		case '\t':
+			sb.WriteString(`\t`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		case '\f':
+			sb.WriteString(`\f`)
+		default:
+			sb.WriteRune(c)
+		}
+	}
+	return sb.String()
+}
+
+// Reads the properties written by java.util.Properties.store(). Line continuations and unicode escapes are not used in LTPA keys files.
+func readProperties(data []byte) (map[string]string, error) {
+	properties := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimLeft(scanner.Text(), " \t\f")
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+		key, value, escaped := strings.Builder{}, strings.Builder{}, false
+		inKey := true
+		for _, c := range strings.TrimRight(line, "\r") {
+			target := &value
+			if inKey {
+				target = &key
+			}
+			switch {
+			case escaped:
+				escaped = false
+				switch c {
+				case 't':
+					c = '\t'
+				case 'n':
+					c = '\n'
+				case 'r':
+					c = '\r'
+				case 'f':
+					c = '\f'
+				}
+				target.WriteRune(c)
+			case c == '\\':
+				escaped = true
+			case inKey && (c == '=' || c == ':'):
+				inKey = false
+			default:
+				target.WriteRune(c)
+			}
+		}
+		properties[strings.TrimSpace(key.String())] = strings.TrimSpace(value.String())
+	}
+	return properties, scanner.Err()
+}
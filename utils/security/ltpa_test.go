@@ -0,0 +1,117 @@
+package security
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCreateLTPAKeys(t *testing.T) {
+	created := time.Date(2026, time.October, 16, 9, 5, 30, 0, time.UTC)
+	data, err := createLTPAKeys(rand.Reader, "ltpa-password", created)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	properties, err := readProperties(data)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	keys, err := ParseLTPAKeys(data, "ltpa-password")
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	_, wrongPasswordErr := ParseLTPAKeys(data, "other-password")
+	_, missingKeyErr := ParseLTPAKeys(bytes.ReplaceAll(data, []byte(ltpaPublicKeyProperty), []byte("other")), "ltpa-password")
+	otherData, err := CreateLTPAKeys("ltpa-password")
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	otherKeys, err := ParseLTPAKeys(otherData, "ltpa-password")
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	tests := []Test{
+		{"lines", 9, len(lines)},
+		{"header", "#IBM WebSphere Application Server key file", lines[0]},
+		{"date comment", "#Fri Oct 16 09:05:30 UTC 2026", lines[1]},
+		{"escaped creation date", `com.ibm.websphere.CreationDate=Fri Oct 16 09\:05\:30 UTC 2026`, lines[2]},
+		{"creation date", "Fri Oct 16 09:05:30 UTC 2026", properties[ltpaCreationDateProperty]},
+		{"version", "1.0", properties[ltpaVersionProperty]},
+		{"host", "localhost", properties[ltpaCreationHostProperty]},
+		{"realm", "defaultRealm", keys.Realm},
+		{"escaped shared key padding", true, strings.HasSuffix(lines[4], `\=`)},
+		{"encrypted shared key length", 44, len(properties[ltpaSharedKeyProperty])},
+		{"public key length", 176, len(properties[ltpaPublicKeyProperty])},
+		{"shared key length", 24, len(keys.SharedKey)},
+		{"modulus bits", 1024, keys.PrivateKey.N.BitLen()},
+		{"public exponent", 65537, keys.PrivateKey.E},
+		{"wrong password", true, wrongPasswordErr != nil},
+		{"missing public key", true, missingKeyErr != nil},
+		{"random shared key", false, bytes.Equal(keys.SharedKey, otherKeys.SharedKey)},
+		{"random key pair", false, keys.PrivateKey.N.Cmp(otherKeys.PrivateKey.N) == 0},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+// The LTPA keys file is the output of the Liberty securityUtility createLTPAKeys command
+func TestParseSecurityUtilityLTPAKeys(t *testing.T) {
+	const ltpaPassword = "ltpa-password"
+	data := readSecurityUtilityTestdata(t, "ltpa.keys")
+	properties, err := readProperties(data)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	keys, err := ParseLTPAKeys(data, ltpaPassword)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	encodedPrivateKey, err := decryptLTPAKeyProperty(ltpaPassword, properties[ltpaPrivateKeyProperty])
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	encryptedSharedKey, err := encryptLTPAKey(ltpaPassword, keys.SharedKey)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	_, wrongPasswordErr := ParseLTPAKeys(data, "other-password")
+
+	tests := []Test{
+		{"realm", ltpaDefaultRealm, keys.Realm},
+		{"version", ltpaVersion, properties[ltpaVersionProperty]},
+		{"shared key length", ltpaSharedKeySize, len(keys.SharedKey)},
+		{"modulus bits", ltpaRSAKeyBits, keys.PrivateKey.N.BitLen()},
+		{"public exponent", 65537, keys.PrivateKey.E},
+		{"encrypted shared key", properties[ltpaSharedKeyProperty], base64.StdEncoding.EncodeToString(encryptedSharedKey)},
+		{"private key layout", encodedPrivateKey, encodeLTPAPrivateKey(keys.PrivateKey)},
+		{"public key layout", properties[ltpaPublicKeyProperty], base64.StdEncoding.EncodeToString(encodeLTPAPublicKey(&keys.PrivateKey.PublicKey))},
+		{"wrong password", true, wrongPasswordErr != nil},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestReadProperties(t *testing.T) {
+	properties, err := readProperties([]byte("#comment\n! other comment\n\nkey=value\\=\nescaped\\:key : a\\:b\\\\c\nempty=\n"))
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	tests := []Test{
+		{"value", "value=", properties["key"]},
+		{"escaped key", `a:b\c`, properties["escaped:key"]},
+		{"empty", "", properties["empty"]},
+		{"count", 3, len(properties)},
+		{"escape", `a\:b\\c\=`, escapeProperty(`a:b\c=`)},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
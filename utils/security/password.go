@@ -0,0 +1,243 @@
+package security
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Password encoding types, matching the --encoding option of the Liberty securityUtility encode command
+const (
+	EncodingXOR    = "xor"
+	EncodingAES    = "aes"
+	EncodingAES128 = "aes-128"
+)
+
+// Liberty derives the AES keys from this key string when no key is configured
+const defaultAESKeyString = "WebAS"
+
+// Key derivation parameters of the Liberty AES password cipher
+const aesKeyIterations = 84756
+
+var aesKeySalt = []byte{0xa7, 0xa2, 0x83, 0x39, 0x4c, 0x5a, 0xb3, 0x4f, 0x32, 0x15, 0x0a, 0x9e, 0x2f, 0x17, 0x11, 0x38, 0xc3, 0x2e, 0x7d, 0x80}
+
+// The first byte of an AES encoded password identifies the cipher that encrypted it
+const (
+	aes128Version byte = 0
+	aes256Version byte = 1
+)
+
+// The {xor} encoding XORs every byte of the password with this value
+const xorMask = '_'
+
+// Encode encodes the password in the same format as the Liberty securityUtility encode command.
+// For the AES encodings, base64Key is a base64 encoded AES-256 key and takes precedence over key, which is a key string that the AES key is derived from.
+func Encode(password string, encoding string, key string, base64Key string) (string, error) {
+	return encode(rand.Reader, password, encoding, key, base64Key)
+}
+
+func encode(random io.Reader, password string, encoding string, key string, base64Key string) (string, error) {
+	switch encoding {
+	case EncodingXOR:
+		return "{xor}" + base64.StdEncoding.EncodeToString(xor([]byte(password))), nil
+	case EncodingAES, EncodingAES128:
+		aesKey, err := getAESKey(encoding, key, base64Key)
+		if err != nil {
+			return "", err
+		}
+		var encrypted []byte
+		if encoding == EncodingAES128 {
+			encrypted, err = encryptAES128(random, aesKey, []byte(password))
+		} else {
+			encrypted, err = encryptAES256(random, aesKey, []byte(password))
+		}
+		if err != nil {
+			return "", err
+		}
+		return "{aes}" + base64.StdEncoding.EncodeToString(encrypted), nil
+	}
+	return "", fmt.Errorf("unsupported password encoding type %q", encoding)
+}
+
+// Decode returns the password of a value encoded by Encode or by the Liberty securityUtility encode command.
+// A value without an encoding prefix is returned unchanged.
+func Decode(encoded string, key string, base64Key string) (string, error) {
+	switch {
+	case strings.HasPrefix(encoded, "{xor}"):
+		data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(encoded, "{xor}"))
+		if err != nil {
+			return "", err
+		}
+		return string(xor(data)), nil
+	case strings.HasPrefix(encoded, "{aes}"):
+		data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(encoded, "{aes}"))
+		if err != nil {
+			return "", err
+		}
+		if len(data) == 0 {
+			return "", errors.New("the encoded password is empty")
+		}
+		var password []byte
+		switch data[0] {
+		case aes128Version:
+			aesKey, err := getAESKey(EncodingAES128, key, base64Key)
+			if err != nil {
+				return "", err
+			}
+			password, err = decryptAES128(aesKey, data)
+			if err != nil {
+				return "", err
+			}
+		case aes256Version:
+			aesKey, err := getAESKey(EncodingAES, key, base64Key)
+			if err != nil {
+				return "", err
+			}
+			password, err = decryptAES256(aesKey, data)
+			if err != nil {
+				return "", err
+			}
+		default:
+			return "", fmt.Errorf("unsupported AES password version %d", data[0])
+		}
+		return string(password), nil
+	case strings.HasPrefix(encoded, "{"):
+		return "", fmt.Errorf("unsupported password encoding in %q", encoded[:strings.Index(encoded, "}")+1])
+	}
+	return encoded, nil
+}
+
+func xor(data []byte) []byte {
+	out := make([]byte, len(data))
+	for i, b := range data {
+		out[i] = b ^ xorMask
+	}
+	return out
+}
+
+// Returns the AES key of the encoding type. A base64 key is only used by the AES-256 encoding.
+func getAESKey(encoding string, key string, base64Key string) ([]byte, error) {
+	if encoding == EncodingAES && base64Key != "" {
+		aesKey, err := base64.StdEncoding.DecodeString(base64Key)
+		if err != nil {
+			return nil, fmt.Errorf("the AES key is not base64 encoded: %w", err)
+		}
+		if len(aesKey) != 32 {
+			return nil, fmt.Errorf("the AES key must be 32 bytes long, found %d bytes", len(aesKey))
+		}
+		return aesKey, nil
+	}
+	if key == "" {
+		key = defaultAESKeyString
+	}
+	if encoding == EncodingAES128 {
+		return pbkdf2.Key(sha1.New, key, aesKeySalt, aesKeyIterations, 16)
+	}
+	return pbkdf2.Key(sha512.New, key, aesKeySalt, aesKeyIterations, 32)
+}
+
+// AES-128 encoded passwords are the version byte followed by the AES-CBC encryption of a random length-prefixed seed and the password.
+// The key is also used as the IV, so the seed makes every encoding of the same password different.
+func encryptAES128(random io.Reader, aesKey []byte, password []byte) ([]byte, error) {
+	seedLength := make([]byte, 1)
+	if _, err := io.ReadFull(random, seedLength); err != nil {
+		return nil, err
+	}
+	seed := make([]byte, int(seedLength[0])%20+1)
+	if _, err := io.ReadFull(random, seed); err != nil {
+		return nil, err
+	}
+	plaintext := append(append([]byte{byte(len(seed))}, seed...), password...)
+	encrypted, err := encryptCBC(aesKey, aesKey, plaintext)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{aes128Version}, encrypted...), nil
+}
+
+func decryptAES128(aesKey []byte, data []byte) ([]byte, error) {
+	decrypted, err := decryptCBC(aesKey, aesKey, data[1:])
+	if err != nil {
+		return nil, err
+	}
+	if len(decrypted) == 0 || int(decrypted[0]) >= len(decrypted) {
+		return nil, errors.New("the AES encoded password is malformed")
+	}
+	return decrypted[1+int(decrypted[0]):], nil
+}
+
+// AES-256 encoded passwords are the version byte and the length of the IV, followed by a random IV and the AES-CBC encryption of the password
+func encryptAES256(random io.Reader, aesKey []byte, password []byte) ([]byte, error) {
+	iv := make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(random, iv); err != nil {
+		return nil, err
+	}
+	encrypted, err := encryptCBC(aesKey, iv, password)
+	if err != nil {
+		return nil, err
+	}
+	out := append([]byte{aes256Version, byte(len(iv))}, iv...)
+	return append(out, encrypted...), nil
+}
+
+func decryptAES256(aesKey []byte, data []byte) ([]byte, error) {
+	if len(data) < 2 || int(data[1]) != aes.BlockSize || len(data) < 2+aes.BlockSize {
+		return nil, errors.New("the AES encoded password is malformed")
+	}
+	iv := data[2 : 2+aes.BlockSize]
+	return decryptCBC(aesKey, iv, data[2+aes.BlockSize:])
+}
+
+func encryptCBC(key []byte, iv []byte, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	padded := pkcs5Pad(plaintext, block.BlockSize())
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(padded, padded)
+	return padded, nil
+}
+
+func decryptCBC(key []byte, iv []byte, ciphertext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) == 0 || len(ciphertext)%block.BlockSize() != 0 {
+		return nil, errors.New("the AES encoded password is malformed")
+	}
+	decrypted := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(decrypted, ciphertext)
+	return pkcs5Unpad(decrypted, block.BlockSize())
+}
+
+func pkcs5Pad(data []byte, blockSize int) []byte {
+	padding := blockSize - len(data)%blockSize
+	return append(bytes.Clone(data), bytes.Repeat([]byte{byte(padding)}, padding)...)
+}
+
+// Wrong keys are reported here, since decrypting with another key almost never produces valid padding
+func pkcs5Unpad(data []byte, blockSize int) ([]byte, error) {
+	if len(data) == 0 || len(data)%blockSize != 0 {
+		return nil, errors.New("invalid padding")
+	}
+	padding := int(data[len(data)-1])
+	if padding == 0 || padding > blockSize || padding > len(data) {
+		return nil, errors.New("invalid padding, the key may be incorrect")
+	}
+	for _, b := range data[len(data)-padding:] {
+		if int(b) != padding {
+			return nil, errors.New("invalid padding, the key may be incorrect")
+		}
+	}
+	return data[:len(data)-padding], nil
+}
@@ -0,0 +1,144 @@
+package security
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type Test struct {
+	test     string
+	expected interface{}
+	actual   interface{}
+}
+
+func verifyTests(tests []Test) error {
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.actual, tt.expected) {
+			return fmt.Errorf("%s test expected: (%v) actual: (%v)", tt.test, tt.expected, tt.actual)
+		}
+	}
+	return nil
+}
+
+// The values encoded by the Liberty securityUtility command in testdata, which are written by scripts/generate-security-testdata.sh
+const (
+	securityUtilityPassword  = "Pa55w0rd-with-a-longer-value-than-one-block"
+	securityUtilityKey       = "my-key"
+	securityUtilityBase64Key = "BwcHBwcHBwcHBwcHBwcHBwcHBwcHBwcHBwcHBwcHBwc="
+)
+
+// Returns the content of a file written by scripts/generate-security-testdata.sh. The test fails if the file has not been generated, since these files are
+// the only check that Liberty accepts the passwords and LTPA keys created by the operator.
+func readSecurityUtilityTestdata(t *testing.T, name string) []byte {
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("testdata/%s not found, run 'make security-testdata' to generate it", name)
+	}
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	return data
+}
+
+func mustEncode(t *testing.T, password string, encoding string, key string, base64Key string) string {
+	encoded, err := Encode(password, encoding, key, base64Key)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	return encoded
+}
+
+func mustDecode(t *testing.T, encoded string, key string, base64Key string) string {
+	password, err := Decode(encoded, key, base64Key)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	return password
+}
+
+// The {xor} values are the output of the Liberty securityUtility encode command
+func TestEncodeXOR(t *testing.T) {
+	tests := []Test{
+		{"password", "{xor}Lz4sLCgwLTs=", mustEncode(t, "password", EncodingXOR, "", "")},
+		{"empty", "{xor}", mustEncode(t, "", EncodingXOR, "", "")},
+		{"decode", "password", mustDecode(t, "{xor}Lz4sLCgwLTs=", "", "")},
+		{"decode without encoding", "password", mustDecode(t, "password", "", "")},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestEncodeAES(t *testing.T) {
+	base64Key := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, 32))
+	password := "Pa55w0rd-with-a-longer-value-than-one-block"
+
+	aes256 := mustEncode(t, password, EncodingAES, "", "")
+	aes128 := mustEncode(t, password, EncodingAES128, "", "")
+	aes256Key := mustEncode(t, password, EncodingAES, "my-key", "")
+	aes128Key := mustEncode(t, password, EncodingAES128, "my-key", "")
+	aes256Base64Key := mustEncode(t, password, EncodingAES, "my-key", base64Key)
+
+	decoded256, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(aes256, "{aes}"))
+	decoded128, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(aes128, "{aes}"))
+	_, wrongKeyErr := Decode(aes256Key, "other-key", "")
+	_, wrongBase64KeyErr := Decode(aes256Base64Key, "my-key", "")
+	_, invalidBase64KeyErr := Encode(password, EncodingAES, "", "c2hvcnQ=")
+	_, unsupportedErr := Encode(password, "hash", "", "")
+
+	tests := []Test{
+		{"aes prefix", true, strings.HasPrefix(aes256, "{aes}")},
+		{"aes version", aes256Version, decoded256[0]},
+		{"aes-128 version", aes128Version, decoded128[0]},
+		{"aes is salted", false, aes256 == mustEncode(t, password, EncodingAES, "", "")},
+		{"aes-128 is salted", false, aes128 == mustEncode(t, password, EncodingAES128, "", "")},
+		{"aes default key", password, mustDecode(t, aes256, "", "")},
+		{"aes-128 default key", password, mustDecode(t, aes128, "", "")},
+		{"aes key string", password, mustDecode(t, aes256Key, "my-key", "")},
+		{"aes-128 key string", password, mustDecode(t, aes128Key, "my-key", "")},
+		{"aes base64 key", password, mustDecode(t, aes256Base64Key, "", base64Key)},
+		{"aes-128 ignores the base64 key", password, mustDecode(t, mustEncode(t, password, EncodingAES128, "my-key", base64Key), "my-key", base64Key)},
+		{"aes short password", "a", mustDecode(t, mustEncode(t, "a", EncodingAES, "", ""), "", "")},
+		{"aes-128 empty password", "", mustDecode(t, mustEncode(t, "", EncodingAES128, "", ""), "", "")},
+		{"wrong key string", true, wrongKeyErr != nil},
+		{"wrong base64 key", true, wrongBase64KeyErr != nil},
+		{"invalid base64 key", true, invalidBase64KeyErr != nil},
+		{"unsupported encoding", true, unsupportedErr != nil},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+// The {aes} values are the output of the Liberty securityUtility encode command
+func TestDecodeSecurityUtilityAES(t *testing.T) {
+	aes256 := strings.TrimSpace(string(readSecurityUtilityTestdata(t, "aes-default.txt")))
+	aes256Key := strings.TrimSpace(string(readSecurityUtilityTestdata(t, "aes-key.txt")))
+	aes256Base64Key := strings.TrimSpace(string(readSecurityUtilityTestdata(t, "aes-base64key.txt")))
+	aes128 := strings.TrimSpace(string(readSecurityUtilityTestdata(t, "aes128-default.txt")))
+	aes128Key := strings.TrimSpace(string(readSecurityUtilityTestdata(t, "aes128-key.txt")))
+
+	decoded256, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(aes256, "{aes}"))
+	decoded128, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(aes128, "{aes}"))
+
+	tests := []Test{
+		{"aes version", aes256Version, decoded256[0]},
+		{"aes-128 version", aes128Version, decoded128[0]},
+		{"aes default key", securityUtilityPassword, mustDecode(t, aes256, "", "")},
+		{"aes key string", securityUtilityPassword, mustDecode(t, aes256Key, securityUtilityKey, "")},
+		{"aes base64 key", securityUtilityPassword, mustDecode(t, aes256Base64Key, "", securityUtilityBase64Key)},
+		{"aes-128 default key", securityUtilityPassword, mustDecode(t, aes128, "", "")},
+		{"aes-128 key string", securityUtilityPassword, mustDecode(t, aes128Key, securityUtilityKey, "")},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
@@ -0,0 +1,166 @@
+package socket
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	_ "unsafe"
+
+	"github.com/OpenLiberty/open-liberty-operator/utils"
+	"github.com/OpenLiberty/open-liberty-operator/utils/collector"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/kubectl/pkg/scheme"
+)
+
+//go:linkname cpMakeTar k8s.io/kubectl/pkg/cmd/cp.makeTar
+func cpMakeTar(srcPath, destPath string, writer io.Writer) error
+
+// CopyAndRunCollector copies the assets of the collector to the pod and runs the collector, calling doneCallback with the
+// output of the collector once it completes
+func CopyAndRunCollector(restConfig *rest.Config, c collector.Collector, podName string, podNamespace string, encodedAttrs string, dirName string, doneCallback func(string, string, error)) (*io.PipeReader, *io.PipeWriter, context.CancelFunc, error) {
+	containerName := "app"
+	sourceFolder := c.AssetsFolder()
+	destFolder := "$WLP_OUTPUT_DIR/helper"
+	runCmd := collector.GetRunCmd(c, encodedAttrs, podName, podNamespace, dirName)
+	return CopyFolderToPodAndRunScript(restConfig, sourceFolder, destFolder, podName, podNamespace, containerName, runCmd, func(stdout string, stderr string, err error) {
+		var scriptErr *scriptError
+		if errors.As(err, &scriptErr) {
+			err = collector.GetError(c, scriptErr.err)
+		}
+		doneCallback(stdout, stderr, err)
+	})
+}
+
+// ReattachCollector waits for a run of the collector that was started before the operator restarted, calling doneCallback
+// with the output of the collector once it completes
+func ReattachCollector(restConfig *rest.Config, c collector.Collector, podName string, podNamespace string, dirName string, doneCallback func(string, string, error)) (context.CancelFunc, error) {
+	clientset, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create Clientset: %v", err.Error())
+	}
+	exec, err := remotecommand.NewSPDYExecutor(restConfig, "POST", podExec(clientset, podName, podNamespace, "app", false, []string{"/bin/sh", "-c", collector.GetWaitCmd(podName, podNamespace, dirName)}).URL())
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create SPDY Executor: %v", err)
+	}
+	streamContext, cancelStreamContext := context.WithCancel(context.TODO())
+	go func() {
+		var stdout, stderr bytes.Buffer
+		err := exec.StreamWithContext(streamContext, remotecommand.StreamOptions{
+			Stdout: &stdout,
+			Stderr: &stderr,
+			Tty:    false,
+		})
+		if err != nil {
+			err = collector.GetError(c, err)
+		}
+		doneCallback(stdout.String(), stderr.String(), err)
+	}()
+	return cancelStreamContext, nil
+}
+
+func podExec(clientset *kubernetes.Clientset, podName, podNamespace, containerName string, usingStdin bool, command []string) *rest.Request {
+	return clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Name(podName).
+		Namespace(podNamespace).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Command:   command,
+			Container: containerName,
+			Stdin:     usingStdin,
+			Stdout:    true,
+			Stderr:    true,
+			TTY:       false,
+		}, scheme.ParameterCodec)
+}
+
+// The error of the script run by CopyFolderToPodAndRunScript
+type scriptError struct {
+	err error
+}
+
+func (e *scriptError) Error() string {
+	return e.err.Error()
+}
+
+func (e *scriptError) Unwrap() error {
+	return e.err
+}
+
+// CopyFolderToPodAndRunScript copies srcFolder to destFolder in the container, unless srcFolder is "", and runs scriptCmd,
+// calling doneCallback with the output of scriptCmd once it completes. The error of scriptCmd is a *scriptError.
+func CopyFolderToPodAndRunScript(config *rest.Config, srcFolder string, destFolder string, podName, podNamespace, containerName, scriptCmd string, doneCallback func(string, string, error)) (*io.PipeReader, *io.PipeWriter, context.CancelFunc, error) {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("Failed to create Clientset: %v", err.Error())
+	}
+
+	var reader *io.PipeReader
+	var writer *io.PipeWriter
+	if srcFolder != "" {
+		reader, writer = io.Pipe()
+		go func() {
+			defer writer.Close()
+			cpMakeTar(srcFolder, destFolder, writer)
+		}()
+	}
+
+	command := []string{"tar", "-xf", "-"}
+	destDir := path.Dir(destFolder)
+	if destDir != "" {
+		command = append(command, "-C", destDir)
+	}
+	tarCmd := strings.Join(command, utils.FlagDelimiterSpace)
+
+	streamContext, cancelStreamContext := context.WithCancel(context.TODO())
+	go func() {
+		if reader != nil {
+			usingStdin := true
+			exec, err := remotecommand.NewSPDYExecutor(config, "POST", podExec(clientset, podName, podNamespace, containerName, usingStdin, []string{"/bin/sh", "-c", tarCmd}).URL())
+			if err != nil {
+				wrappedErr := fmt.Errorf("Failed to create primary SPDY Executor: %v", err)
+				doneCallback("", "", wrappedErr)
+				return
+			}
+			var stdout, stderr bytes.Buffer
+			err = exec.StreamWithContext(streamContext, remotecommand.StreamOptions{
+				Stdin:  reader,
+				Stdout: &stdout,
+				Stderr: &stderr,
+				Tty:    false,
+			})
+			if err != nil {
+				wrappedErr := fmt.Errorf("Failed to create primary StreamWithContext: %v", err)
+				doneCallback(stdout.String(), stderr.String(), wrappedErr)
+				return
+			}
+		}
+
+		usingStdin := false
+		exec, err := remotecommand.NewSPDYExecutor(config, "POST", podExec(clientset, podName, podNamespace, containerName, usingStdin, []string{"/bin/sh", "-c", scriptCmd}).URL())
+		if err != nil {
+			wrappedErr := fmt.Errorf("Failed to create secondary SPDY Executor: %v", err)
+			doneCallback("", "", wrappedErr)
+			return
+		}
+		var stdout, stderr bytes.Buffer
+		err = exec.StreamWithContext(streamContext, remotecommand.StreamOptions{
+			Stdout: &stdout,
+			Stderr: &stderr,
+			Tty:    false,
+		})
+		if err != nil {
+			err = &scriptError{err: err}
+		}
+		doneCallback(stdout.String(), stderr.String(), err)
+	}()
+	return reader, writer, cancelStreamContext, nil
+}
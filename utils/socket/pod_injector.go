@@ -1,17 +1,18 @@
 package socket
 
 import (
-	"bufio"
 	"context"
 	"fmt"
 	"io"
 	"net"
 	"os"
 	"strconv"
-	"strings"
 	"sync"
+	"time"
 
 	"github.com/OpenLiberty/open-liberty-operator/utils"
+	"github.com/OpenLiberty/open-liberty-operator/utils/collector"
+	"github.com/OpenLiberty/open-liberty-operator/utils/metrics"
 	"github.com/go-logr/logr"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 )
@@ -35,21 +36,31 @@ const (
 	PodInjectorStatusClosed                          PodInjectorStatusResponse = "closed..."
 	PodInjectorStatusNotFound                        PodInjectorStatusResponse = "notfound..."
 	PodInjectorStatusTooManyWorkers                  PodInjectorStatusResponse = "toomanyworkers..."
+	PodInjectorStatusError                           PodInjectorStatusResponse = "error..."
+	PodInjectorStatusFileName                        PodInjectorStatusResponse = "name..."
 )
 
 const (
-	MinWorkers         = 1
-	MaxWorkers         = 100
-	BuildMessageLength = 5
+	MinWorkers = 1
+	MaxWorkers = 100
+	// The time a client waits for the response to a request
+	responseTimeout = 30 * time.Second
+	// The time after which a pod that stopped polling for a worker leaves the queue, for example because the pod or its
+	// OpenLibertyPerformanceData was deleted while it was waiting
+	waitingPodTimeout = time.Minute
 )
 
 var (
-	mutex             = &sync.Mutex{}
-	workers           = []Worker{}
+	mutex   = &sync.Mutex{}
+	workers = []Worker{}
+	// The pods that were told to wait for a worker, which are the queue of the pod injector, and the time they last polled
+	waitingPods       = map[string]time.Time{}
 	completedPods     = &sync.Map{}
 	erroringPods      = &sync.Map{}
 	linperfFileNames  = &sync.Map{}
+	podStates         = &sync.Map{}
 	currentMaxWorkers = 10
+	store             *stateStore
 )
 
 type Worker struct {
@@ -62,6 +73,7 @@ type Worker struct {
 type Client struct {
 	conn   net.Conn
 	logger logr.Logger
+	nextID uint64
 }
 
 func (c *Client) Connect() error {
@@ -70,71 +82,67 @@ func (c *Client) Connect() error {
 		return err
 	}
 	c.conn = conn
+	c.nextID = 0
 	return nil
 }
 
-func buildMessage(podName, podNamespace, scriptName, podInjectorAction, payload string) []byte {
-	return []byte(fmt.Sprintf("%s:%s:%s:%s:%s\n", podName, podNamespace, scriptName, podInjectorAction, payload))
-}
-
-func (c *Client) PollStatus(scriptName, podName, podNamespace, encodedAttrs string) string {
+// Sends a request and returns its ID
+func (c *Client) send(scriptName, podName, podNamespace, podInjectorAction, payload string) (uint64, error) {
 	if c.conn == nil {
-		return string(PodInjectorStatusClosed)
+		return 0, fmt.Errorf("the connection is closed")
 	}
-	output := make(chan string, 1)
-	var wg sync.WaitGroup
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		scanner := bufio.NewScanner(c.conn)
-		for scanner.Scan() {
-			msg := scanner.Text()
-			c.logger.Info(fmt.Sprintf("PollStatus: Received message: %s", msg))
-			output <- msg
-			break
-		}
-	}()
-	c.conn.Write(buildMessage(podName, podNamespace, scriptName, PodInjectorActionStatus, encodedAttrs))
-	wg.Wait()
-	return <-output
+	c.nextID++
+	req := &request{
+		Version:      protocolVersion,
+		ID:           c.nextID,
+		PodName:      podName,
+		PodNamespace: podNamespace,
+		Tool:         scriptName,
+		Action:       podInjectorAction,
+		Payload:      payload,
+	}
+	return req.ID, writeFrame(c.conn, req)
 }
 
-func (c *Client) PollLinperfFileName(scriptName, podName, podNamespace, attrs string) string {
-	if c.conn == nil {
+// Sends a request and waits for its response, skipping the responses to earlier requests that were not waited for
+func (c *Client) roundTrip(scriptName, podName, podNamespace, podInjectorAction, payload string) string {
+	id, err := c.send(scriptName, podName, podNamespace, podInjectorAction, payload)
+	if err != nil {
+		c.logger.Info(fmt.Sprintf("%s: Failed to send message: %v", podInjectorAction, err))
 		return string(PodInjectorStatusClosed)
 	}
-	output := make(chan string, 1)
-	var wg sync.WaitGroup
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		scanner := bufio.NewScanner(c.conn)
-		for scanner.Scan() {
-			msg := scanner.Text()
-			c.logger.Info(fmt.Sprintf("PollLinperfFileName: Received message: %s", msg))
-			output <- msg
-			break
+	c.conn.SetReadDeadline(time.Now().Add(responseTimeout))
+	defer c.conn.SetReadDeadline(time.Time{})
+	for {
+		res, err := readResponse(c.conn)
+		if err != nil {
+			c.logger.Info(fmt.Sprintf("%s: Failed to receive message: %v", podInjectorAction, err))
+			return string(PodInjectorStatusClosed)
 		}
-	}()
-	c.conn.Write(buildMessage(podName, podNamespace, scriptName, PodInjectorActionLinperfFileName, attrs))
-	wg.Wait()
-	return <-output
+		if res.ID != id {
+			continue
+		}
+		msg := res.String()
+		c.logger.Info(fmt.Sprintf("%s: Received message: %s", podInjectorAction, msg))
+		return msg
+	}
 }
 
-func (c *Client) write(msg []byte) {
-	if c.conn == nil {
-		return
-	}
-	c.conn.Write(msg)
+func (c *Client) PollStatus(scriptName, podName, podNamespace, encodedAttrs string) string {
+	return c.roundTrip(scriptName, podName, podNamespace, PodInjectorActionStatus, encodedAttrs)
+}
+
+func (c *Client) PollLinperfFileName(scriptName, podName, podNamespace, attrs string) string {
+	return c.roundTrip(scriptName, podName, podNamespace, PodInjectorActionLinperfFileName, attrs)
 }
 
 func (c *Client) StartScript(scriptName, podName, podNamespace, attrs string) bool {
-	c.write(buildMessage(podName, podNamespace, scriptName, PodInjectorActionStart, attrs))
-	return true
+	_, err := c.send(scriptName, podName, podNamespace, PodInjectorActionStart, attrs)
+	return err == nil
 }
 
 func (c *Client) CompleteScript(scriptName, podName, podNamespace, attrs string) {
-	c.write(buildMessage(podName, podNamespace, scriptName, PodInjectorActionComplete, attrs))
+	c.send(scriptName, podName, podNamespace, PodInjectorActionComplete, attrs)
 }
 
 func (c *Client) CloseConnection() {
@@ -145,25 +153,7 @@ func (c *Client) CloseConnection() {
 }
 
 func (c *Client) SetMaxWorkers(scriptName, podName, podNamespace, maxWorkers string) bool {
-	if c.conn == nil {
-		return false
-	}
-	output := make(chan string, 1)
-	var wg sync.WaitGroup
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		scanner := bufio.NewScanner(c.conn)
-		for scanner.Scan() {
-			msg := scanner.Text()
-			c.logger.Info(fmt.Sprintf("SetMaxWorkers: Received message: %s", msg))
-			output <- msg
-			break
-		}
-	}()
-	c.conn.Write(buildMessage(podName, podNamespace, scriptName, PodInjectorActionSetMaxWorkers, maxWorkers))
-	wg.Wait()
-	return <-output == string(PodInjectorStatusUpdateMaxWorkersSuccess)
+	return c.roundTrip(scriptName, podName, podNamespace, PodInjectorActionSetMaxWorkers, maxWorkers) == string(PodInjectorStatusUpdateMaxWorkersSuccess)
 }
 
 func GetPodInjectorClient(logger logr.Logger) *Client {
@@ -175,6 +165,9 @@ func GetPodInjectorClient(logger logr.Logger) *Client {
 var _ utils.PodInjectorClient = (*Client)(nil)
 
 func ServePodInjector(mgr manager.Manager, logger logr.Logger) (net.Listener, error) {
+	store = newStateStore(mgr.GetClient(), mgr.GetAPIReader(), logger)
+	restoreWorkers(mgr, logger)
+
 	os.Remove(podInjectorSocketPath)
 	logger.Info(fmt.Sprintf("Creating socket at path: %s", podInjectorSocketPath))
 	listener, err := net.Listen("unix", podInjectorSocketPath)
@@ -182,6 +175,15 @@ func ServePodInjector(mgr manager.Manager, logger logr.Logger) (net.Listener, er
 		return nil, err
 	}
 
+	// expire the pods that stopped waiting for a worker even when no other pod polls the pod injector
+	go func() {
+		for range time.Tick(waitingPodTimeout) {
+			mutex.Lock()
+			updateWorkerMetrics()
+			mutex.Unlock()
+		}
+	}()
+
 	go func() {
 		for {
 			conn, err := listener.Accept()
@@ -195,26 +197,32 @@ func ServePodInjector(mgr manager.Manager, logger logr.Logger) (net.Listener, er
 	return listener, nil
 }
 
-func writeResponse(conn net.Conn, response PodInjectorStatusResponse) {
-	conn.Write([]byte(fmt.Sprintf("%s\n", response)))
+// Writes the response to the request
+func writeResponse(conn net.Conn, req *request, res *response) error {
+	res.Version = protocolVersion
+	res.ID = req.ID
+	return writeFrame(conn, res)
+}
+
+func newResponse(status PodInjectorStatusResponse) *response {
+	return &response{Status: status}
 }
 
 func getPodKey(podName, podNamespace string) string {
 	return fmt.Sprintf("%s:%s", podNamespace, podName)
 }
 
-func processAction(conn net.Conn, mgr manager.Manager, logger logr.Logger, podName, podNamespace, tool, action, encodedAttrs string) {
-	if len(podName) == 0 || len(podNamespace) == 0 {
-		return
-	}
+// Processes the action and returns its response, or nil if the action has no response
+func processAction(mgr manager.Manager, logger logr.Logger, podName, podNamespace, tool, action, encodedAttrs string) *response {
 	podKeyPair := getPodKey(podName, podNamespace)
-	decodedLinperfAttrs := utils.DecodeLinperfAttr(encodedAttrs)
-	podKey := fmt.Sprintf("%s:%s", podKeyPair, decodedLinperfAttrs["uid"])
+	decodedAttrs := collector.DecodeAttrs(encodedAttrs)
+	podKey := fmt.Sprintf("%s:%s", podKeyPair, decodedAttrs["uid"])
 	debugLogSignature := fmt.Sprintf("pod (%s), namespace (%s), active workers: (%d)", podName, podNamespace, len(workers))
 	logger.V(2).Info(fmt.Sprintf("processAction: start: [%s]", debugLogSignature))
 	defer func() {
 		debugLogSignature := fmt.Sprintf("pod (%s), namespace (%s), active workers: (%d)", podName, podNamespace, len(workers))
 		logger.V(2).Info(fmt.Sprintf("processAction: end: [%s]", debugLogSignature))
+		updateWorkerMetrics()
 	}()
 
 	switch action {
@@ -222,8 +230,7 @@ func processAction(conn net.Conn, mgr manager.Manager, logger logr.Logger, podNa
 		desiredWorkers, err := strconv.Atoi(encodedAttrs)
 		// Exit early if desired workers is out of bounds
 		if err != nil || desiredWorkers < MinWorkers || desiredWorkers > MaxWorkers {
-			writeResponse(conn, PodInjectorStatusUpdateMaxWorkersInvalidArgument)
-			return
+			return newResponse(PodInjectorStatusUpdateMaxWorkersInvalidArgument)
 		}
 		// Update currentMaxWorkers as needed
 		if desiredWorkers != currentMaxWorkers {
@@ -231,41 +238,34 @@ func processAction(conn net.Conn, mgr manager.Manager, logger logr.Logger, podNa
 			currentMaxWorkers = max(activeWorkers, desiredWorkers)
 		}
 		if desiredWorkers == currentMaxWorkers {
-			writeResponse(conn, PodInjectorStatusUpdateMaxWorkersSuccess)
+			return newResponse(PodInjectorStatusUpdateMaxWorkersSuccess)
 		} else {
-			writeResponse(conn, PodInjectorStatusUpdateMaxWorkersBusy)
+			return newResponse(PodInjectorStatusUpdateMaxWorkersBusy)
 		}
 	case PodInjectorActionStart:
 		if hasWorker(podKey) {
-			writeResponse(conn, PodInjectorStatusWriting)
-			return
+			return newResponse(PodInjectorStatusWriting)
 		} else if len(workers) >= currentMaxWorkers {
-			writeResponse(conn, PodInjectorStatusTooManyWorkers)
-			return
+			waitingPods[podKey] = time.Now()
+			return newResponse(PodInjectorStatusTooManyWorkers)
+		}
+		delete(waitingPods, podKey)
+		c, err := collector.Get(tool)
+		if err != nil {
+			erroringPods.Store(podKey, err.Error())
+			return &response{Status: PodInjectorStatusError, Message: err.Error()}
 		}
 		completedPods.Store(podKey, false)
 		linperfFileNames.Delete(podKey)
-		reader, writer, cancelContext, err := CopyAndRunLinperf(mgr.GetConfig(), podName, podNamespace, encodedAttrs, func(stdout string, stderr string, err error) {
-			removeWorker(podKey)
-			if err == nil {
-				logger.Info("The linperf script has completed successfully!")
-				logger.Info("> linperf.sh (stdout):")
-				logger.Info(stdout)
-				logger.Info("> linperf.sh (stderr):")
-				logger.Info(stderr)
-				completedPods.Store(podKey, true)
-				fileName := getLinperfDataFileName(stdout)
-				linperfFileNames.Store(podKey, fileName)
-			} else {
-				errMessage := fmt.Sprintf("The performance data collector failed with error: %s", err)
-				logger.Error(err, "The performance data collector failed")
-				logger.Info("> linperf.sh (stdout):")
-				logger.Info(stdout)
-				logger.Info("> linperf.sh (stderr):")
-				logger.Info(stderr)
-				erroringPods.Store(podKey, errMessage)
-			}
-		})
+		state := podState{
+			PodKey:       podKey,
+			PodName:      podName,
+			PodNamespace: podNamespace,
+			Tool:         c.Name(),
+			DirName:      c.DirName(decodedAttrs, time.Now()),
+			Status:       podStateRunning,
+		}
+		reader, writer, cancelContext, err := CopyAndRunCollector(mgr.GetConfig(), c, podName, podNamespace, encodedAttrs, state.DirName, getCollectorDoneCallback(logger, c, state))
 		if err == nil {
 			workers = append(workers, Worker{
 				reader:        reader,
@@ -273,67 +273,188 @@ func processAction(conn net.Conn, mgr manager.Manager, logger logr.Logger, podNa
 				cancelContext: cancelContext,
 				podKey:        podKey,
 			})
+			podStates.Store(podKey, state)
+			store.trySave(podKey, &state)
 		}
-		writeResponse(conn, PodInjectorStatusWriting)
+		return newResponse(PodInjectorStatusWriting)
 	case PodInjectorActionComplete:
 		removeWorker(podKey)
+		delete(waitingPods, podKey)
 		completedPods.Delete(podKey)
 		erroringPods.Delete(podKey)
+		if value, ok := podStates.LoadAndDelete(podKey); ok {
+			state := value.(podState)
+			store.trySave(podKey, nil)
+			go cleanupCollectorRun(mgr, logger, state)
+		}
 	case PodInjectorActionStatus:
 		if hasWorker(podKey) {
-			writeResponse(conn, PodInjectorStatusWriting)
+			return newResponse(PodInjectorStatusWriting)
 		} else if value, ok := erroringPods.Load(podKey); ok {
-			writeResponse(conn, PodInjectorStatusResponse(fmt.Sprintf("error:%s", value.(string))))
+			return &response{Status: PodInjectorStatusError, Message: value.(string)}
 		} else if value, ok := completedPods.Load(podKey); ok && value.(bool) {
-			writeResponse(conn, PodInjectorStatusDone)
+			return newResponse(PodInjectorStatusDone)
 		} else if len(workers) >= currentMaxWorkers {
-			writeResponse(conn, PodInjectorStatusTooManyWorkers)
+			waitingPods[podKey] = time.Now()
+			return newResponse(PodInjectorStatusTooManyWorkers)
 		} else {
-			writeResponse(conn, PodInjectorStatusIdle)
+			return newResponse(PodInjectorStatusIdle)
 		}
 	case PodInjectorActionLinperfFileName:
 		if value, ok := linperfFileNames.Load(podKey); ok {
-			writeResponse(conn, PodInjectorStatusResponse(fmt.Sprintf("name:%s", value.(string))))
+			return &response{Status: PodInjectorStatusFileName, FileName: value.(string)}
 		} else {
-			writeResponse(conn, PodInjectorStatusNotFound)
+			return newResponse(PodInjectorStatusNotFound)
 		}
 	case PodInjectorActionStop:
 		removeWorker(podKey)
+		delete(waitingPods, podKey)
+		if _, ok := podStates.LoadAndDelete(podKey); ok {
+			store.trySave(podKey, nil)
+		}
+	default:
+		return &response{Status: PodInjectorStatusError, Message: fmt.Sprintf("unknown action %q", action)}
+	}
+	return nil
+}
+
+// Returns the callback of a worker running a collector, which records the result of the run in memory and in the
+// persisted state
+func getCollectorDoneCallback(logger logr.Logger, c collector.Collector, state podState) func(string, string, error) {
+	return func(stdout string, stderr string, err error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		podKey := state.PodKey
+		// the worker was stopped or completed
+		if !hasWorker(podKey) {
+			return
+		}
+		removeWorker(podKey)
+		updateWorkerMetrics()
+		if err == nil {
+			logger.Info(fmt.Sprintf("The %s collector has completed successfully!", c.Name()))
+			logger.Info(fmt.Sprintf("> %s (stdout):", c.Name()))
+			logger.Info(stdout)
+			logger.Info(fmt.Sprintf("> %s (stderr):", c.Name()))
+			logger.Info(stderr)
+			completedPods.Store(podKey, true)
+			fileName := c.OutputFile(stdout, collector.GetOutputDir(state.PodName, state.PodNamespace), state.DirName)
+			linperfFileNames.Store(podKey, fileName)
+			state.Status = podStateDone
+			state.FileName = fileName
+		} else {
+			errMessage := fmt.Sprintf("The performance data collector failed with error: %s", err)
+			logger.Error(err, "The performance data collector failed")
+			logger.Info(fmt.Sprintf("> %s (stdout):", c.Name()))
+			logger.Info(stdout)
+			logger.Info(fmt.Sprintf("> %s (stderr):", c.Name()))
+			logger.Info(stderr)
+			erroringPods.Store(podKey, errMessage)
+			state.Status = podStateError
+			state.Error = errMessage
+		}
+		podStates.Store(podKey, state)
+		store.trySave(podKey, &state)
+	}
+}
+
+// Restores the state persisted before the operator restarted, reattaching to the runs of linperf that are still in progress
+func restoreWorkers(mgr manager.Manager, logger logr.Logger) {
+	states, err := store.load(context.TODO())
+	if err != nil {
+		logger.Error(err, "Failed to load the pod injector state")
+		return
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	defer updateWorkerMetrics()
+	for _, state := range states {
+		podKey := state.PodKey
+		podStates.Store(podKey, state)
+		switch state.Status {
+		case podStateDone:
+			completedPods.Store(podKey, true)
+			linperfFileNames.Store(podKey, state.FileName)
+		case podStateError:
+			erroringPods.Store(podKey, state.Error)
+		case podStateRunning:
+			c, err := collector.Get(state.Tool)
+			var cancelContext context.CancelFunc
+			if err == nil {
+				cancelContext, err = ReattachCollector(mgr.GetConfig(), c, state.PodName, state.PodNamespace, state.DirName, getCollectorDoneCallback(logger, c, state))
+			}
+			if err != nil {
+				errMessage := fmt.Sprintf("The performance data collector failed with error: %s", err)
+				logger.Error(err, "Failed to reattach to the performance data collector of pod "+state.PodName)
+				erroringPods.Store(podKey, errMessage)
+				state.Status = podStateError
+				state.Error = errMessage
+				podStates.Store(podKey, state)
+				store.trySave(podKey, &state)
+				continue
+			}
+			logger.Info(fmt.Sprintf("Reattached to the performance data collector of pod %s in namespace %s", state.PodName, state.PodNamespace))
+			completedPods.Store(podKey, false)
+			workers = append(workers, Worker{
+				cancelContext: cancelContext,
+				podKey:        podKey,
+			})
+		}
+	}
+}
+
+// Removes the files that were kept in the pod to reattach to a run of a collector
+func cleanupCollectorRun(mgr manager.Manager, logger logr.Logger, state podState) {
+	_, err := utils.ExecuteCommandInContainer(mgr.GetConfig(), state.PodName, state.PodNamespace, "app", collector.GetCleanupCmd(state.PodName, state.PodNamespace, state.DirName))
+	if err != nil {
+		logger.V(2).Info(fmt.Sprintf("Failed to clean up the performance data collector files of pod %s in namespace %s: %v", state.PodName, state.PodNamespace, err))
 	}
 }
 
 func handleConnection(mgr manager.Manager, conn net.Conn, logger logr.Logger) {
 	defer conn.Close()
 
-	buffer := make([]byte, 1024)
 	for {
-		n, err := conn.Read(buffer)
+		req, err := readRequest(conn)
 		if err != nil {
-			// logger.Error(fmt.Errorf("Failed to populate data into the buffer, skipping..."), "Invalid message")
+			if err != io.EOF {
+				logger.Error(err, "Invalid message")
+			}
 			return
 		}
-		messagesString := string(buffer[:n])
-		messages := strings.Split(messagesString, "\n")
-		for _, message := range messages {
-			message = strings.Trim(message, " ")
-			if len(message) == 0 {
-				// logger.Error(fmt.Errorf("Expected an non-empty message but received nothing, skipping..."), "Invalid message")
-				continue
-			}
-			messageArr := strings.Split(message, ":")
-			if len(messageArr) != BuildMessageLength {
-				logger.Error(fmt.Errorf("Expected len(messageArr) == %d but got length %d", BuildMessageLength, len(messageArr)), "Invalid message")
-				return
+		if err := req.validate(); err != nil {
+			logger.Error(err, "Invalid message")
+			writeResponse(conn, req, &response{Status: PodInjectorStatusError, Message: err.Error()})
+			continue
+		}
+		podName, podNamespace, tool, action, encodedAttrs := req.PodName, req.PodNamespace, req.Tool, req.Action, req.Payload
+
+		debugLogSignature := fmt.Sprintf("tool (%s), action (%s), pod (%s), namespace (%s), payload (%s)", tool, action, podName, podNamespace, encodedAttrs)
+		logger.V(2).Info(fmt.Sprintf("Requesting lock: [%s]", debugLogSignature))
+		mutex.Lock()
+		logger.V(2).Info(fmt.Sprintf("Holding critical section: [%s]", debugLogSignature))
+		res := processAction(mgr, logger, podName, podNamespace, tool, action, encodedAttrs)
+		logger.V(2).Info(fmt.Sprintf("Releasing lock: [%s]", debugLogSignature))
+		mutex.Unlock()
+		if res != nil {
+			if err := writeResponse(conn, req, res); err != nil {
+				logger.V(2).Info(fmt.Sprintf("Failed to write the response: [%s]: %v", debugLogSignature, err))
 			}
-			podName, podNamespace, tool, action, encodedAttrs := messageArr[0], messageArr[1], messageArr[2], messageArr[3], messageArr[4]
+		}
+	}
+}
 
-			debugLogSignature := fmt.Sprintf("tool (%s), action (%s), pod (%s), namespace (%s), payload (%s)", tool, action, podName, podNamespace, encodedAttrs)
-			logger.V(2).Info(fmt.Sprintf("Requesting lock: [%s]", debugLogSignature))
-			mutex.Lock()
-			logger.V(2).Info(fmt.Sprintf("Holding critical section: [%s]", debugLogSignature))
-			processAction(conn, mgr, logger, podName, podNamespace, tool, action, encodedAttrs)
-			logger.V(2).Info(fmt.Sprintf("Releasing lock: [%s]", debugLogSignature))
-			mutex.Unlock()
+// Updates the metrics of the workers and the queue of the pod injector. Assumes mutex is held.
+func updateWorkerMetrics() {
+	removeExpiredWaitingPods(time.Now())
+	metrics.SetPodInjectorWorkers(len(workers), currentMaxWorkers, len(waitingPods))
+}
+
+// Removes the pods that have not polled for a worker within waitingPodTimeout from the queue. Assumes mutex is held.
+func removeExpiredWaitingPods(now time.Time) {
+	for podKey, lastPoll := range waitingPods {
+		if now.Sub(lastPoll) > waitingPodTimeout {
+			delete(waitingPods, podKey)
 		}
 	}
 }
@@ -0,0 +1,30 @@
+package socket
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRemoveExpiredWaitingPods(t *testing.T) {
+	now := time.Now()
+	mutex.Lock()
+	defer mutex.Unlock()
+	waitingPods = map[string]time.Time{
+		"ns:polling:uid-1": now.Add(-time.Second),
+		"ns:deleted:uid-2": now.Add(-2 * waitingPodTimeout),
+	}
+	defer func() {
+		waitingPods = map[string]time.Time{}
+	}()
+
+	removeExpiredWaitingPods(now)
+	_, polling := waitingPods["ns:polling:uid-1"]
+
+	tests := []Test{
+		{"queue depth", 1, len(waitingPods)},
+		{"pod still polling", true, polling},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
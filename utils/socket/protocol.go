@@ -0,0 +1,121 @@
+package socket
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Messages on the pod injector socket are frames made of a 4-byte big-endian length followed by a JSON document. A
+// request carries the protocol version and an ID that is echoed in its response, so that a client can skip the responses
+// of the requests it did not wait for.
+const (
+	protocolVersion = 1
+	frameHeaderSize = 4
+	maxFrameSize    = 64 * 1024
+)
+
+type request struct {
+	Version      int    `json:"version"`
+	ID           uint64 `json:"id"`
+	PodName      string `json:"podName"`
+	PodNamespace string `json:"podNamespace"`
+	Tool         string `json:"tool"`
+	Action       string `json:"action"`
+	Payload      string `json:"payload,omitempty"`
+}
+
+type response struct {
+	Version int                       `json:"version"`
+	ID      uint64                    `json:"id"`
+	Status  PodInjectorStatusResponse `json:"status"`
+	// Message is set when Status is PodInjectorStatusError
+	Message string `json:"message,omitempty"`
+	// FileName is set when Status is PodInjectorStatusFileName
+	FileName string `json:"fileName,omitempty"`
+}
+
+// Returns the response as the string returned by the Client, which is the status unless the response carries a value
+func (r *response) String() string {
+	switch r.Status {
+	case PodInjectorStatusError:
+		return fmt.Sprintf("error:%s", r.Message)
+	case PodInjectorStatusFileName:
+		return fmt.Sprintf("name:%s", r.FileName)
+	}
+	return string(r.Status)
+}
+
+// Returns an error if the request is not supported by this version of the protocol
+func (r *request) validate() error {
+	if r.Version != protocolVersion {
+		return fmt.Errorf("unsupported protocol version %d, expected version %d", r.Version, protocolVersion)
+	}
+	if r.Action == "" {
+		return fmt.Errorf("the action is required")
+	}
+	if r.PodName == "" || r.PodNamespace == "" {
+		return fmt.Errorf("the pod name and namespace are required")
+	}
+	return nil
+}
+
+// Writes v as a single frame
+func writeFrame(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if len(data) > maxFrameSize {
+		return fmt.Errorf("message of %d bytes exceeds the maximum size of %d bytes", len(data), maxFrameSize)
+	}
+	frame := make([]byte, frameHeaderSize+len(data))
+	binary.BigEndian.PutUint32(frame, uint32(len(data)))
+	copy(frame[frameHeaderSize:], data)
+	_, err = w.Write(frame)
+	return err
+}
+
+// Reads a single frame and returns its JSON document
+func readFrame(r io.Reader) ([]byte, error) {
+	header := make([]byte, frameHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(header)
+	if size == 0 || size > maxFrameSize {
+		return nil, fmt.Errorf("invalid message size %d, the maximum size is %d bytes", size, maxFrameSize)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// Reads a single request. The request is not validated so that the caller can respond to an unsupported request.
+func readRequest(r io.Reader) (*request, error) {
+	data, err := readFrame(r)
+	if err != nil {
+		return nil, err
+	}
+	req := &request{}
+	if err := json.Unmarshal(data, req); err != nil {
+		return nil, fmt.Errorf("invalid request: %v", err)
+	}
+	return req, nil
+}
+
+// Reads a single response
+func readResponse(r io.Reader) (*response, error) {
+	data, err := readFrame(r)
+	if err != nil {
+		return nil, err
+	}
+	res := &response{}
+	if err := json.Unmarshal(data, res); err != nil {
+		return nil, fmt.Errorf("invalid response: %v", err)
+	}
+	return res, nil
+}
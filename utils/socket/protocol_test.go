@@ -0,0 +1,124 @@
+package socket
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"reflect"
+	"testing"
+
+	"github.com/go-logr/logr"
+)
+
+func encodeFrame(v interface{}) []byte {
+	var buffer bytes.Buffer
+	if err := writeFrame(&buffer, v); err != nil {
+		panic(err)
+	}
+	return buffer.Bytes()
+}
+
+func rawFrame(size uint32, data string) []byte {
+	frame := make([]byte, frameHeaderSize)
+	binary.BigEndian.PutUint32(frame, size)
+	return append(frame, data...)
+}
+
+func isReadError(data []byte) bool {
+	_, err := readRequest(bytes.NewReader(data))
+	return err != nil
+}
+
+func TestReadRequest(t *testing.T) {
+	// separators of the previous text protocol are carried as is
+	req := &request{Version: protocolVersion, ID: 7, PodName: "app:0\nstatus", PodNamespace: "ns", Tool: "linperf", Action: PodInjectorActionStatus, Payload: "a:b"}
+	decoded, err := readRequest(bytes.NewReader(encodeFrame(req)))
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	// several requests can be read from the same stream
+	stream := bytes.NewReader(append(encodeFrame(req), encodeFrame(&request{Version: protocolVersion, ID: 8})...))
+	readRequest(stream)
+	second, err := readRequest(stream)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	tests := []Test{
+		{"round trip", req, decoded},
+		{"second request", uint64(8), second.ID},
+		{"valid request", nil, decoded.validate()},
+		{"empty stream", true, isReadError([]byte{})},
+		{"truncated header", true, isReadError([]byte{0, 0})},
+		{"truncated body", true, isReadError(rawFrame(10, "{}"))},
+		{"empty frame", true, isReadError(rawFrame(0, ""))},
+		{"frame too large", true, isReadError(rawFrame(maxFrameSize+1, "{}"))},
+		{"invalid JSON", true, isReadError(rawFrame(3, "{x}"))},
+		{"unsupported version", "unsupported protocol version 2, expected version 1", (&request{Version: 2, PodName: "app-0", PodNamespace: "ns", Action: PodInjectorActionStatus}).validate().Error()},
+		{"missing action", "the action is required", (&request{Version: protocolVersion, PodName: "app-0", PodNamespace: "ns"}).validate().Error()},
+		{"missing pod", "the pod name and namespace are required", (&request{Version: protocolVersion, Action: PodInjectorActionStatus}).validate().Error()},
+		{"message too large", true, writeFrame(&bytes.Buffer{}, &request{Payload: string(make([]byte, maxFrameSize))}) != nil},
+		{"error response", "error:failed", (&response{Status: PodInjectorStatusError, Message: "failed"}).String()},
+		{"file name response", "name:/serviceability/ns/app-0/performanceData/linperf.tar.gz", (&response{Status: PodInjectorStatusFileName, FileName: "/serviceability/ns/app-0/performanceData/linperf.tar.gz"}).String()},
+		{"status response", "done...", (&response{Status: PodInjectorStatusDone}).String()},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestClient(t *testing.T) {
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	go handleConnection(nil, serverConn, logr.Discard())
+	c := &Client{conn: clientConn, logger: logr.Discard()}
+
+	// the response to the request that was not waited for is skipped
+	c.CompleteScript("linperf", "app-0", "ns", "")
+	unknown := c.roundTrip("linperf", "app-0", "ns", "unknown", "")
+	idle := c.PollStatus("linperf", "app:0", "ns", "")
+	fileName := c.PollLinperfFileName("linperf", "app-0", "ns", "")
+	invalidWorkers := c.SetMaxWorkers("linperf", "app-0", "ns", "0")
+	invalidRequest := c.PollStatus("linperf", "", "ns", "")
+
+	c.CloseConnection()
+	closed := c.PollStatus("linperf", "app-0", "ns", "")
+	closedClient := (&Client{logger: logr.Discard()}).PollStatus("linperf", "app-0", "ns", "")
+
+	tests := []Test{
+		{"unknown action", `error:unknown action "unknown"`, unknown},
+		{"status", string(PodInjectorStatusIdle), idle},
+		{"file name", string(PodInjectorStatusNotFound), fileName},
+		{"invalid max workers", false, invalidWorkers},
+		{"invalid request", "error:the pod name and namespace are required", invalidRequest},
+		{"closed connection", string(PodInjectorStatusClosed), closed},
+		{"not connected", string(PodInjectorStatusClosed), closedClient},
+		{"request IDs", uint64(7), c.nextID},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func FuzzReadRequest(f *testing.F) {
+	f.Add(encodeFrame(&request{Version: protocolVersion, ID: 1, PodName: "app-0", PodNamespace: "ns", Tool: "linperf", Action: PodInjectorActionStart, Payload: "dWlkPTE="}))
+	f.Add(encodeFrame(&request{Version: 2, Action: PodInjectorActionStatus}))
+	f.Add(rawFrame(2, "{}"))
+	f.Add(rawFrame(maxFrameSize+1, ""))
+	f.Add([]byte("app-0:ns:linperf:status:\n"))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		req, err := readRequest(bytes.NewReader(data))
+		if err != nil {
+			return
+		}
+		// a decoded request is encoded back to the same request
+		decoded, err := readRequest(bytes.NewReader(encodeFrame(req)))
+		if err != nil {
+			t.Fatalf("failed to read the encoded request: %v", err)
+		}
+		if !reflect.DeepEqual(req, decoded) {
+			t.Fatalf("expected: (%v) actual: (%v)", req, decoded)
+		}
+		req.validate()
+	})
+}
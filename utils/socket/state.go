@@ -0,0 +1,143 @@
+package socket
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	rcoutils "github.com/application-stacks/runtime-component-operator/utils"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// Name of the ConfigMap, in the operator namespace, that persists the state of the pod injector workers
+const podInjectorStateConfigMapName = "olo-pod-injector-state"
+
+type podStateStatus string
+
+const (
+	podStateRunning podStateStatus = "running"
+	podStateDone    podStateStatus = "done"
+	podStateError   podStateStatus = "error"
+)
+
+// The state of a run of a collector in a pod, persisted so that the operator can reattach to the run after it restarts
+type podState struct {
+	PodKey       string         `json:"podKey"`
+	PodName      string         `json:"podName"`
+	PodNamespace string         `json:"podNamespace"`
+	Tool         string         `json:"tool,omitempty"`
+	DirName      string         `json:"dirName"`
+	Status       podStateStatus `json:"status"`
+	FileName     string         `json:"fileName,omitempty"`
+	Error        string         `json:"error,omitempty"`
+}
+
+// Persists the state of the pod injector workers to a ConfigMap, keyed by pod key
+type stateStore struct {
+	client    client.Client
+	reader    client.Reader
+	namespace string
+	logger    logr.Logger
+}
+
+// Returns a store that persists to the operator namespace, or nil if the operator namespace is unknown, such as when
+// the operator runs locally
+func newStateStore(c client.Client, reader client.Reader, logger logr.Logger) *stateStore {
+	namespace, err := rcoutils.GetOperatorNamespace()
+	if err != nil || namespace == "" {
+		logger.Info("The operator namespace is unknown, the pod injector state will not survive operator restarts")
+		return nil
+	}
+	return &stateStore{client: c, reader: reader, namespace: namespace, logger: logger}
+}
+
+// ConfigMap keys may only contain alphanumeric characters, '-', '_' or '.', while pod keys are separated by colons
+func getStateKey(podKey string) string {
+	return strings.ReplaceAll(podKey, ":", ".")
+}
+
+// Returns the persisted states
+func (s *stateStore) load(ctx context.Context) ([]podState, error) {
+	states := []podState{}
+	if s == nil {
+		return states, nil
+	}
+	configMap := &corev1.ConfigMap{}
+	if err := s.reader.Get(ctx, types.NamespacedName{Name: podInjectorStateConfigMapName, Namespace: s.namespace}, configMap); err != nil {
+		if kerrors.IsNotFound(err) {
+			return states, nil
+		}
+		return nil, err
+	}
+	for key, value := range configMap.Data {
+		state := podState{}
+		if err := json.Unmarshal([]byte(value), &state); err != nil {
+			s.logger.Error(err, "Ignoring the invalid pod injector state "+key)
+			continue
+		}
+		states = append(states, state)
+	}
+	return states, nil
+}
+
+// Persists the state of the pod key, or forgets it if state is nil
+func (s *stateStore) save(ctx context.Context, podKey string, state *podState) error {
+	if s == nil {
+		return nil
+	}
+	key := getStateKey(podKey)
+	var value string
+	if state != nil {
+		data, err := json.Marshal(state)
+		if err != nil {
+			return err
+		}
+		value = string(data)
+	}
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMap := &corev1.ConfigMap{}
+		err := s.reader.Get(ctx, types.NamespacedName{Name: podInjectorStateConfigMapName, Namespace: s.namespace}, configMap)
+		if kerrors.IsNotFound(err) {
+			if state == nil {
+				return nil
+			}
+			configMap = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      podInjectorStateConfigMapName,
+					Namespace: s.namespace,
+					Labels:    map[string]string{"app.kubernetes.io/managed-by": "open-liberty-operator"},
+				},
+				Data: map[string]string{key: value},
+			}
+			return s.client.Create(ctx, configMap)
+		} else if err != nil {
+			return err
+		}
+		if state == nil {
+			if _, found := configMap.Data[key]; !found {
+				return nil
+			}
+			delete(configMap.Data, key)
+		} else {
+			if configMap.Data == nil {
+				configMap.Data = map[string]string{}
+			}
+			configMap.Data[key] = value
+		}
+		return s.client.Update(ctx, configMap)
+	})
+}
+
+// Persists the state of the pod key, logging the error if the state cannot be saved
+func (s *stateStore) trySave(podKey string, state *podState) {
+	if err := s.save(context.TODO(), podKey, state); err != nil {
+		s.logger.Error(err, fmt.Sprintf("Failed to persist the pod injector state of %s", podKey))
+	}
+}
@@ -0,0 +1,80 @@
+package socket
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	fakeclient "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestStateStore(t *testing.T) {
+	c := fakeclient.NewClientBuilder().WithScheme(clientgoscheme.Scheme).Build()
+	s := &stateStore{client: c, reader: c, namespace: "openliberty", logger: logr.Discard()}
+	ctx := context.TODO()
+
+	running := podState{PodKey: "ns:app-0:uid", PodName: "app-0", PodNamespace: "ns", DirName: "2024-1-10_0:0:0", Status: podStateRunning}
+	done := podState{PodKey: "ns:app-1:uid", PodName: "app-1", PodNamespace: "ns", DirName: "2024-1-10_0:0:0", Status: podStateDone, FileName: "/serviceability/ns/app-1/performanceData/linperf_RESULTS.tar.gz"}
+
+	emptyStates, err := s.load(ctx)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	// forgetting a state before the ConfigMap exists does not create it
+	if err := s.save(ctx, running.PodKey, nil); err != nil {
+		t.Fatalf("%v", err)
+	}
+	notCreated := c.Get(ctx, types.NamespacedName{Name: podInjectorStateConfigMapName, Namespace: "openliberty"}, &corev1.ConfigMap{}) != nil
+
+	if err := s.save(ctx, running.PodKey, &running); err != nil {
+		t.Fatalf("%v", err)
+	}
+	if err := s.save(ctx, done.PodKey, &done); err != nil {
+		t.Fatalf("%v", err)
+	}
+	bothStates, err := s.load(ctx)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	sort.Slice(bothStates, func(i, j int) bool { return bothStates[i].PodKey < bothStates[j].PodKey })
+
+	configMap := &corev1.ConfigMap{}
+	if err := c.Get(ctx, types.NamespacedName{Name: podInjectorStateConfigMapName, Namespace: "openliberty"}, configMap); err != nil {
+		t.Fatalf("%v", err)
+	}
+	keys := []string{}
+	for key := range configMap.Data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	if err := s.save(ctx, running.PodKey, nil); err != nil {
+		t.Fatalf("%v", err)
+	}
+	remainingStates, err := s.load(ctx)
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	var nilStore *stateStore
+	nilStates, nilErr := nilStore.load(ctx)
+
+	tests := []Test{
+		{"load without ConfigMap", []podState{}, emptyStates},
+		{"forget without ConfigMap", true, notCreated},
+		{"load saved states", []podState{running, done}, bothStates},
+		{"ConfigMap keys", []string{"ns.app-0.uid", "ns.app-1.uid"}, keys},
+		{"ConfigMap label", "open-liberty-operator", configMap.Labels["app.kubernetes.io/managed-by"]},
+		{"load after forget", []podState{done}, remainingStates},
+		{"nil store load", []podState{}, nilStates},
+		{"nil store error", nil, nilErr},
+		{"nil store save", nil, nilStore.save(ctx, running.PodKey, &running)},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package trace
+
+import (
+	"encoding/xml"
+	"fmt"
+	"path"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// Trace levels accepted in a trace specification, from the least to the most detailed
+var levels = []string{"off", "fatal", "severe", "warning", "audit", "info", "config", "detail", "fine", "finer", "finest", "all"}
+
+// Values accepted by the attributes of the logging element
+var (
+	TraceFormats     = []string{"ENHANCED", "BASIC", "ADVANCED"}
+	ConsoleLogLevels = []string{"INFO", "AUDIT", "WARNING", "ERROR", "OFF"}
+	MessageFormats   = []string{"simple", "json", "tbasic"}
+)
+
+// A component of a trace specification is a logger or trace group name, optionally ending with a * wildcard
+var componentPattern = regexp.MustCompile(`^(\*|[A-Za-z0-9_$][A-Za-z0-9_$./\-]*\*?)$`)
+
+// Logging is the logging element of the trace configuration drop-in. Optional attributes are omitted when empty.
+type Logging struct {
+	XMLName            xml.Name `xml:"logging"`
+	TraceSpecification string   `xml:"traceSpecification,attr,omitempty"`
+	LogDirectory       string   `xml:"logDirectory,attr"`
+	MaxFileSize        *int32   `xml:"maxFileSize,attr,omitempty"`
+	MaxFiles           *int32   `xml:"maxFiles,attr,omitempty"`
+	TraceFormat        string   `xml:"traceFormat,attr,omitempty"`
+	ConsoleLogLevel    string   `xml:"consoleLogLevel,attr,omitempty"`
+	MessageFormat      string   `xml:"messageFormat,attr,omitempty"`
+}
+
+type server struct {
+	XMLName xml.Name `xml:"server"`
+	Logging Logging  `xml:"logging"`
+}
+
+// ValidateSpecification returns an error unless spec is a list of component=level entries separated by colons, such as
+// *=info:com.ibm.ws.webcontainer*=all. An entry may end with =enabled or =disabled. An empty specification is valid.
+func ValidateSpecification(spec string) error {
+	if spec == "" {
+		return nil
+	}
+	for _, entry := range strings.Split(spec, ":") {
+		parts := strings.Split(strings.TrimSpace(entry), "=")
+		if len(parts) < 2 || len(parts) > 3 {
+			return fmt.Errorf("trace specification entry %q must have the form component=level", entry)
+		}
+		if !componentPattern.MatchString(parts[0]) {
+			return fmt.Errorf("trace specification entry %q has an invalid component %q", entry, parts[0])
+		}
+		if !containsFold(levels, parts[1]) {
+			return fmt.Errorf("trace specification entry %q has an invalid level %q, valid levels are %s", entry, parts[1], strings.Join(levels, ", "))
+		}
+		if len(parts) == 3 && !containsFold([]string{"enabled", "disabled"}, parts[2]) {
+			return fmt.Errorf("trace specification entry %q must end with =enabled or =disabled", entry)
+		}
+	}
+	return nil
+}
+
+// Validate returns an error if an attribute of the logging element is not valid
+func (l *Logging) Validate() error {
+	if err := ValidateSpecification(l.TraceSpecification); err != nil {
+		return err
+	}
+	if !path.IsAbs(l.LogDirectory) || path.Clean(l.LogDirectory) != l.LogDirectory {
+		return fmt.Errorf("log directory %q must be a clean absolute path", l.LogDirectory)
+	}
+	if l.MaxFileSize != nil && *l.MaxFileSize < 0 {
+		return fmt.Errorf("maxFileSize must not be negative")
+	}
+	if l.MaxFiles != nil && *l.MaxFiles < 0 {
+		return fmt.Errorf("maxFiles must not be negative")
+	}
+	for _, attr := range []struct {
+		name   string
+		value  string
+		values []string
+	}{
+		{"traceFormat", l.TraceFormat, TraceFormats},
+		{"consoleLogLevel", l.ConsoleLogLevel, ConsoleLogLevels},
+		{"messageFormat", l.MessageFormat, MessageFormats},
+	} {
+		if attr.value != "" && !contains(attr.values, attr.value) {
+			return fmt.Errorf("%s %q is not one of %s", attr.name, attr.value, strings.Join(attr.values, ", "))
+		}
+	}
+	return nil
+}
+
+// Marshal validates the logging element and returns the server.xml document that configures it. The document is parsed
+// back to make sure that it holds exactly the configured attributes.
+func (l *Logging) Marshal() ([]byte, error) {
+	if err := l.Validate(); err != nil {
+		return nil, err
+	}
+	doc, err := xml.MarshalIndent(server{Logging: *l}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to build the trace configuration: %v", err)
+	}
+	doc = append(doc, '\n')
+
+	parsed := server{}
+	if err := xml.Unmarshal(doc, &parsed); err != nil {
+		return nil, fmt.Errorf("the trace configuration is malformed: %v", err)
+	}
+	parsed.Logging.XMLName = xml.Name{}
+	expected := *l
+	expected.XMLName = xml.Name{}
+	if !reflect.DeepEqual(parsed.Logging, expected) {
+		return nil, fmt.Errorf("the trace configuration does not hold the configured attributes")
+	}
+	return doc, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,102 @@
+package trace
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type Test struct {
+	test     string
+	expected interface{}
+	actual   interface{}
+}
+
+func verifyTests(tests []Test) error {
+	for _, tt := range tests {
+		if !reflect.DeepEqual(tt.actual, tt.expected) {
+			return fmt.Errorf("%s test expected: (%v) actual: (%v)", tt.test, tt.expected, tt.actual)
+		}
+	}
+	return nil
+}
+
+func isValid(err error) bool {
+	return err == nil
+}
+
+func TestValidateSpecification(t *testing.T) {
+	tests := []Test{
+		{"empty", true, isValid(ValidateSpecification(""))},
+		{"default", true, isValid(ValidateSpecification("*=info"))},
+		{"several entries", true, isValid(ValidateSpecification("*=info:com.ibm.ws.webcontainer*=all:SSL=FINEST"))},
+		{"enabled", true, isValid(ValidateSpecification("*=info=enabled:com.ibm.ws.*=fine=disabled"))},
+		{"unknown level", false, isValid(ValidateSpecification("*=verbose"))},
+		{"missing level", false, isValid(ValidateSpecification("com.ibm.ws.webcontainer*"))},
+		{"empty entry", false, isValid(ValidateSpecification("*=info::SSL=all"))},
+		{"quote", false, isValid(ValidateSpecification(`*=info" consoleLogLevel="OFF`))},
+		{"shell", false, isValid(ValidateSpecification("*=info'; rm -rf /config; echo '"))},
+		{"invalid state", false, isValid(ValidateSpecification("*=info=on"))},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	maxFileSize := int32(20)
+	maxFiles := int32(0)
+	logging := &Logging{
+		TraceSpecification: "*=info:com.ibm.ws.webcontainer*=all",
+		LogDirectory:       "/serviceability/ns/app-0/logs",
+		MaxFileSize:        &maxFileSize,
+		MaxFiles:           &maxFiles,
+		TraceFormat:        "BASIC",
+		ConsoleLogLevel:    "WARNING",
+		MessageFormat:      "json",
+	}
+	doc, err := logging.Marshal()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	minimal, err := (&Logging{LogDirectory: "/serviceability/ns/app-0/logs"}).Marshal()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+	// attribute values are escaped even though they are validated first
+	escaped, err := (&Logging{LogDirectory: "/serviceability/ns/a&b/\"logs\""}).Marshal()
+	if err != nil {
+		t.Fatalf("%v", err)
+	}
+
+	marshalErr := func(l Logging) bool {
+		_, err := l.Marshal()
+		return err != nil
+	}
+	negative := int32(-1)
+
+	tests := []Test{
+		{"all attributes", `<server>
+  <logging traceSpecification="*=info:com.ibm.ws.webcontainer*=all" logDirectory="/serviceability/ns/app-0/logs" maxFileSize="20" maxFiles="0" traceFormat="BASIC" consoleLogLevel="WARNING" messageFormat="json"></logging>
+</server>
+`, string(doc)},
+		{"minimal", `<server>
+  <logging logDirectory="/serviceability/ns/app-0/logs"></logging>
+</server>
+`, string(minimal)},
+		{"escaped", `<server>
+  <logging logDirectory="/serviceability/ns/a&amp;b/&#34;logs&#34;"></logging>
+</server>
+`, string(escaped)},
+		{"invalid trace specification", true, marshalErr(Logging{TraceSpecification: "*=info&", LogDirectory: "/logs"})},
+		{"relative log directory", true, marshalErr(Logging{LogDirectory: "logs"})},
+		{"unclean log directory", true, marshalErr(Logging{LogDirectory: "/serviceability/../config"})},
+		{"negative max files", true, marshalErr(Logging{LogDirectory: "/logs", MaxFiles: &negative})},
+		{"invalid trace format", true, marshalErr(Logging{LogDirectory: "/logs", TraceFormat: "json"})},
+		{"invalid console log level", true, marshalErr(Logging{LogDirectory: "/logs", ConsoleLogLevel: "DEBUG"})},
+		{"invalid message format", true, marshalErr(Logging{LogDirectory: "/logs", MessageFormat: "xml"})},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
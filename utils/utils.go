@@ -4,12 +4,12 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
-	"time"
 
 	"math/rand/v2"
 
@@ -44,6 +44,7 @@ const StatusReferenceLibertyVersionLastPull = "libertyVersionLastPull"
 
 // Constant Values
 const serviceabilityMountPath = "/serviceability"
+const autoDumpPreStopCommand = "server javadump"
 const ssoEnvVarPrefix = "SEC_SSO_"
 const OperandVersion = "1.6.2"
 
@@ -51,6 +52,7 @@ const OperandVersion = "1.6.2"
 const LTPAServerXMLSuffix = "-managed-ltpa-server-xml"
 const LTPAServerXMLMountSuffix = "-managed-ltpa-mount-server-xml"
 const LTPAKeysFileName = "ltpa.keys"
+const LTPAValidationKeysFileName = "ltpa.validation.keys"
 const LTPAKeysXMLFileName = "managedLTPA.xml"
 const LTPAKeysMountXMLFileName = "managedLTPAMount.xml"
 
@@ -174,6 +176,11 @@ func Validate(olapp *olv1.OpenLibertyApplication) (bool, error) {
 				return false, fmt.Errorf("validation failed: cannot parse '%v': %v", olapp.GetServiceability().GetSize(), err)
 			}
 		}
+		if retention := olapp.GetServiceability().GetRetention(); retention != nil && retention.MaxTotalSize != "" {
+			if _, err := resource.ParseQuantity(retention.MaxTotalSize); err != nil {
+				return false, fmt.Errorf("validation failed: cannot parse '%v': %v", retention.MaxTotalSize, err)
+			}
+		}
 	}
 
 	return true, nil
@@ -186,6 +193,8 @@ const (
 	OpConfigImageVersionChecks                       = "imageVersionChecks"
 	OpConfigImageVersionChecksRefreshIntervalMinutes = "imageVersionChecksRefreshIntervalMinutes"
 	OpConfigPasswordEncodingType                     = "passwordEncodingType"
+	OpConfigLTPAKeysRotationInterval                 = "ltpaKeysRotationInterval"
+	OpConfigLTPAKeysValidationPeriod                 = "ltpaKeysValidationPeriod"
 )
 
 var DefaultLibertyOpConfig *sync.Map
@@ -196,18 +205,8 @@ func init() {
 	DefaultLibertyOpConfig.Store(OpConfigImageVersionChecks, "true")
 	DefaultLibertyOpConfig.Store(OpConfigImageVersionChecksRefreshIntervalMinutes, "720")
 	DefaultLibertyOpConfig.Store(OpConfigPasswordEncodingType, "aes")
-}
-
-func parseFlag(key, value, delimiter string) string {
-	return fmt.Sprintf("%s%s%s", key, delimiter, value)
-}
-
-func EncodeLinperfAttr(instance *olv1.OpenLibertyPerformanceData) string {
-	return fmt.Sprintf("timespan/%d|interval/%d|uid/%s|name/%s",
-		instance.GetTimespan(),
-		instance.GetInterval(),
-		instance.GetUID(),
-		instance.GetName())
+	DefaultLibertyOpConfig.Store(OpConfigLTPAKeysRotationInterval, "0")
+	DefaultLibertyOpConfig.Store(OpConfigLTPAKeysValidationPeriod, "24h")
 }
 
 func DecodeLinperfAttr(encodedAttr string) map[string]string {
@@ -231,44 +230,36 @@ func GetPerformanceDataWritingMessage(podName string) string {
 	return fmt.Sprintf("Collecting performance data for Pod '%s'...", podName)
 }
 
-func GetLinperfCmd(encodedAttrs, podName, podNamespace string) string {
-	scriptDir := "$WLP_OUTPUT_DIR/helper"
-	scriptName := "linperf.sh"
-
-	decodedLinperfAttrs := DecodeLinperfAttr(encodedAttrs)
-
-	linperfCmdArgs := []string{fmt.Sprintf("%s/%s", scriptDir, scriptName)}
-	serviceabilityRootDir := "/serviceability"
-	outputDir := fmt.Sprintf("%s/%s/%s/performanceData/", serviceabilityRootDir, podNamespace, podName)
-	linperfCmdArgs = append(linperfCmdArgs, parseFlag("--output-dir", outputDir, FlagDelimiterEquals))
-
-	now := time.Now()
-	startDate := fmt.Sprintf("%d%d%d", now.Year(), now.Month(), now.Day())
-	startTime := fmt.Sprintf("%d%d%d", now.Hour(), now.Minute(), now.Second())
-	fileName := fmt.Sprintf("linperf_RESULTS_%s.%s.%s", decodedLinperfAttrs["name"], startDate, startTime)
-	linperfCmdArgs = append(linperfCmdArgs, parseFlag("--dir-name", fileName, FlagDelimiterEquals))
+// ExecuteCommandInContainer Execute command inside a container in a pod through API
+func ExecuteCommandInContainer(config *rest.Config, podName, podNamespace, containerName string, command []string) (string, error) {
+	return ExecuteCommandInContainerWithContext(context.Background(), config, podName, podNamespace, containerName, command)
+}
 
-	linperfCmdArgs = append(linperfCmdArgs, parseFlag("-s", decodedLinperfAttrs["timespan"], FlagDelimiterSpace))
-	linperfCmdArgs = append(linperfCmdArgs, parseFlag("-j", decodedLinperfAttrs["interval"], FlagDelimiterSpace))
+// ExecuteCommandInContainerWithContext Execute command inside a container in a pod through API, stopping the stream when ctx is done
+func ExecuteCommandInContainerWithContext(ctx context.Context, config *rest.Config, podName, podNamespace, containerName string, command []string) (string, error) {
+	var stdout bytes.Buffer
+	return StreamCommandInContainer(ctx, config, podName, podNamespace, containerName, command, &stdout)
+}
 
-	linperfCmdArgs = append(linperfCmdArgs, "--ignore-root")
-	linperfCmdArgs = append(linperfCmdArgs, "--clean-up-javacores")
-	linperfCmd := strings.Join(linperfCmdArgs, FlagDelimiterSpace)
+// StreamCommandInContainer Execute command inside a container in a pod through API, writing its standard output to stdout
+func StreamCommandInContainer(ctx context.Context, config *rest.Config, podName, podNamespace, containerName string, command []string, stdout io.Writer) (string, error) {
+	return StreamCommandInContainerWithInput(ctx, config, podName, podNamespace, containerName, command, nil, stdout)
+}
 
-	requiredCLIs := []string{"netstat", "ps", "dmesg", "tput", "ifconfig", "vmstat", "top", "uptime", "hostname"}
-	cmdArgs := []string{}
-	for _, cli := range requiredCLIs {
-		cmdArgs = append(cmdArgs, fmt.Sprintf("command -v %s >/dev/null 2>&1", cli))
+// WriteFileInContainer Write content to a file inside a container in a pod through API. The content is streamed to the
+// standard input of tee, so it is never interpreted by a shell, and the file is replaced by renaming a temporary file.
+func WriteFileInContainer(ctx context.Context, config *rest.Config, podName, podNamespace, containerName, path string, content io.Reader) error {
+	tmpPath := path + ".tmp"
+	if _, err := StreamCommandInContainerWithInput(ctx, config, podName, podNamespace, containerName, []string{"tee", tmpPath}, content, io.Discard); err != nil {
+		return err
 	}
-	checkCLICmd := strings.Join(cmdArgs, " && ") // add spaces for readability
-
-	linperfCmdWithPids := fmt.Sprintf("if ! (%s); then exit 130; elif [ $(df | grep %s -c) -eq 0 ]; then exit 129; else mkdir -p %s && %s \"1\"; fi", checkCLICmd, serviceabilityRootDir, outputDir, linperfCmd)
-	// fmt.Println("Linperf cmd: " + linperfCmdWithPids) // un-commment this line for debugging
-	return linperfCmdWithPids
+	_, err := ExecuteCommandInContainerWithContext(ctx, config, podName, podNamespace, containerName, []string{"mv", "-f", tmpPath, path})
+	return err
 }
 
-// ExecuteCommandInContainer Execute command inside a container in a pod through API
-func ExecuteCommandInContainer(config *rest.Config, podName, podNamespace, containerName string, command []string) (string, error) {
+// StreamCommandInContainerWithInput Execute command inside a container in a pod through API, streaming stdin to its
+// standard input when it is not nil and writing its standard output to stdout
+func StreamCommandInContainerWithInput(ctx context.Context, config *rest.Config, podName, podNamespace, containerName string, command []string, stdin io.Reader, stdout io.Writer) (string, error) {
 
 	clientset, err := kubernetes.NewForConfig(config)
 	if err != nil {
@@ -285,6 +276,7 @@ func ExecuteCommandInContainer(config *rest.Config, podName, podNamespace, conta
 	req.VersionedParams(&corev1.PodExecOptions{
 		Command:   command,
 		Container: containerName,
+		Stdin:     stdin != nil,
 		Stdout:    true,
 		Stderr:    true,
 		TTY:       false,
@@ -295,9 +287,10 @@ func ExecuteCommandInContainer(config *rest.Config, podName, podNamespace, conta
 		return "", fmt.Errorf("Encountered error while creating Executor: %v", err.Error())
 	}
 
-	var stdout, stderr bytes.Buffer
-	err = exec.StreamWithContext(context.Background(), remotecommand.StreamOptions{
-		Stdout: &stdout,
+	var stderr bytes.Buffer
+	err = exec.StreamWithContext(ctx, remotecommand.StreamOptions{
+		Stdin:  stdin,
+		Stdout: stdout,
 		Stderr: &stderr,
 		Tty:    false,
 	})
@@ -329,6 +322,10 @@ func createLibertyEnv(la *olv1.OpenLibertyApplication, client client.Client) ([]
 			corev1.EnvVar{Name: "IBM_COREDIR", Value: logDirEnvValue},
 			corev1.EnvVar{Name: "IBM_JAVACOREDIR", Value: logDirEnvValue},
 		)
+		if autoDump := la.GetServiceability().GetAutoDump(); autoDump != nil && autoDump.IsOOMKilledEnabled() {
+			// OpenJ9 and HotSpot both accept these options. OpenJ9 also writes a javacore on an OutOfMemoryError by default.
+			targetEnv = append(targetEnv, corev1.EnvVar{Name: "JVM_ARGS", Value: "-XX:+HeapDumpOnOutOfMemoryError -XX:HeapDumpPath=" + logDirEnvValue})
+		}
 	}
 
 	if IsFileBasedProbesEnabled(la) {
@@ -590,8 +587,9 @@ func ConfigureServiceabilityNetworkPolicy(networkPolicy *networkingv1.NetworkPol
 	networkPolicy.Spec.Ingress[0].From = append(networkPolicy.Spec.Ingress[0].From, peer)
 }
 
-// ConfigureServiceability setups the shared-storage for serviceability
+// ConfigureServiceability setups the shared-storage for serviceability and the preStop hook of .spec.serviceability.autoDump
 func ConfigureServiceability(pts *corev1.PodTemplateSpec, la *olv1.OpenLibertyApplication) {
+	configureAutoDumpPreStop(&pts.Spec.Containers[0], la)
 	if la.GetServiceability() != nil {
 		name := "serviceability"
 
@@ -637,6 +635,47 @@ func ConfigureServiceability(pts *corev1.PodTemplateSpec, la *olv1.OpenLibertyAp
 	}
 }
 
+// Sets the preStop hook that dumps the server before the container is stopped when .spec.serviceability.autoDump.preStop
+// is enabled, and removes the hook once it is disabled
+func configureAutoDumpPreStop(container *corev1.Container, la *olv1.OpenLibertyApplication) {
+	var autoDump *olv1.OpenLibertyApplicationAutoDump
+	if la.GetServiceability() != nil {
+		autoDump = la.GetServiceability().GetAutoDump()
+	}
+	if autoDump != nil && autoDump.IsPreStopEnabled() {
+		if container.Lifecycle == nil {
+			container.Lifecycle = &corev1.Lifecycle{}
+		}
+		container.Lifecycle.PreStop = getAutoDumpPreStopHandler(autoDump)
+		return
+	}
+	if container.Lifecycle != nil && container.Lifecycle.PreStop != nil && container.Lifecycle.PreStop.Exec != nil {
+		command := container.Lifecycle.PreStop.Exec.Command
+		if len(command) == 3 && strings.HasPrefix(command[2], autoDumpPreStopCommand) {
+			container.Lifecycle.PreStop = nil
+		}
+	}
+}
+
+// getAutoDumpPreStopHandler returns the preStop hook that writes a javacore, and the other dump types of autoDump, of the
+// running server. The dumps are written to the directories of IBM_JAVACOREDIR and IBM_HEAPDUMPDIR, the logs folder of the pod.
+func getAutoDumpPreStopHandler(autoDump *olv1.OpenLibertyApplicationAutoDump) *corev1.LifecycleHandler {
+	command := autoDumpPreStopCommand
+	include := []string{}
+	for _, dumpType := range autoDump.GetInclude() {
+		// server javadump always writes a thread dump
+		if dumpType != olv1.OpenLibertyDumpIncludeThread {
+			include = append(include, string(dumpType))
+		}
+	}
+	if len(include) > 0 {
+		command += " --include=" + strings.Join(include, ",")
+	}
+	return &corev1.LifecycleHandler{
+		Exec: &corev1.ExecAction{Command: []string{"/bin/sh", "-c", command}},
+	}
+}
+
 func normalizeEnvVariableName(name string) string {
 	return strings.NewReplacer("-", "_", ".", "_").Replace(strings.ToUpper(name))
 }
@@ -943,6 +982,19 @@ func ConfigureLTPAConfig(pts *corev1.PodTemplateSpec, la *olv1.OpenLibertyApplic
 	MountSecretAsVolume(pts, operatorShortName+LTPAServerXMLMountSuffix+ltpaSuffixName, CreateVolumeMount(overridesMountPath, LTPAKeysMountXMLFileName))
 }
 
+// ConfigureLTPAValidationKeys mounts the previous LTPA keys that are kept in the LTPA Secret after a key rotation
+func ConfigureLTPAValidationKeys(pts *corev1.PodTemplateSpec, la *olv1.OpenLibertyApplication, client client.Client, ltpaSecretName string) error {
+	secret := &corev1.Secret{}
+	err := client.Get(context.TODO(), types.NamespacedName{Name: ltpaSecretName, Namespace: la.GetNamespace()}, secret)
+	if err != nil {
+		return errors.Wrapf(err, "Secret %q was not found in namespace %q", ltpaSecretName, la.GetNamespace())
+	}
+	if _, found := secret.Data[LTPAValidationKeysFileName]; found {
+		MountSecretAsVolume(pts, ltpaSecretName, CreateVolumeMount(SecureMountPath, LTPAValidationKeysFileName))
+	}
+	return nil
+}
+
 func MountSecretAsVolume(pts *corev1.PodTemplateSpec, secretName string, volumeMount corev1.VolumeMount) {
 	if !isVolumeMountFound(pts, volumeMount.Name) {
 		pts.Spec.Containers[0].VolumeMounts = append(pts.Spec.Containers[0].VolumeMounts, volumeMount)
@@ -990,15 +1042,26 @@ func CustomizePasswordEncryptionKeyXML(managedEncryptionXMLSecret *corev1.Secret
 	return nil
 }
 
-func CustomizeLTPAServerXML(xmlSecret *corev1.Secret, la *olv1.OpenLibertyApplication, encryptedPassword string) error {
+// CustomizeLTPAServerXML sets the Liberty server XML that imports the LTPA keys. When encryptedValidationPassword is set,
+// the previous LTPA keys are imported as validation keys that are accepted until validUntilDate.
+func CustomizeLTPAServerXML(xmlSecret *corev1.Secret, la *olv1.OpenLibertyApplication, encryptedPassword string, encryptedValidationPassword string, validUntilDate string) error {
 	xmlSecret.StringData = make(map[string]string)
 	managedLTPADir := strings.Replace(SecureMountPath, "/output", "${server.output.dir}", 1)
-	serverXML, err := os.ReadFile("internal/controller/assets/ltpa.xml")
+	serverXMLFile := "internal/controller/assets/ltpa.xml"
+	if encryptedValidationPassword != "" {
+		serverXMLFile = "internal/controller/assets/ltpa-validation-keys.xml"
+	}
+	serverXML, err := os.ReadFile(serverXMLFile)
 	if err != nil {
 		return err
 	}
 	severXMLString := strings.Replace(string(serverXML), "LTPA_KEYS_FILE_NAME", managedLTPADir+"/"+LTPAKeysFileName, 1)
 	severXMLString = strings.Replace(severXMLString, "LTPA_KEYS_PASSWORD", encryptedPassword, 1)
+	if encryptedValidationPassword != "" {
+		severXMLString = strings.Replace(severXMLString, "LTPA_VALIDATION_KEYS_FILE_NAME", managedLTPADir+"/"+LTPAValidationKeysFileName, 1)
+		severXMLString = strings.Replace(severXMLString, "LTPA_VALIDATION_KEYS_PASSWORD", encryptedValidationPassword, 1)
+		severXMLString = strings.Replace(severXMLString, "LTPA_VALIDATION_KEYS_VALID_UNTIL_DATE", validUntilDate, 1)
+	}
 	xmlSecret.StringData[LTPAKeysXMLFileName] = severXMLString
 	return nil
 }
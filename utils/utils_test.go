@@ -113,6 +113,42 @@ func TestCustomizeLibertyEnv(t *testing.T) {
 	}
 }
 
+func TestConfigureServiceabilityAutoDump(t *testing.T) {
+	enabled := true
+	autoDump := &openlibertyv1.OpenLibertyApplicationAutoDump{PreStop: &enabled, OOMKilled: &enabled}
+	openliberty := createOpenLibertyApp(name, namespace, openlibertyv1.OpenLibertyApplicationSpec{
+		Serviceability: &openlibertyv1.OpenLibertyApplicationServiceability{Size: "1Gi", AutoDump: autoDump},
+	})
+	pts := &corev1.PodTemplateSpec{Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}}}
+	ConfigureServiceability(pts, openliberty)
+	preStop := pts.Spec.Containers[0].Lifecycle.PreStop.Exec.Command
+	targetEnv, _, _ := createLibertyEnv(openliberty, nil)
+
+	// the hook is removed once preStop is disabled, but a hook set by the user is kept
+	autoDump.PreStop = nil
+	ConfigureServiceability(pts, openliberty)
+	removedPreStop := pts.Spec.Containers[0].Lifecycle.PreStop
+	userPreStop := &corev1.LifecycleHandler{Exec: &corev1.ExecAction{Command: []string{"/bin/sh", "-c", "sleep 5"}}}
+	pts.Spec.Containers[0].Lifecycle.PreStop = userPreStop
+	ConfigureServiceability(pts, openliberty)
+
+	autoDump.OOMKilled = nil
+	disabledEnv, _, _ := createLibertyEnv(openliberty, nil)
+
+	tests := []Test{
+		{"preStop hook", []string{"/bin/sh", "-c", "server javadump --include=heap"}, preStop},
+		{"preStop hook of thread dumps", []string{"/bin/sh", "-c", "server javadump"},
+			getAutoDumpPreStopHandler(&openlibertyv1.OpenLibertyApplicationAutoDump{Include: []openlibertyv1.OpenLibertyDumpInclude{openlibertyv1.OpenLibertyDumpIncludeThread}}).Exec.Command},
+		{"preStop hook removed", (*corev1.LifecycleHandler)(nil), removedPreStop},
+		{"user preStop hook kept", userPreStop, pts.Spec.Containers[0].Lifecycle.PreStop},
+		{"OutOfMemoryError JVM options", corev1.EnvVar{Name: "JVM_ARGS", Value: "-XX:+HeapDumpOnOutOfMemoryError -XX:HeapDumpPath=$(LOG_DIR)"}, targetEnv[len(targetEnv)-2]},
+		{"OutOfMemoryError JVM options disabled", len(targetEnv) - 1, len(disabledEnv)},
+	}
+	if err := verifyTests(tests); err != nil {
+		t.Fatalf("%v", err)
+	}
+}
+
 func TestCustomizeEnvSSO(t *testing.T) {
 	logger := zap.New()
 	logf.SetLogger(logger)